@@ -126,25 +126,22 @@ func main() {
 	// var insertId int
 	// firstnames := []string{"Vahag","Sergey","Bagrat","Mery"}
 	// lastnames := []string{"Zargaryan","Galstyan","Sargsyan","Voskanyan"}
-	// query := &bytes.Buffer{}
+	// stmt, err := client.Prepare([]byte(`
+	// 	INSERT INTO testtable (firstname, lastname, amount, birthday)
+	// 	VALUES (?, ?, ?, ?);
+	// `))
+	// exitIfErr(errors.Wrap(err, "prepare failed"))
+	// defer stmt.Close()
 	// // setInterval(time.Second, func() {
 	// // 	fmt.Println("[interval]", insertId)
 	// // })
-	// for i := 0; i < 10; i++ {
-	// 	query.Reset()
-	// 	query.WriteString("INSERT INTO testtable (firstname, lastname, amount, birthday) VALUES")
-	// 	for i := 0; i < 10; i++ {
-	// 		query.WriteString(fmt.Sprintf(
-	// 			"\n(%q,%q,%f,%d),",
-	// 			firstnames[rand.Intn(len(firstnames))],
-	// 			lastnames[rand.Intn(len(lastnames))],
-	// 			100 * rand.Float64(),
-	// 			rand.Intn(int(60 * 60 * 24 * 30)) + int(time.Now().Unix()),
-	// 		))
-	// 	}
-	// 	query.Truncate(query.Len() - 1)
-	// 	query.WriteByte(';')
-	// 	rows, err = client.Query(query.Bytes())
+	// for i := 0; i < 100; i++ {
+	// 	rows, err = stmt.Exec(
+	// 		firstnames[rand.Intn(len(firstnames))],
+	// 		lastnames[rand.Intn(len(lastnames))],
+	// 		100 * rand.Float64(),
+	// 		rand.Intn(int(60 * 60 * 24 * 30)) + int(time.Now().Unix()),
+	// 	)
 	// 	exitIfErr(errors.Wrap(err, "query failed"))
 	// 	for rows.Next() {
 	// 		rows.Scan(&insertId)
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"encoding/binary"
+	"encoding/json"
+
+	"go-dbms/client/types"
+
+	"github.com/pkg/errors"
+)
+
+// commands of prepared statements protocol, must match server's ones
+const (
+	cmdPrepare byte = iota + 1
+	cmdExecute
+	cmdClose
+)
+
+// Stmt is query prepared on server, which can be executed many times
+// with different parameters.
+type Stmt struct {
+	c         *Client
+	handle    uint32
+	NumParams int
+}
+
+// Prepare sends query with '?' or '$<number>' placeholders to server.
+func (c *Client) Prepare(q []byte) (*Stmt, error) {
+	rows, err := c.Query(append([]byte{cmdPrepare}, q...))
+	if err != nil {
+		return nil, err
+	}
+
+	st := &Stmt{c: c}
+	if !rows.Next() {
+		return nil, errors.New("empty prepare response")
+	} else if err := rows.Scan(&st.handle, &st.NumParams); err != nil {
+		return nil, errors.Wrap(err, "prepare failed")
+	}
+
+	for rows.Next() {  }
+	return st, nil
+}
+
+// Exec executes prepared query, params are bound to placeholders in order
+// of their numbers. Params must be numbers, strings or nil.
+func (st *Stmt) Exec(params ...any) (*types.Rows, error) {
+	if params == nil {
+		params = []any{}
+	}
+
+	data, err := json.Marshal(params)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal params")
+	}
+
+	return st.c.Query(append(st.command(cmdExecute), data...))
+}
+
+// Close removes prepared query from server.
+func (st *Stmt) Close() error {
+	rows, err := st.c.Query(st.command(cmdClose))
+	if err != nil {
+		return err
+	}
+
+	for rows.Next() {  }
+	return nil
+}
+
+func (st *Stmt) command(cmd byte) []byte {
+	return binary.BigEndian.AppendUint32([]byte{cmd}, st.handle)
+}
@@ -2,20 +2,33 @@ package column
 
 import (
 	"encoding/json"
+	"fmt"
+	"reflect"
 
 	"go-dbms/pkg/types"
+	"go-dbms/util/helpers"
+
+	"github.com/pkg/errors"
 )
 
 type Column struct {
-	Name string             `json:"name"`
-	Typ  types.TypeCode     `json:"type"`
-	Meta types.DataTypeMeta `json:"meta"`
+	// ID identifies column between versions of table schema,
+	// so column keeps its values when it's renamed
+	ID       uint16             `json:"id,omitempty"`
+	Name     string             `json:"name"`
+	Typ      types.TypeCode     `json:"type"`
+	Meta     types.DataTypeMeta `json:"meta"`
+	Nullable bool               `json:"nullable,omitempty"`
+	Default  types.DataType     `json:"default,omitempty"`
 }
 
 type column struct {
-	Name string          `json:"name"`
-	Typ  types.TypeCode  `json:"type"`
-	Meta json.RawMessage `json:"meta"`
+	ID       uint16          `json:"id,omitempty"`
+	Name     string          `json:"name"`
+	Typ      types.TypeCode  `json:"type"`
+	Meta     json.RawMessage `json:"meta"`
+	Nullable bool            `json:"nullable,omitempty"`
+	Default  any             `json:"default,omitempty"`
 }
 
 func New(name string, meta types.DataTypeMeta) *Column {
@@ -26,14 +39,68 @@ func New(name string, meta types.DataTypeMeta) *Column {
 	}
 }
 
+// DefaultValue returns value of column in rows, which don't have it.
+func (c *Column) DefaultValue() types.DataType {
+	if c.Default != nil {
+		return c.Default.Copy()
+	} else if c.Nullable {
+		return nil
+	}
+	return c.Meta.Default()
+}
+
+// Cast converts val to type of column.
+func (c *Column) Cast(val types.DataType) (res types.DataType, err error) {
+	defer helpers.RecoverOnError(&err)()
+
+	if res, err = val.Cast(c.Meta); err != nil {
+		return nil, err
+	} else if !reflect.DeepEqual(res.MetaCopy(), c.Meta) {
+		// some casts keep size of source type, e.g. VARCHAR keeps its capacity
+		res = types.Type(c.Meta).Set(res.Value())
+	}
+	return res, nil
+}
+
+// SetDefault sets default value of column casted to column type,
+// NULL is allowed only for nullable column.
+func (c *Column) SetDefault(val types.DataType) error {
+	if val == nil {
+		if !c.Nullable {
+			return fmt.Errorf("default of column can't be null: '%s'", c.Name)
+		}
+		c.Default = nil
+		return nil
+	}
+
+	def, err := c.Cast(val)
+	if err != nil {
+		return errors.Wrapf(err, "failed to cast default '%v' to type '%v'", val.Value(), c.Typ)
+	}
+	c.Default = def
+	return nil
+}
+
 func (c *Column) UnmarshalJSON(data []byte) error {
 	col := &column{}
 	if err := json.Unmarshal(data, col); err != nil {
 		return err
 	}
 
+	c.ID = col.ID
 	c.Name = col.Name
 	c.Typ = col.Typ
+	c.Nullable = col.Nullable
 	c.Meta = types.Meta(col.Typ)
-	return json.Unmarshal(col.Meta, c.Meta)
+	if err := json.Unmarshal(col.Meta, c.Meta); err != nil {
+		return err
+	}
+
+	if col.Default != nil {
+		var err error
+		if c.Default, err = c.Cast(types.ParseJSONValue(col.Default)); err != nil {
+			return err
+		}
+	}
+	return nil
 }
@@ -48,7 +48,7 @@ func Open(fileName string, opts *Options) (*DataFile, error) {
 		file:    pagerFile,
 		mu:      &sync.RWMutex{},
 		heap:    heap,
-		columns: opts.Columns,
+		schema:  newSchema(opts.Version, opts.Columns, opts.Schemas),
 	}
 
 	df.cache = cache.NewCache[*record](10000, df.newEmptyRecord)
@@ -72,7 +72,7 @@ type DataFile struct {
 	heap    *allocator.Allocator
 	cache   *cache.Cache[*record] // records cache to avoid IO
 	meta    *metadata             // metadata about df structure
-	columns []*column.Column      // columns list of data
+	schema  *schema               // columns of records
 }
 
 // Get fetches the record from the given pointer. Returns error if record not found.
@@ -81,9 +81,12 @@ func (df *DataFile) Get(ptr allocator.Pointable) []types.DataType {
 	defer df.mu.RUnlock()
 
 	r := df.fetchN(ptr).Get()
-	dataCopy := make([]types.DataType, len(r.data))
-	for i, dt := range r.data {
-		dataCopy[i] = dt.Copy()
+	data := df.schema.upgrade(r.version, r.data)
+	dataCopy := make([]types.DataType, len(data))
+	for i, dt := range data {
+		if dt != nil {
+			dataCopy[i] = dt.Copy()
+		}
 	}
 	return dataCopy
 }
@@ -94,9 +97,13 @@ func (df *DataFile) GetMap(ptr allocator.Pointable) types.DataRow {
 	defer df.mu.RUnlock()
 
 	r := df.fetchN(ptr).Get()
-	dataCopy := make(types.DataRow, len(r.data))
-	for i, data := range r.data {
-		dataCopy[df.columns[i].Name] = data.Copy()
+	dataCopy := make(types.DataRow, len(df.schema.columns))
+	for i, data := range df.schema.upgrade(r.version, r.data) {
+		if data == nil {
+			dataCopy[df.schema.columns[i].Name] = nil
+		} else {
+			dataCopy[df.schema.columns[i].Name] = data.Copy()
+		}
 	}
 	return dataCopy
 }
@@ -113,13 +120,13 @@ func (df *DataFile) Insert(val []types.DataType) (allocator.Pointable, error) {
 }
 
 func (df *DataFile) InsertMem(val []types.DataType) (allocator.Pointable, error) {
-	if len(val) != len(df.columns) {
-		return nil, customerrors.ErrKeyTooLarge
-	}
-
 	df.mu.Lock()
 	defer df.mu.Unlock()
 
+	if len(val) != len(df.schema.columns) {
+		return nil, customerrors.ErrKeyTooLarge
+	}
+
 	ptr := df.insert(val)
 	df.meta.dirty = true
 	df.meta.count++
@@ -163,7 +170,10 @@ func (df *DataFile) Scan(scanFn func(ptr allocator.Pointable, row []types.DataTy
 	return df.heap.Scan(df.metaPtr, func(ptr allocator.Pointable) (bool, error) {
 		if ptr.IsFree() {
 			return false, nil
-		} else if stop, err := scanFn(ptr, df.fetchR(ptr).Get().data); err != nil {
+		}
+
+		r := df.fetchN(ptr).Get()
+		if stop, err := scanFn(ptr, df.schema.upgrade(r.version, r.data)); err != nil {
 			return true, err
 		} else if stop {
 			return true, nil
@@ -172,6 +182,78 @@ func (df *DataFile) Scan(scanFn func(ptr allocator.Pointable, row []types.DataTy
 	})
 }
 
+// Alter changes columns of records to columns of version. Records written
+// before are not changed, they are upgraded to new columns on read.
+func (df *DataFile) Alter(version uint16, columns []*column.Column) error {
+	df.mu.Lock()
+	defer df.mu.Unlock()
+
+	if df.schema.legacy {
+		return errors.New("records of legacy data file must be migrated before alteration")
+	} else if version <= df.schema.version {
+		return fmt.Errorf("version %d must be greater than current %d", version, df.schema.version)
+	}
+
+	// schema is changed in place, because records loaded by cache
+	// get schema of data file, which may be copied after open
+	*df.schema = *df.schema.alter(version, columns)
+	return nil
+}
+
+// Versioned checks if records store version of schema, so schema can be altered.
+func (df *DataFile) Versioned() bool {
+	return !df.schema.legacy
+}
+
+// Migrate rewrites records of legacy data file with version of schema, so schema
+// can be altered. Records, which don't fit their place, are moved, so pointers
+// to records must be rebuilt after that.
+func (df *DataFile) Migrate() error {
+	df.mu.Lock()
+	defer df.mu.Unlock()
+
+	if !df.schema.legacy {
+		return nil
+	} else if err := df.writeAll(); err != nil {
+		return err
+	}
+
+	ptrs := []allocator.Pointable{}
+	err := df.heap.Scan(df.metaPtr, func(ptr allocator.Pointable) (bool, error) {
+		if !ptr.IsFree() {
+			ptrs = append(ptrs, ptr)
+		}
+		return false, nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to scan legacy records")
+	}
+
+	// records are read by copy of legacy schema, written ones have version
+	legacy := *df.schema
+	df.schema.legacy = false
+	df.cache = cache.NewCache[*record](10000, df.newEmptyRecord)
+	for _, ptr := range ptrs {
+		r := &record{schema: &legacy}
+		if err := ptr.Get(r); err != nil {
+			return errors.Wrap(err, "failed to read legacy record")
+		}
+
+		r = df.newRecord(r.data)
+		if r.Size() > ptr.Size() {
+			df.heap.Free(ptr)
+			ptr = df.heap.Alloc(r.Size())
+		}
+		if err := ptr.Set(r); err != nil {
+			return errors.Wrap(err, "failed to write migrated record")
+		}
+	}
+
+	df.meta.version = version
+	df.meta.dirty = true
+	return df.writeMeta()
+}
+
 // PrepareSpace allocates size bytes on underlying file.
 // This is usefull if big amount of data is going to be inserted.
 // It's increases performance of insertion.
@@ -197,6 +279,19 @@ func (df *DataFile) Close() {
 	df.heap = nil
 }
 
+// Remove closes data file without flushing and removes it from disk.
+func (df *DataFile) Remove() {
+	df.mu.Lock()
+	defer df.mu.Unlock()
+
+	if df.heap == nil {
+		return
+	}
+
+	df.heap.Remove()
+	df.heap = nil
+}
+
 // Pointer returns ptr with zero value attached to underlying pager
 func (df *DataFile) Pointer() allocator.Pointable {
 	return df.heap.Nil()
@@ -240,16 +335,18 @@ func (df *DataFile) free(ptr allocator.Pointable) {
 func (df *DataFile) newRecord(data []types.DataType) *record {
 	return &record{
 		dirty:   true,
+		version: df.schema.version,
 		data:    data,
-		columns: df.columns,
+		schema:  df.schema,
 	}
 }
 
 func (df *DataFile) newEmptyRecord() *record {
 	return &record{
 		dirty:   true,
+		version: df.schema.version,
 		data:    make([]types.DataType, 0),
-		columns: df.columns,
+		schema:  df.schema,
 	}
 }
 
@@ -304,9 +401,10 @@ func (df *DataFile) open(opts *Options) error {
 	}
 
 	// verify metadata
-	if df.meta.version != version {
+	if df.meta.version != version && df.meta.version != legacyVersion {
 		return fmt.Errorf("incompatible version %#x (expected: %#x)", df.meta.version, version)
 	}
+	df.schema.legacy = df.meta.version == legacyVersion
 
 	return nil
 }
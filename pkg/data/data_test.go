@@ -0,0 +1,100 @@
+package data
+
+import (
+	"path/filepath"
+	"testing"
+
+	"go-dbms/pkg/column"
+	"go-dbms/pkg/types"
+
+	"github.com/stretchr/testify/require"
+	allocator "github.com/vahagz/disk-allocator/heap"
+)
+
+func intColumn(id uint16, name string) *column.Column {
+	col := column.New(name, types.Meta(types.TYPE_INTEGER, true, 4, false))
+	col.ID = id
+	return col
+}
+
+func intOf(v int) types.DataType {
+	return types.Type(types.Meta(types.TYPE_INTEGER, true, 4, false)).Set(int32(v))
+}
+
+func values(t *testing.T, df *DataFile) [][]any {
+	rows := [][]any{}
+	require.NoError(t, df.Scan(func(ptr allocator.Pointable, row []types.DataType) (bool, error) {
+		vals := make([]any, len(row))
+		for i, v := range row {
+			vals[i] = v.Value()
+		}
+		rows = append(rows, vals)
+		return false, nil
+	}))
+	return rows
+}
+
+func TestMigrateLegacy(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "data")
+	opts := DefaultOptions
+	opts.Columns = []*column.Column{intColumn(1, "id")}
+
+	// records of legacy data file don't have version
+	df, err := Open(file, &opts)
+	require.NoError(t, err)
+	df.meta.version = legacyVersion
+	df.schema.legacy = true
+	for i := range 100 {
+		_, err := df.Insert([]types.DataType{intOf(i)})
+		require.NoError(t, err)
+	}
+	df.Close()
+
+	df, err = Open(file, &opts)
+	require.NoError(t, err)
+	require.False(t, df.Versioned())
+	require.Error(t, df.Alter(1, opts.Columns))
+
+	require.NoError(t, df.Migrate())
+	require.True(t, df.Versioned())
+	require.Len(t, values(t, df), 100)
+
+	added := intColumn(2, "v")
+	require.NoError(t, added.SetDefault(intOf(7)))
+	columns := []*column.Column{opts.Columns[0], added}
+	require.NoError(t, df.Alter(1, columns))
+	df.Close()
+
+	// migrated file is opened with versioned records
+	opts.Version = 1
+	opts.Columns = columns
+	opts.Schemas = []*Schema{{Version: 0, Columns: []*column.Column{intColumn(1, "id")}}}
+	df, err = Open(file, &opts)
+	require.NoError(t, err)
+	defer df.Close()
+	require.True(t, df.Versioned())
+
+	// records may be moved by migration
+	expected := make([][]any, 100)
+	for i := range expected {
+		expected[i] = []any{int32(i), int32(7)}
+	}
+	require.ElementsMatch(t, expected, values(t, df))
+}
+
+func TestUpgradeDefaultOfVersion(t *testing.T) {
+	id := intColumn(1, "id")
+	v1 := intColumn(2, "v")
+	require.NoError(t, v1.SetDefault(intOf(7)))
+	v2 := intColumn(2, "v")
+	require.NoError(t, v2.SetDefault(intOf(9)))
+
+	// v is added with default 7 at version 1, default is changed to 9 at version 2
+	s := newSchema(2, []*column.Column{id, v2}, []*Schema{
+		{Version: 0, Columns: []*column.Column{id}},
+		{Version: 1, Columns: []*column.Column{id, v1}},
+	})
+
+	row := s.upgrade(0, []types.DataType{intOf(1)})
+	require.Equal(t, int32(7), row[1].Value())
+}
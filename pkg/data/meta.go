@@ -2,8 +2,12 @@ package data
 
 const (
 	magic        = 0xD0D
-	version      = uint8(0x1)
+	version      = uint8(0x2)
 	metadataSize = 14
+
+	// records of data files of legacy version don't have schema
+	// version, schema of such data files can't be changed
+	legacyVersion = uint8(0x1)
 )
 
 // metadata represents the metadata for the data file stored in a file.
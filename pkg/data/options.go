@@ -20,4 +20,9 @@ type Options struct {
 	// list of columns to distinguish data in records, this is general
 	// for all records, and is stored in metadata of table
 	Columns []*column.Column
+
+	// Version of Columns and previous schemas, records
+	// written with them are upgraded on read
+	Version uint16
+	Schemas []*Schema
 }
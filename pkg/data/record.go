@@ -5,14 +5,29 @@ import (
 	"go-dbms/pkg/types"
 )
 
+// size of schema version stored at start of record
+const versionSize = 2
+
 // record represents a data row in the Data file.
 type record struct {
 	// configs for read/write
 	dirty bool
 
 	// record data
+	version uint16
 	data    []types.DataType
-	columns []*column.Column // list of columns
+	schema  *schema // schema of data file, record is decoded by columns of its version
+}
+
+func (r *record) columns() []*column.Column {
+	return r.schema.columnsOf(r.version)
+}
+
+func (r *record) headerSize() uint32 {
+	if r.schema.legacy {
+		return 0
+	}
+	return versionSize
 }
 
 func (r *record) IsDirty() bool {
@@ -28,9 +43,13 @@ func (r *record) IsNil() bool {
 }
 
 func (r *record) Size() uint32 {
-	var sz uint32 = 0
+	var sz uint32 = r.headerSize() + r.bitmapSize()
 
 	for i := 0; i < len(r.data); i++ {
+		if r.data[i] == nil {
+			continue
+		}
+
 		// 1 for the type code size
 		sz += 1 + uint32(r.data[i].Size())
 
@@ -44,10 +63,19 @@ func (r *record) Size() uint32 {
 
 func (r *record) MarshalBinary() ([]byte, error) {
 	buf := make([]byte, r.Size())
-	offset := 0
+	header := int(r.headerSize())
+	offset := header + int(r.bitmapSize())
+	if header != 0 {
+		bin.PutUint16(buf[0:versionSize], r.version)
+	}
 
 	for i := 0; i < len(r.data); i++ {
 		data := r.data[i]
+		if data == nil {
+			buf[header+i/8] |= 1 << (i % 8)
+			continue
+		}
+
 		size := data.Size()
 		if !data.IsFixedSize() {
 			bin.PutUint16(buf[offset:offset+2], uint16(size))
@@ -63,10 +91,22 @@ func (r *record) MarshalBinary() ([]byte, error) {
 }
 
 func (r *record) UnmarshalBinary(d []byte) error {
-	offset := 0
-	r.data = make([]types.DataType, len(r.columns))
+	r.version = r.schema.version
+	if !r.schema.legacy {
+		r.version = bin.Uint16(d[0:versionSize])
+		d = d[versionSize:]
+	}
+
+	columns := r.columns()
+	bitmap := d[:r.bitmapSize()]
+	offset := len(bitmap)
+	r.data = make([]types.DataType, len(columns))
+
+	for i, column := range columns {
+		if len(bitmap) != 0 && bitmap[i/8] & (1 << (i % 8)) != 0 {
+			continue // NULL value
+		}
 
-	for i, column := range r.columns {
 		size := 0
 		v := types.Type(column.Meta)
 
@@ -84,3 +124,15 @@ func (r *record) UnmarshalBinary(d []byte) error {
 
 	return nil
 }
+
+// bitmapSize returns size of NULL values bitmap. Bitmap is stored
+// only if record has nullable columns.
+func (r *record) bitmapSize() uint32 {
+	columns := r.columns()
+	for _, col := range columns {
+		if col.Nullable {
+			return uint32(len(columns) + 7) / 8
+		}
+	}
+	return 0
+}
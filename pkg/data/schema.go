@@ -0,0 +1,154 @@
+package data
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+
+	"go-dbms/pkg/column"
+	"go-dbms/pkg/types"
+	"go-dbms/util/helpers"
+)
+
+// Schema is list of columns of records written at version.
+type Schema struct {
+	Version uint16           `json:"version"`
+	Columns []*column.Column `json:"columns"`
+}
+
+// schema is layout of records in data file. Records written before schema
+// changes keep their version and are upgraded to current columns on read.
+type schema struct {
+	// records of legacy data files don't have version
+	legacy  bool
+	version uint16
+	columns []*column.Column
+
+	previous map[uint16][]*column.Column
+	upgrades map[uint16][]upgradeStep
+}
+
+// upgradeStep builds value of column from value src of previous version. If
+// column didn't exist, src is -1 and default def had, when column was added, is
+// used. Otherwise value is casted to types of column in following versions.
+type upgradeStep struct {
+	src   int
+	def   *column.Column
+	casts []*column.Column
+}
+
+func newSchema(version uint16, columns []*column.Column, previous []*Schema) *schema {
+	s := &schema{
+		version:  version,
+		columns:  columns,
+		previous: make(map[uint16][]*column.Column, len(previous)),
+	}
+	for _, p := range previous {
+		s.previous[p.Version] = p.Columns
+	}
+	s.buildUpgrades()
+	return s
+}
+
+// buildUpgrades builds steps of upgrade of records of previous versions.
+func (s *schema) buildUpgrades() {
+	versions := make([]uint16, 0, len(s.previous))
+	for v := range s.previous {
+		versions = append(versions, v)
+	}
+	slices.Sort(versions)
+
+	s.upgrades = make(map[uint16][]upgradeStep, len(versions))
+	for i, v := range versions {
+		// columns of following versions, from oldest to current
+		next := make([][]*column.Column, 0, len(versions)-i)
+		for _, nv := range versions[i+1:] {
+			next = append(next, s.previous[nv])
+		}
+		next = append(next, s.columns)
+
+		steps := make([]upgradeStep, len(s.columns))
+		for j, col := range s.columns {
+			st := &steps[j]
+			if st.src = slices.IndexFunc(s.previous[v], func(c *column.Column) bool { return c.ID == col.ID }); st.src < 0 {
+				for _, cols := range next {
+					if st.def = columnByID(cols, col.ID); st.def != nil {
+						break
+					}
+				}
+				continue
+			}
+
+			prev := s.previous[v][st.src]
+			for _, cols := range next {
+				if cur := columnByID(cols, col.ID); cur.Typ != prev.Typ || !reflect.DeepEqual(cur.Meta, prev.Meta) {
+					st.casts = append(st.casts, cur)
+					prev = cur
+				}
+			}
+		}
+		s.upgrades[v] = steps
+	}
+}
+
+func columnByID(columns []*column.Column, id uint16) *column.Column {
+	for _, col := range columns {
+		if col.ID == id {
+			return col
+		}
+	}
+	return nil
+}
+
+// alter returns schema of next version with columns.
+func (s *schema) alter(version uint16, columns []*column.Column) *schema {
+	next := &schema{
+		version:  version,
+		columns:  columns,
+		previous: maps.Clone(s.previous),
+	}
+	next.previous[s.version] = s.columns
+	next.buildUpgrades()
+	return next
+}
+
+// columnsOf returns columns of records of version.
+func (s *schema) columnsOf(version uint16) []*column.Column {
+	if version == s.version {
+		return s.columns
+	} else if cols, ok := s.previous[version]; ok {
+		return cols
+	}
+	panic(fmt.Errorf("unknown record version: %d", version))
+}
+
+// upgrade converts values of record of version to current columns. Alteration
+// is rejected if values can't be casted, so casts of upgrade don't fail.
+func (s *schema) upgrade(version uint16, data []types.DataType) []types.DataType {
+	if version == s.version {
+		return data
+	}
+
+	steps, ok := s.upgrades[version]
+	if !ok {
+		panic(fmt.Errorf("unknown record version: %d", version))
+	}
+
+	row := make([]types.DataType, len(s.columns))
+	for i, st := range steps {
+		if st.src < 0 {
+			row[i] = st.def.DefaultValue()
+			continue
+		}
+
+		val := data[st.src]
+		for _, col := range st.casts {
+			if val != nil {
+				val = helpers.MustVal(col.Cast(val))
+			}
+		}
+		row[i] = val
+	}
+	return row
+}
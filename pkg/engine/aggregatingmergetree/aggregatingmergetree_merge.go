@@ -54,12 +54,11 @@ func (t *AggregatingMergeTree) MergeTreeFn(dst table.ITable, src table.ITable) {
 				updRow[col] = ag.Value()
 			}
 
-			mainRes, err := dst.UpdateByIndex(t.PrimaryKey(), filter, nil, nil, updRow)
-			if err != nil {
+			mainRes, eg := dst.UpdateByIndex(t.PrimaryKey(), filter, nil, nil, table.SetValues(updRow))
+			mainRes.PopAll()
+			if err := eg.Wait(); err != nil {
 				panic(errors.Wrap(err, "failed to update data from main table on merge process"))
 			}
-
-			mainRes.PopAll()
 		} else {
 			in := stream.New[types.DataRow](1)
 			in.Push(row)
@@ -1,6 +1,8 @@
 package aggregatingmergetree
 
 import (
+	"maps"
+
 	"go-dbms/pkg/table"
 	"go-dbms/services/parser/query/dml/aggregator"
 )
@@ -18,3 +20,33 @@ type IMetadata interface {
 
 func (m *Metadata) GetAggregations() map[string]aggregator.AggregatorType { return m.Aggregations }
 func (m *Metadata) SetAggregations(v map[string]aggregator.AggregatorType) { m.Aggregations = v }
+
+// Alter changes columns and aggregations of columns.
+func (m *Metadata) Alter(a *table.Alteration) error {
+	if err := m.Metadata.Alter(a); err != nil {
+		return err
+	}
+
+	aggrs := maps.Clone(m.Aggregations)
+	if aggrs == nil {
+		aggrs = map[string]aggregator.AggregatorType{}
+	}
+
+	// aggregation is part of column type, so modified column has only new one
+	switch a.Action {
+		case table.ADD_COLUMN, table.MODIFY_COLUMN:
+			delete(aggrs, a.Name)
+			if a.Aggregation != "" {
+				aggrs[a.Name] = aggregator.AggregatorType(a.Aggregation)
+			}
+		case table.DROP_COLUMN:
+			delete(aggrs, a.Name)
+		case table.RENAME_COLUMN:
+			if aggr, ok := aggrs[a.Name]; ok {
+				delete(aggrs, a.Name)
+				aggrs[a.NewName] = aggr
+			}
+	}
+	m.Aggregations = aggrs
+	return nil
+}
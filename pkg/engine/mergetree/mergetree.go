@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"sync"
+	"sync/atomic"
 
 	"go-dbms/pkg/table"
 	"go-dbms/pkg/types"
@@ -20,6 +21,8 @@ const partsPath = "./parts"
 type IMergeTree interface {
 	table.ITable
 	Merge()
+	Merging() (MergeStatus, bool)
+	PartsCount() int
 }
 
 func Open(opts *table.Options) (table.ITable, error) {
@@ -32,6 +35,7 @@ func Open(opts *table.Options) (table.ITable, error) {
 		Table:     t.(*table.Table),
 		Parts:     map[string]*table.Table{},
 		mergeLock: &sync.Mutex{},
+		merging:   &atomic.Pointer[MergeStatus]{},
 	}
 	tree.MergeFn = tree.MergeTreeFn
 
@@ -46,6 +50,9 @@ type MergeTree struct {
 	Parts     map[string]*table.Table
 	MergeFn   func(main, part table.ITable)
 	mergeLock *sync.Mutex
+
+	// status of merge in progress, nil if table isn't merged now
+	merging *atomic.Pointer[MergeStatus]
 }
 
 func (t *MergeTree) Init(opts *table.Options) error {
@@ -109,8 +116,98 @@ func (t *MergeTree) PartsIterator(yield func(name string, part *table.Table) boo
 	}
 }
 
-func (t *MergeTree) Close() {
+// PartsCount returns count of parts including master table.
+func (t *MergeTree) PartsCount() int {
+	return len(t.Parts) + 1
+}
+
+// Drop waits for merge in progress and removes master table with parts.
+func (t *MergeTree) Drop() {
+	t.mergeLock.Lock()
+	defer t.mergeLock.Unlock()
+
+	parts := t.Parts
+	t.Parts = map[string]*table.Table{}
+	for _, p := range parts {
+		p.Drop()
+	}
+	t.Table.Drop()
+}
+
+// DropIndex removes index from master table and parts having it.
+func (t *MergeTree) DropIndex(name string) error {
+	t.mergeLock.Lock()
+	defer t.mergeLock.Unlock()
+
+	if err := t.Table.DropIndex(name); err != nil {
+		return err
+	}
+	for _, p := range t.Parts {
+		if p.HasIndex(name) {
+			if err := p.DropIndex(name); err != nil {
+				return errors.Wrap(err, "failed to drop index of part")
+			}
+		}
+	}
+	return nil
+}
+
+// Alter changes columns of master table and parts, which share metadata.
+// All of them are locked, so no rows are written by old columns meanwhile.
+func (t *MergeTree) Alter(a *table.Alteration) error {
+	t.mergeLock.Lock()
+	defer t.mergeLock.Unlock()
+
+	tables := []*table.Table{t.Table}
 	for _, p := range t.Parts {
+		tables = append(tables, p)
+	}
+
+	for _, p := range tables {
+		unlock, err := p.Lock()
+		if err != nil {
+			return err
+		}
+		defer unlock()
+
+		if err := p.CheckAlter(a); err != nil {
+			return err
+		}
+	}
+
+	if err := t.Table.AlterMeta(a); err != nil {
+		return err
+	}
+	for _, p := range tables {
+		if err := p.AlterData(); err != nil {
+			return errors.Wrap(err, "failed to alter data")
+		}
+	}
+	return nil
+}
+
+// Truncate waits for merge in progress, drops parts and truncates master table.
+func (t *MergeTree) Truncate() error {
+	t.mergeLock.Lock()
+	defer t.mergeLock.Unlock()
+
+	parts := t.Parts
+	t.Parts = map[string]*table.Table{}
+	for _, p := range parts {
+		p.Drop()
+	}
+	return t.Table.Truncate()
+}
+
+// Close waits for merge in progress and closes master table with parts.
+// Parts are forgotten, so closed table isn't merged anymore.
+func (t *MergeTree) Close() {
+	t.mergeLock.Lock()
+	defer t.mergeLock.Unlock()
+
+	parts := t.Parts
+	t.Parts = map[string]*table.Table{}
+	for _, p := range parts {
 		p.Close()
 	}
 	t.Table.Close()
@@ -125,9 +222,11 @@ func Pipe(
 	src map[string]stream.ReaderContinue[types.DataRow],
 	dst stream.WriterContinue[types.DataRow],
 	indexCols []string,
+	reverse bool,
 ) {
 	hp := &sorted.Heap[string]{
-		Keys: indexCols,
+		Keys:    indexCols,
+		Reverse: reverse,
 	}
 
 	for name, partStr := range src {
@@ -9,16 +9,41 @@ import (
 	"go-dbms/util/stream"
 
 	"github.com/google/uuid"
+	"github.com/pkg/errors"
 	"golang.org/x/sync/errgroup"
 )
 
+var ErrUpsert = errors.New("IGNORE, REPLACE and ON DUPLICATE KEY UPDATE are not supported by MergeTree")
+
 func (t *MergeTree) Insert(in stream.Reader[types.DataRow]) (stream.Reader[types.DataRow], *errgroup.Group) {
+	return t.newPart().Insert(in)
+}
+
+// Upsert inserts rows only if conflicts fail insert, other actions aren't supported,
+// since keys are unique only within part till parts are merged.
+func (t *MergeTree) Upsert(in stream.Reader[types.DataRow], oc *table.OnConflict) (stream.Reader[types.DataRow], *errgroup.Group) {
+	if oc.Action == table.CONFLICT_FAIL {
+		return t.Insert(in)
+	}
+
+	s := stream.New[types.DataRow](0)
+	s.Close()
+
+	eg := &errgroup.Group{}
+	eg.Go(func() error { return ErrUpsert })
+	return s, eg
+}
+
+// newPart opens part under merge lock, so columns
+// aren't altered till part is added to parts.
+func (t *MergeTree) newPart() *table.Table {
+	t.mergeLock.Lock()
+	defer t.mergeLock.Unlock()
+
 	name, opts := t.newPartOpts()
 	part := helpers.MustVal(table.Open(opts)).(*table.Table)
-	t.mergeLock.Lock()
 	t.Parts[name] = part
-	t.mergeLock.Unlock()
-	return part.Insert(in)
+	return part
 }
 
 func (t *MergeTree) newPartOpts() (name string, opts *table.Options) {
@@ -2,10 +2,20 @@ package mergetree
 
 import (
 	"fmt"
+	"time"
+
 	"go-dbms/pkg/table"
 	"go-dbms/util/helpers"
 )
 
+// MergeStatus is progress of merge in progress.
+type MergeStatus struct {
+	Started time.Time
+	Parts   int    // count of parts being merged
+	Merged  int    // count of parts already merged
+	Part    string // name of part being merged now
+}
+
 func (t *MergeTree) Merge() {
 	if !t.mergeLock.TryLock() {
 		return
@@ -18,14 +28,27 @@ func (t *MergeTree) Merge() {
 		fmt.Printf("[%s] starting merge\n", t.DataPath)
 		defer fmt.Printf("[%s] merge finished\n", t.DataPath)
 		defer t.mergeLock.Unlock()
+		defer t.merging.Store(nil)
 
+		// status is replaced for each part, so readers get consistent copy
+		started, parts, merged := time.Now(), len(t.Parts), 0
 		for name, part := range t.Parts {
+			t.merging.Store(&MergeStatus{Started: started, Parts: parts, Merged: merged, Part: name})
 			t.merge(part)
 			delete(t.Parts, name)
+			merged++
 		}
 	}()
 }
 
+// Merging returns status of merge in progress, false if table isn't merged now.
+func (t *MergeTree) Merging() (MergeStatus, bool) {
+	if status := t.merging.Load(); status != nil {
+		return *status, true
+	}
+	return MergeStatus{}, false
+}
+
 func (t *MergeTree) merge(part *table.Table) {
 	t.MergeFn(t.Table, part)
 	part.Drop()
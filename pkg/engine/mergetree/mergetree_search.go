@@ -2,14 +2,16 @@ package mergetree
 
 import (
 	"fmt"
+	"slices"
 	"sync"
 
 	"go-dbms/pkg/index"
 	"go-dbms/pkg/statement"
 	"go-dbms/pkg/table"
 	"go-dbms/pkg/types"
-	"go-dbms/util/helpers"
 	"go-dbms/util/stream"
+
+	"github.com/pkg/errors"
 )
 
 func (t *MergeTree) Find(filter *statement.WhereStatement) stream.Reader[index.Entry] {
@@ -39,25 +41,27 @@ func (t *MergeTree) ScanByIndex(
 	indexName string,
 	start, end *index.Filter,
 ) (stream.ReaderContinue[types.DataRow], error) {
-	if _, ok := t.Indexes[indexName]; !ok {
+	meta := t.IndexMeta(indexName)
+	if meta == nil {
 		return nil, fmt.Errorf("index not found => '%s'", indexName)
 	}
 
+	cols := meta.Columns
+	if indexName != t.PrimaryKey() {
+		cols = slices.Concat(cols, t.IndexMeta(t.PrimaryKey()).Columns)
+	}
+
+	sMap, err := t.partScans(func(part *table.Table) (stream.ReaderContinue[types.DataRow], error) {
+		return part.ScanByIndex(indexName, start, end)
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	s := stream.New[types.DataRow](len(t.Parts))
 	go func() {
 		defer s.Close()
-		sMap := make(map[string]stream.ReaderContinue[types.DataRow], len(t.Parts))
-
-		t.PartsIterator(func(name string, part *table.Table) bool {
-			sMap[name] = helpers.MustVal(part.ScanByIndex(indexName, start, end))
-			return true
-		})
-
-		cols := t.Indexes[indexName].Meta().Columns
-		if indexName != t.PrimaryKey() {
-			cols = append(cols, t.Indexes[t.PrimaryKey()].Meta().Columns...)
-		}
-		Pipe(sMap, s, cols)
+		Pipe(sMap, s, cols, false)
 	}()
 	return s, nil
 }
@@ -73,7 +77,7 @@ func (t *MergeTree) FullScan() stream.ReaderContinue[types.DataRow] {
 			return true
 		})
 
-		Pipe(sMap, s, []string{})
+		Pipe(sMap, s, []string{}, false)
 	}()
 	return s
 }
@@ -82,22 +86,51 @@ func (t *MergeTree) FullScanByIndex(
 	indexName string,
 	reverse bool,
 ) (stream.ReaderContinue[types.DataRow], error) {
-	if _, ok := t.Indexes[indexName]; !ok {
+	meta := t.IndexMeta(indexName)
+	if meta == nil {
 		return nil, fmt.Errorf("index not found => '%s'", indexName)
 	}
 
+	cols := slices.Concat(meta.Columns, t.IndexMeta(t.PrimaryKey()).Columns)
+	sMap, err := t.partScans(func(part *table.Table) (stream.ReaderContinue[types.DataRow], error) {
+		return part.FullScanByIndex(indexName, reverse)
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	s := stream.New[types.DataRow](len(t.Parts))
 	go func() {
 		defer s.Close()
-		sMap := make(map[string]stream.ReaderContinue[types.DataRow], len(t.Parts))
-
-		t.PartsIterator(func(name string, part *table.Table) bool {
-			sMap[name] = helpers.MustVal(part.FullScanByIndex(indexName, reverse))
-			return true
-		})
-
-		cols := append(t.Indexes[indexName].Meta().Columns, t.Indexes[t.PrimaryKey()].Meta().Columns...)
-		Pipe(sMap, s, cols)
+		Pipe(sMap, s, cols, reverse)
 	}()
 	return s, nil
 }
+
+// partScans opens scans of master table and parts before rows are read,
+// so indexes can't be dropped in between. Opened scans are stopped on error.
+// Part closed meanwhile is skipped, it was dropped after merge into master table.
+func (t *MergeTree) partScans(
+	scan func(part *table.Table) (stream.ReaderContinue[types.DataRow], error),
+) (map[string]stream.ReaderContinue[types.DataRow], error) {
+	sMap := make(map[string]stream.ReaderContinue[types.DataRow], len(t.Parts)+1)
+
+	var err error
+	t.PartsIterator(func(name string, part *table.Table) bool {
+		var s stream.ReaderContinue[types.DataRow]
+		if s, err = scan(part); err == nil {
+			sMap[name] = s
+		} else if name != "" && errors.Is(err, table.ErrClosed) {
+			err = nil
+		}
+		return err == nil
+	})
+
+	if err != nil {
+		for _, s := range sMap {
+			s.Continue(false)
+		}
+		return nil, err
+	}
+	return sMap, nil
+}
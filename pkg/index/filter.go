@@ -1,6 +1,8 @@
 package index
 
 import (
+	"slices"
+
 	"go-dbms/pkg/types"
 	"go-dbms/services/parser/query/dml/projection"
 	"go-dbms/util/helpers"
@@ -17,6 +19,30 @@ type Filter struct {
 	Conditions []FilterCondition
 }
 
+// points expands IN filter into equality filters, one for every
+// combination of list values.
+func (f *Filter) points() []*Filter {
+	points := []*Filter{{Operator: types.Equal}}
+	for _, cond := range f.Conditions {
+		values := []*projection.Projection{cond.Right}
+		if cond.Right.Type == projection.LIST {
+			values = cond.Right.Arguments
+		}
+
+		next := make([]*Filter, 0, len(points)*len(values))
+		for _, p := range points {
+			for _, v := range values {
+				next = append(next, &Filter{
+					Operator:   types.Equal,
+					Conditions: append(slices.Clone(p.Conditions), FilterCondition{Left: cond.Left, Right: v}),
+				})
+			}
+		}
+		points = next
+	}
+	return points
+}
+
 type operator struct {
 	cmpOption  map[int]struct{}
 	scanOption bptree.ScanOptions
@@ -37,12 +37,19 @@ func (i *Index) SetPK(pk *Index) {
 	i.primary = pk
 }
 
+// Reset replaces meta and columns of index, which are changed
+// without changing keys, e.g. when column is renamed.
+func (i *Index) Reset(meta *Meta, columns []*column.Column) {
+	i.meta = meta
+	i.columns = columns
+}
+
 func (i *Index) Meta() *Meta {
 	return i.meta
 }
 
 func (i *Index) CanInsert(values types.DataRow) bool {
-	return i.tree.CanInsert(i.key(values), i.primary.key(values))
+	return i.tree.CanInsert(i.key(values), i.suffix(values))
 }
 
 func (i *Index) Columns() []*column.Column {
@@ -73,16 +80,35 @@ func (i *Index) key(values types.DataRow) [][]byte {
 	key := make([][]byte, len(i.columns))
 
 	for i, col := range i.columns {
-		if col, ok := values[col.Name]; ok {
-			key[i] = col.Bytes()
-		} else {
-			key[i] = nil
+		val, ok := values[col.Name]
+		switch {
+			case !ok:          key[i] = nil
+			case col.Nullable: key[i] = nullableKey(val)
+			default:           key[i] = val.Bytes()
 		}
 	}
 
 	return key
 }
 
+// suffix returns primary key, which is added to keys of non unique index
+// to tell apart equal keys. Keys of unique index don't have suffix.
+func (i *Index) suffix(values types.DataRow) [][]byte {
+	if i.uniq {
+		return nil
+	}
+	return i.primary.key(values)
+}
+
+// nullableKey prefixes value bytes with marker byte, so NULL keys
+// are ordered before any other value.
+func nullableKey(val types.DataType) []byte {
+	if val == nil {
+		return []byte{0}
+	}
+	return append([]byte{1}, val.Bytes()...)
+}
+
 func (i *Index) removeAutoSetCols(k [][]byte, prefixCount, postfixCount int) [][]byte {
 	newKey := make([][]byte, 0, len(k) - postfixCount)
 	newKey = append(newKey, k[:prefixCount]...)
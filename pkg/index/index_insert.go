@@ -15,7 +15,7 @@ func (i *Index) Insert(dataPtr allocator.Pointable, values types.DataRow) error
 
 	_, err = i.tree.PutMem(
 		i.key(values),
-		i.primary.key(values),
+		i.suffix(values),
 		val,
 		bptree.PutOptions{Update: false},
 	)
@@ -1,9 +1,13 @@
 package index
 
 import (
+	"slices"
+
+	"go-dbms/pkg/column"
 	"go-dbms/pkg/statement"
 	"go-dbms/pkg/types"
 	"go-dbms/services/parser/query/dml/eval"
+	"go-dbms/util/helpers"
 
 	"github.com/pkg/errors"
 	allocator "github.com/vahagz/disk-allocator/heap"
@@ -21,6 +25,10 @@ func (i *Index) Scan(
 }
 
 func (i *Index) ScanFilter(start, end *Filter, scanFn func(ptr allocator.Pointable) (stop bool, err error)) error {
+	if start.Operator == types.In {
+		return i.scanPoints(start, scanFn)
+	}
+
 	opts := operatorMapping[start.Operator].scanOption
 	prefixColsCountStart := len(start.Conditions)
 	prefixColsCountEnd := 0
@@ -28,16 +36,10 @@ func (i *Index) ScanFilter(start, end *Filter, scanFn func(ptr allocator.Pointab
 	postfixColsCountEnd := 0
 	var endKey [][]byte
 
-	startVal := types.DataRow{}
-	for _, cond := range start.Conditions {
-		startVal[cond.Left.Alias] = eval.Eval(nil, cond.Right)
-	}
+	startVal := i.filterValues(start.Conditions)
 
 	if end != nil {
-		endVal := types.DataRow{}
-		for _, cond := range end.Conditions {
-			endVal[cond.Left.Alias] = eval.Eval(nil, cond.Right)
-		}
+		endVal := i.filterValues(end.Conditions)
 
 		endKey = i.key(endVal)
 		postfixColsCountEnd = len(endKey) - len(endVal)
@@ -49,6 +51,8 @@ func (i *Index) ScanFilter(start, end *Filter, scanFn func(ptr allocator.Pointab
 			postfixColsCountStart++
 			if (opts.Strict && opts.Reverse) || (!opts.Strict && !opts.Reverse) {
 				startVal[col.Name] = types.Type(col.Meta).Fill()
+			} else if col.Nullable {
+				startVal[col.Name] = nil // NULL is less than zero value
 			} else {
 				startVal[col.Name] = types.Type(col.Meta).Zero()
 			}
@@ -79,6 +83,9 @@ func (i *Index) ScanFilter(start, end *Filter, scanFn func(ptr allocator.Pointab
 		if shouldStop(kStart, start.Operator, searchingKey) || (endKey != nil && shouldStop(kEnd, end.Operator, endKey)) {
 			return true, nil
 		}
+		if i.isNullKey(k, prefixColsCountStart) {
+			return false, nil // NULL doesn't satisfy any condition
+		}
 
 		ptr := i.df.Pointer()
 		if err := ptr.UnmarshalBinary(v); err != nil {
@@ -88,6 +95,70 @@ func (i *Index) ScanFilter(start, end *Filter, scanFn func(ptr allocator.Pointab
 	})
 }
 
+// filterValues evaluates right sides of conditions and casts them to types
+// of index columns. Conditions are evaluated on every scan, so values of
+// query parameters can change between executions of prepared query.
+func (i *Index) filterValues(conds []FilterCondition) types.DataRow {
+	val := types.DataRow{}
+	for _, cond := range conds {
+		v := eval.Eval(nil, cond.Right)
+		if v != nil {
+			if j := slices.IndexFunc(i.columns, func(col *column.Column) bool {
+				return col.Name == cond.Left.Alias
+			}); j != -1 {
+				v = helpers.MustVal(v.Cast(i.columns[j].Meta))
+			}
+		}
+		val[cond.Left.Alias] = v
+	}
+	return val
+}
+
+// isNullKey checks if any of first n columns of key is NULL.
+func (i *Index) isNullKey(k [][]byte, n int) bool {
+	for j := 0; j < n && j < len(k); j++ {
+		if i.columns[j].Nullable && len(k[j]) == 1 && k[j][0] == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// scanPoints scans index with equality filter for every value of IN filter.
+// Points are scanned in index order, duplicates are skipped.
+func (i *Index) scanPoints(f *Filter, scanFn func(ptr allocator.Pointable) (stop bool, err error)) error {
+	type point struct {
+		filter *Filter
+		key    [][]byte
+	}
+
+	points := []point{}
+	for _, pf := range f.points() {
+		points = append(points, point{pf, i.key(i.filterValues(pf.Conditions))})
+	}
+
+	slices.SortFunc(points, func(a, b point) int {
+		return helpers.CompareMatrix(a.key, b.key)
+	})
+
+	stop := false
+	for j, p := range points {
+		if j > 0 && helpers.CompareMatrix(points[j-1].key, p.key) == 0 {
+			continue
+		}
+
+		err := i.ScanFilter(p.filter, nil, func(ptr allocator.Pointable) (bool, error) {
+			var err error
+			stop, err = scanFn(ptr)
+			return stop, err
+		})
+		if err != nil || stop {
+			return err
+		}
+	}
+	return nil
+}
+
 func (i *Index) ScanEntries(start *Filter, end *Filter, filter *statement.WhereStatement) []Entry {
 	entries := []Entry{}
 
@@ -104,3 +175,17 @@ func (i *Index) ScanEntries(start *Filter, end *Filter, filter *statement.WhereS
 
 	return entries
 }
+
+// Get returns pointer to row, which has key of values in unique index.
+func (i *Index) Get(values types.DataRow) (allocator.Pointable, bool, error) {
+	vals, err := i.tree.Get(i.key(values), nil)
+	if err != nil || len(vals) == 0 {
+		return nil, false, err
+	}
+
+	ptr := i.df.Pointer()
+	if err := ptr.UnmarshalBinary(vals[0]); err != nil {
+		return nil, false, err
+	}
+	return ptr, true, nil
+}
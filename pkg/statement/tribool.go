@@ -0,0 +1,41 @@
+package statement
+
+// tribool is a value of SQL three-valued logic.
+type tribool uint8
+
+const (
+	false_ tribool = iota
+	true_
+	unknown
+)
+
+func boolOf(b bool) tribool {
+	if b {
+		return true_
+	}
+	return false_
+}
+
+func (b tribool) not() tribool {
+	switch b {
+		case true_:  return false_
+		case false_: return true_
+		default:     return unknown
+	}
+}
+
+func (b tribool) and(o tribool) tribool {
+	switch {
+		case b == false_ || o == false_: return false_
+		case b == true_ && o == true_:   return true_
+		default:                         return unknown
+	}
+}
+
+func (b tribool) or(o tribool) tribool {
+	switch {
+		case b == true_ || o == true_:   return true_
+		case b == false_ && o == false_: return false_
+		default:                         return unknown
+	}
+}
@@ -3,6 +3,7 @@ package statement
 import (
 	"go-dbms/pkg/types"
 	"go-dbms/services/parser/query/dml/eval"
+	"go-dbms/services/parser/query/dml/projection"
 	"go-dbms/util/helpers"
 
 	"github.com/pkg/errors"
@@ -12,36 +13,118 @@ type WhereStatement struct {
 	And       []*WhereStatement `json:"and,omitempty"`
 	Or        []*WhereStatement `json:"or,omitempty"`
 	Statement *Statement        `json:"statement,omitempty"`
+	Not       bool              `json:"not,omitempty"`
 }
 
+// Compare checks if row satisfies statement. Comparisons with NULL
+// are evaluated to unknown according to SQL three-valued logic,
+// row satisfies statement only if it's evaluated to true.
 func (ws *WhereStatement) Compare(row types.DataRow) bool {
+	return ws.eval(row) == true_
+}
+
+func (ws *WhereStatement) eval(row types.DataRow) tribool {
+	if ws.Not {
+		return ws.evalUnnegated(row).not()
+	}
+	return ws.evalUnnegated(row)
+}
+
+func (ws *WhereStatement) evalUnnegated(row types.DataRow) tribool {
 	if ws.Statement != nil {
-		l := eval.Eval(row, ws.Statement.Left)
-		r := eval.Eval(row, ws.Statement.Right)
-		return l.CompareOp(ws.Statement.Op, helpers.MustVal(r.Cast(l.MetaCopy())))
+		return ws.Statement.eval(row)
 	}
 
 	if len(ws.And) != 0 {
+		res := true_
 		for _, ws := range ws.And {
-			if !ws.Compare(row) {
-				return false
+			res = res.and(ws.eval(row))
+			if res == false_ {
+				break
 			}
 		}
-		return true
+		return res
 	}
 
 	if len(ws.Or) != 0 {
+		res := false_
 		for _, ws := range ws.Or {
-			if ws.Compare(row) {
-				return true
+			res = res.or(ws.eval(row))
+			if res == true_ {
+				break
 			}
 		}
-		return false
+		return res
 	}
 
 	panic(errors.New("invalid where statement"))
 }
 
+// Operands returns left and right sides of all statements.
+func (ws *WhereStatement) Operands() []*projection.Projection {
+	ops := []*projection.Projection{}
+	if ws.Statement != nil {
+		ops = append(ops, ws.Statement.Left, ws.Statement.Right)
+	}
+	for _, w := range append(ws.And, ws.Or...) {
+		ops = append(ops, w.Operands()...)
+	}
+	return ops
+}
+
+func (s *Statement) eval(row types.DataRow) tribool {
+	l := eval.Eval(row, s.Left)
+	switch s.Op {
+		case types.IsNull:    return boolOf(l == nil)
+		case types.IsNotNull: return boolOf(l != nil)
+	}
+
+	if l == nil {
+		return unknown
+	}
+
+	switch s.Op {
+		case types.In:         return s.in(row, l)
+		case types.NotIn:      return s.in(row, l).not()
+		case types.Between:    return s.between(row, l)
+		case types.NotBetween: return s.between(row, l).not()
+	}
+	return compareOp(l, s.Op, eval.Eval(row, s.Right))
+}
+
+// in checks if val is equal to any value of statement's right side list.
+func (s *Statement) in(row types.DataRow, val types.DataType) tribool {
+	res := false_
+	for _, p := range s.Right.Arguments {
+		res = res.or(compareOp(val, types.Equal, eval.Eval(row, p)))
+		if res == true_ {
+			break
+		}
+	}
+	return res
+}
+
+// between checks if val is in range of statement's right side list bounds.
+func (s *Statement) between(row types.DataRow, val types.DataType) tribool {
+	low := compareOp(val, types.GreaterOrEqual, eval.Eval(row, s.Right.Arguments[0]))
+	high := compareOp(val, types.LessOrEqual, eval.Eval(row, s.Right.Arguments[1]))
+	return low.and(high)
+}
+
+func compareOp(l types.DataType, op types.Operator, r types.DataType) tribool {
+	if r == nil {
+		return unknown
+	}
+
+	meta := l.MetaCopy()
+	switch op {
+		case types.Like, types.NotLike, types.ILike, types.NotILike:
+			// pattern is matched as is, type of column could truncate it
+			meta = types.Meta(types.TYPE_STRING)
+	}
+	return boolOf(l.CompareOp(op, helpers.MustVal(r.Cast(meta))))
+}
+
 func WhereS(s *Statement) *WhereStatement {
 	return &WhereStatement{Statement: s}
 }
@@ -0,0 +1,60 @@
+package statement
+
+import (
+	"testing"
+
+	"go-dbms/pkg/types"
+	"go-dbms/services/parser/query/dml/projection"
+
+	"github.com/stretchr/testify/require"
+)
+
+func ident(name string) *projection.Projection {
+	return &projection.Projection{Name: name, Alias: name, Type: projection.IDENTIFIER}
+}
+
+func literal(val types.DataType) *projection.Projection {
+	return &projection.Projection{Type: projection.LITERAL, Literal: val}
+}
+
+func TestCompareNull(t *testing.T) {
+	one := types.Type(types.Meta(types.TYPE_INTEGER, true, 4, false)).Set(int32(1))
+	row := types.DataRow{"a": one, "b": nil}
+
+	aEq1 := &Statement{Left: ident("a"), Op: types.Equal, Right: literal(one)}
+	bEq1 := &Statement{Left: ident("b"), Op: types.Equal, Right: literal(one)}
+	bNull := &Statement{Left: ident("b"), Op: types.IsNull, Right: literal(nil)}
+
+	require.True(t, WhereS(aEq1).Compare(row))
+	require.False(t, WhereS(bEq1).Compare(row))
+	require.False(t, (&WhereStatement{Statement: bEq1, Not: true}).Compare(row))
+	require.True(t, WhereS(bNull).Compare(row))
+
+	require.False(t, And(aEq1, bEq1).Compare(row))
+	require.True(t, Or(aEq1, bEq1).Compare(row))
+	require.False(t, (&WhereStatement{Or: []*WhereStatement{WhereS(bEq1)}, Not: true}).Compare(row))
+
+	// unknown OR false is unknown, so negation is unknown too
+	aNe1 := &Statement{Left: ident("a"), Op: types.NotEqual, Right: literal(one)}
+	require.False(t, (&WhereStatement{Or: []*WhereStatement{WhereS(bEq1), WhereS(aNe1)}, Not: true}).Compare(row))
+
+	in := &Statement{Left: ident("a"), Op: types.NotIn, Right: &projection.Projection{
+		Type:      projection.LIST,
+		Arguments: []*projection.Projection{literal(nil)},
+	}}
+	require.False(t, WhereS(in).Compare(row))
+}
+
+func TestLikeLongPattern(t *testing.T) {
+	name := types.Type(types.Meta(types.TYPE_VARCHAR, 3)).Set("abc")
+	row := types.DataRow{"name": name}
+	pattern := func(val string) *projection.Projection {
+		return literal(types.Type(types.Meta(types.TYPE_STRING)).Set(val))
+	}
+
+	// pattern longer than column isn't truncated to its capacity
+	require.False(t, WhereS(&Statement{Left: ident("name"), Op: types.Like, Right: pattern("abcdef")}).Compare(row))
+	require.True(t, WhereS(&Statement{Left: ident("name"), Op: types.NotLike, Right: pattern("abcdef")}).Compare(row))
+	require.True(t, WhereS(&Statement{Left: ident("name"), Op: types.Like, Right: pattern("ab%")}).Compare(row))
+	require.True(t, WhereS(&Statement{Left: ident("name"), Op: types.ILike, Right: pattern("A_C%")}).Compare(row))
+}
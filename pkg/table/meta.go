@@ -1,8 +1,14 @@
 package table
 
 import (
+	"fmt"
+	"slices"
+
 	"go-dbms/pkg/column"
+	"go-dbms/pkg/data"
 	"go-dbms/pkg/index"
+
+	"github.com/pkg/errors"
 )
 
 // metadata represents the metadata for the table stored in a json file.
@@ -12,6 +18,11 @@ type Metadata struct {
 	PrimaryKey string                    `json:"primary_key"`
 	Columns    []*column.Column          `json:"columns"`
 	ColumnsMap map[string]*column.Column `json:"-"`
+
+	// version of columns and columns of previous versions,
+	// which records written before alterations still have
+	Version uint16         `json:"version,omitempty"`
+	Schemas []*data.Schema `json:"schemas,omitempty"`
 }
 
 type IMetadata interface {
@@ -25,6 +36,9 @@ type IMetadata interface {
 	SetColumns(v []*column.Column)
 	GetColumnsMap() map[string]*column.Column
 	SetColumnsMap(v map[string]*column.Column)
+	GetVersion() uint16
+	GetSchemas() []*data.Schema
+	Alter(a *Alteration) error
 }
 
 func (m *Metadata) GetEngine() Engine { return m.Engine }
@@ -37,3 +51,160 @@ func (m *Metadata) GetColumns() []*column.Column { return m.Columns }
 func (m *Metadata) SetColumns(v []*column.Column) { m.Columns = v }
 func (m *Metadata) GetColumnsMap() map[string]*column.Column { return m.ColumnsMap }
 func (m *Metadata) SetColumnsMap(v map[string]*column.Column) { m.ColumnsMap = v }
+func (m *Metadata) GetVersion() uint16 { return m.Version }
+func (m *Metadata) GetSchemas() []*data.Schema { return m.Schemas }
+
+type AlterAction string
+
+const (
+	ADD_COLUMN    AlterAction = "ADD COLUMN"
+	DROP_COLUMN   AlterAction = "DROP COLUMN"
+	RENAME_COLUMN AlterAction = "RENAME COLUMN"
+	MODIFY_COLUMN AlterAction = "MODIFY COLUMN"
+)
+
+// Alteration is change of table columns. Name is name of changed column,
+// Column is definition of added or modified column.
+type Alteration struct {
+	Action      AlterAction
+	Name        string
+	NewName     string
+	Column      *column.Column
+	Aggregation string
+}
+
+// Alter changes columns and indexes by a and increments version of columns.
+// Columns and indexes are replaced, so their previous lists stay unchanged.
+// Indexes, which must be rebuilt, get new options.
+func (m *Metadata) Alter(a *Alteration) error {
+	pos := slices.IndexFunc(m.Columns, func(c *column.Column) bool { return c.Name == a.Name })
+	if pos < 0 && a.Action != ADD_COLUMN {
+		return fmt.Errorf("unknown column:'%s'", a.Name)
+	}
+
+	columns := slices.Clone(m.Columns)
+	indexes := slices.Clone(m.Indexes)
+
+	switch a.Action {
+		case ADD_COLUMN:
+			if pos >= 0 {
+				return fmt.Errorf("column already exists:'%s'", a.Name)
+			}
+
+			col := *a.Column
+			col.ID = m.nextColumnID()
+			columns = append(columns, &col)
+		case DROP_COLUMN:
+			if len(columns) == 1 {
+				return errors.New("the only column of table can't be dropped")
+			}
+
+			columns = slices.Delete(columns, pos, pos+1)
+			for _, meta := range m.Indexes {
+				if !slices.Contains(meta.Columns, a.Name) {
+					continue
+				} else if meta.Name == m.PrimaryKey {
+					return fmt.Errorf("column of primary key can't be dropped:'%s'", a.Name)
+				}
+				indexes = slices.DeleteFunc(indexes, func(i *index.Meta) bool { return i == meta })
+			}
+		case RENAME_COLUMN:
+			if slices.ContainsFunc(columns, func(c *column.Column) bool { return c.Name == a.NewName }) {
+				return fmt.Errorf("column already exists:'%s'", a.NewName)
+			}
+
+			col := *columns[pos]
+			col.Name = a.NewName
+			columns[pos] = &col
+
+			for i, meta := range indexes {
+				if j := slices.Index(meta.Columns, a.Name); j >= 0 {
+					renamed := *meta
+					renamed.Columns = slices.Clone(meta.Columns)
+					renamed.Columns[j] = a.NewName
+					indexes[i] = &renamed
+				}
+			}
+		case MODIFY_COLUMN:
+			col := *a.Column
+			col.ID = columns[pos].ID
+			col.Name = a.Name
+			columns[pos] = &col
+
+			if err := m.rebuildIndexes(indexes, columns, a.Name); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("unsupported alteration:'%s'", a.Action)
+	}
+
+	columnsMap := make(map[string]*column.Column, len(columns))
+	for _, col := range columns {
+		columnsMap[col.Name] = col
+	}
+
+	m.Schemas = append(slices.Clone(m.Schemas), &data.Schema{Version: m.Version, Columns: m.Columns})
+	m.Version++
+	m.Columns = columns
+	m.ColumnsMap = columnsMap
+	m.Indexes = indexes
+	return nil
+}
+
+// rebuildIndexes replaces metas of indexes containing modified column with
+// ones having options for new key size. All secondary indexes are rebuilt
+// if primary key is changed, because they store it as key suffix.
+func (m *Metadata) rebuildIndexes(indexes []*index.Meta, columns []*column.Column, name string) error {
+	pk := slices.IndexFunc(indexes, func(i *index.Meta) bool { return i.Name == m.PrimaryKey })
+	pkChanged := slices.Contains(indexes[pk].Columns, name)
+
+	rebuild := func(i int, suffixSize int) error {
+		cols := make([]*column.Column, len(indexes[i].Columns))
+		for j, colName := range indexes[i].Columns {
+			cols[j] = columns[slices.IndexFunc(columns, func(c *column.Column) bool { return c.Name == colName })]
+		}
+
+		keySize, err := indexKeySize(cols, i == pk)
+		if err != nil {
+			return errors.Wrapf(err, "index '%s'", indexes[i].Name)
+		}
+
+		opts := *indexes[i].Options
+		opts.MaxKeySize = keySize
+		if i != pk && !opts.Uniq {
+			opts.MaxSuffixSize = suffixSize
+		}
+
+		meta := *indexes[i]
+		meta.Options = &opts
+		indexes[i] = &meta
+		return nil
+	}
+
+	if pkChanged {
+		if err := rebuild(pk, 0); err != nil {
+			return err
+		}
+	}
+	for i, meta := range indexes {
+		if i != pk && (pkChanged || slices.Contains(meta.Columns, name)) {
+			if err := rebuild(i, indexes[pk].Options.MaxKeySize); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (m *Metadata) nextColumnID() uint16 {
+	id := uint16(0)
+	for _, col := range m.Columns {
+		id = max(id, col.ID)
+	}
+	for _, s := range m.Schemas {
+		for _, col := range s.Columns {
+			id = max(id, col.ID)
+		}
+	}
+	return id + 1
+}
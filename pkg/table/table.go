@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"sync"
+	"time"
 
 	"go-dbms/pkg/column"
 	"go-dbms/pkg/data"
@@ -15,6 +16,7 @@ import (
 	"go-dbms/util/helpers"
 	"go-dbms/util/stream"
 
+	"github.com/pkg/errors"
 	"github.com/vahagz/bptree"
 	"golang.org/x/sync/errgroup"
 )
@@ -25,23 +27,32 @@ const (
 	indexPath        = "./indexes"
 )
 
+// time, which Lock waits for operations in progress
+const lockTimeout = 10 * time.Second
+
+var (
+	ErrClosed = errors.New("table is closed")
+	ErrBusy   = errors.New("table is busy, operations in progress didn't finish in time")
+)
+
 type ITable interface {
 	Insert(in stream.Reader[types.DataRow]) (stream.Reader[types.DataRow], *errgroup.Group)
+	Upsert(in stream.Reader[types.DataRow], oc *OnConflict) (stream.Reader[types.DataRow], *errgroup.Group)
 
 	Find(filter *statement.WhereStatement) stream.Reader[index.Entry]
 	ScanByIndex(name string, start, end *index.Filter) (stream.ReaderContinue[types.DataRow], error)
 	FullScan() stream.ReaderContinue[types.DataRow]
 	FullScanByIndex(indexName string, reverse bool) (stream.ReaderContinue[types.DataRow], error)
 
-	Update(filter *statement.WhereStatement, updateValuesMap types.DataRow) stream.Reader[types.DataRow]
+	Update(filter *statement.WhereStatement, set Assignment) (stream.Reader[types.DataRow], *errgroup.Group)
 	UpdateByIndex(
 		name string,
 		start, end *index.Filter,
 		filter *statement.WhereStatement,
-		updateValuesMap types.DataRow,
-	) (stream.Reader[types.DataRow], error)
+		set Assignment,
+	) (stream.Reader[types.DataRow], *errgroup.Group)
 
-	Delete(filter *statement.WhereStatement) stream.Reader[types.DataRow]
+	Delete(filter *statement.WhereStatement) (stream.Reader[types.DataRow], error)
 	DeleteByIndex(name string, start, end *index.Filter, filter *statement.WhereStatement) (stream.Reader[types.DataRow], error)
 
 	PrepareSpace(rows int)
@@ -51,11 +62,17 @@ type ITable interface {
 	ColumnsMap() map[string]*column.Column
 
 	CreateIndex(name *string, opts *index.IndexOptions) error
+	DropIndex(name string) error
 	HasIndex(name string) bool
+	IndexMeta(name string) *index.Meta
+	IndexesMeta() []*index.Meta
 
 	PrimaryColumns() []*column.Column
 	PrimaryKey() string
 
+	Alter(a *Alteration) error
+	Truncate() error
+
 	Engine() Engine
 	Drop()
 	Close()
@@ -69,6 +86,15 @@ type Table struct {
 	Meta    IMetadata
 	NewMeta func() IMetadata
 	Indexes map[string]*index.Index
+
+	// operations in progress, table and its indexes
+	// are closed only after they are finished
+	opsMu  *sync.Mutex
+	ops    *sync.WaitGroup
+	closed bool
+
+	// held by insert from search of conflicting rows till row is written
+	writeMu *sync.Mutex
 }
 
 func Open(opts *Options) (ITable, error) {
@@ -83,6 +109,9 @@ func Open(opts *Options) (ITable, error) {
 		Indexes:      map[string]*index.Index{},
 		DF:           &data.DataFile{},
 		NewMeta:      opts.NewMeta,
+		opsMu:        &sync.Mutex{},
+		ops:          &sync.WaitGroup{},
+		writeMu:      &sync.Mutex{},
 	}
 
 	err := table.Init(opts)
@@ -90,13 +119,7 @@ func Open(opts *Options) (ITable, error) {
 		return nil, err
 	}
 
-	dataOptions := data.DefaultOptions
-	dataOptions.Columns = table.Meta.GetColumns()
-
-	DF, err := data.Open(
-		filepath.Join(table.DataPath, dataFileName),
-		&dataOptions,
-	)
+	DF, err := table.openData()
 	if err != nil {
 		return nil, err
 	}
@@ -106,10 +129,21 @@ func Open(opts *Options) (ITable, error) {
 }
 
 func (t *Table) HasIndex(name string) bool {
-	_, ok := t.Indexes[name]
+	_, ok := t.indexes()[name]
 	return ok
 }
 
+func (t *Table) IndexMeta(name string) *index.Meta {
+	if i, ok := t.indexes()[name]; ok {
+		return i.Meta()
+	}
+	return nil
+}
+
+func (t *Table) IndexesMeta() []*index.Meta {
+	return t.Meta.GetIndexes()
+}
+
 func (t *Table) Columns() []*column.Column {
 	return t.Meta.GetColumns()
 }
@@ -132,7 +166,7 @@ func (t *Table) Column(name string) *column.Column {
 
 func (t *Table) PrepareSpace(rows int) {
 	t.DF.PrepareSpace(uint32(rows * int(t.DF.HeapSize() / t.DF.Count())))
-	for _, i := range t.Indexes {
+	for _, i := range t.indexes() {
 		i.PrepareSpace(rows)
 	}
 }
@@ -146,7 +180,17 @@ func (t *Table) Drop() {
 	helpers.Must(os.RemoveAll(t.DataPath))
 }
 
+// Close waits for operations in progress and closes table.
+// Operations started after that fail with ErrClosed.
 func (t *Table) Close() {
+	err := t.detach(func() error {
+		t.closed = true
+		return nil
+	})
+	if err != nil {
+		return
+	}
+
 	t.writeMeta()
 	for _, index := range t.Indexes {
 		index.Close()
@@ -154,6 +198,102 @@ func (t *Table) Close() {
 	t.DF.Close()
 }
 
+// indexes returns indexes of table, map is replaced
+// instead of being modified when indexes are changed.
+func (t *Table) indexes() map[string]*index.Index {
+	t.MetaMu.RLock()
+	defer t.MetaMu.RUnlock()
+	return t.Indexes
+}
+
+// setIndexes replaces indexes of table.
+func (t *Table) setIndexes(indexes map[string]*index.Index) {
+	t.MetaMu.Lock()
+	defer t.MetaMu.Unlock()
+	t.Indexes = indexes
+}
+
+// acquire registers operation on table, release must be called when
+// operation is finished. Returns false if table is closed.
+func (t *Table) acquire() (release func(), ok bool) {
+	t.opsMu.Lock()
+	defer t.opsMu.Unlock()
+
+	if t.closed {
+		return nil, false
+	}
+	ops := t.ops
+	ops.Add(1)
+	return ops.Done, true
+}
+
+// Stats returns count of rows and size of data file, zeros for closed table.
+func (t *Table) Stats() (rows, size uint64) {
+	release, ok := t.acquire()
+	if !ok {
+		return 0, 0
+	}
+	defer release()
+	return t.DF.Count(), t.DF.HeapSize()
+}
+
+// detach calls fn, which detaches resources from table, and waits for
+// operations started before, which may still use them. Operations started
+// after fn see changes made by it, so they don't block detach.
+func (t *Table) detach(fn func() error) error {
+	t.opsMu.Lock()
+	if t.closed {
+		t.opsMu.Unlock()
+		return ErrClosed
+	} else if err := fn(); err != nil {
+		t.opsMu.Unlock()
+		return err
+	}
+
+	ops := t.ops
+	t.ops = &sync.WaitGroup{}
+	t.opsMu.Unlock()
+
+	ops.Wait()
+	return nil
+}
+
+// Lock blocks new operations and waits for operations in progress, so table
+// can be changed exclusively till unlock is called. Operations in progress
+// may wait for new ones, e.g. self joins, so waiting is limited by timeout.
+func (t *Table) Lock() (unlock func(), err error) {
+	t.opsMu.Lock()
+	if t.closed {
+		t.opsMu.Unlock()
+		return nil, ErrClosed
+	}
+
+	ops := t.ops
+	t.ops = &sync.WaitGroup{}
+
+	done := make(chan struct{})
+	go func() {
+		ops.Wait()
+		close(done)
+	}()
+
+	select {
+		case <-done:
+			return t.opsMu.Unlock, nil
+		case <-time.After(lockTimeout):
+			// next detach or Lock must wait for operations in progress too
+			next := t.ops
+			next.Add(1)
+			go func() {
+				<-done
+				next.Done()
+			}()
+
+			t.opsMu.Unlock()
+			return nil, ErrBusy
+	}
+}
+
 func (t *Table) Init(opts *Options) error {
 	err := t.CreateDirs()
 	if err != nil {
@@ -170,7 +310,10 @@ func (t *Table) Init(opts *Options) error {
 
 func (t *Table) ReadMeta(opts *Options) error {
 	defer func ()  {
-		for _, c := range t.Meta.GetColumns() {
+		for i, c := range t.Meta.GetColumns() {
+			if c.ID == 0 {
+				c.ID = uint16(i + 1) // columns of tables created before alterations
+			}
 			t.Meta.GetColumnsMap()[c.Name] = c
 		}
 	}()
@@ -203,13 +346,38 @@ func (t *Table) ReadMeta(opts *Options) error {
 }
 
 func (t *Table) ReadIndexes() error {
+	indexes, err := t.openIndexes()
+	if err != nil {
+		return err
+	}
+
+	t.Indexes = indexes
+	return nil
+}
+
+// openData opens data file of table with columns of metadata.
+func (t *Table) openData() (*data.DataFile, error) {
+	dataOptions := data.DefaultOptions
+	dataOptions.Columns = t.Meta.GetColumns()
+	dataOptions.Version = t.Meta.GetVersion()
+	dataOptions.Schemas = t.Meta.GetSchemas()
+
+	return data.Open(
+		filepath.Join(t.DataPath, dataFileName),
+		&dataOptions,
+	)
+}
+
+// openIndexes opens indexes of metadata, which are linked to data file of table.
+func (t *Table) openIndexes() (map[string]*index.Index, error) {
+	indexes := map[string]*index.Index{}
 	for _, metaindex := range t.Meta.GetIndexes() {	
 		bpt, err := bptree.Open(
 			t.indexPath(metaindex.Name),
 			metaindex.Options,
 		)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		columns := make([]*column.Column, 0, len(metaindex.Columns))
@@ -217,7 +385,7 @@ func (t *Table) ReadIndexes() error {
 			columns = append(columns, t.Meta.GetColumnsMap()[colName])
 		}
 
-		t.Indexes[metaindex.Name] = index.New(
+		indexes[metaindex.Name] = index.New(
 			metaindex,
 			t.DF,
 			bpt,
@@ -226,13 +394,13 @@ func (t *Table) ReadIndexes() error {
 		)
 	}
 
-	for k, i := range t.Indexes {
+	for k, i := range indexes {
 		if k != t.Meta.GetPrimaryKey() {
-			i.SetPK(t.Indexes[t.Meta.GetPrimaryKey()])
+			i.SetPK(indexes[t.Meta.GetPrimaryKey()])
 		}
 	}
 
-	return nil
+	return indexes, nil
 }
 
 func (t *Table) indexPath(name string) string {
@@ -276,15 +444,6 @@ func (t *Table) Row2map(row []types.DataType) types.DataRow {
 	return rowMap
 }
 
-func (t *Table) row2pk(row types.DataRow) types.DataRow {
-	pkCols := t.Indexes[t.Meta.GetPrimaryKey()].Columns()
-	pkRow := make(types.DataRow, 1)
-	for _, col := range pkCols {
-		pkRow[col.Name] = row[col.Name]
-	}
-	return pkRow
-}
-
 func (t *Table) validateMap(row types.DataRow) error {
 	if len(row) > len(t.Meta.GetColumns()) {
 		return fmt.Errorf("invalid columns count")
@@ -304,7 +463,7 @@ func (t *Table) setDefaults(row types.DataRow) {
 
 	for _, column := range t.Meta.GetColumns() {
 		if _, ok := row[column.Name]; !ok {
-			row[column.Name] = column.Meta.Default()
+			row[column.Name] = column.DefaultValue()
 		}
 	}
 }
@@ -0,0 +1,192 @@
+package table
+
+import (
+	"fmt"
+	"slices"
+
+	"go-dbms/pkg/column"
+	"go-dbms/pkg/index"
+	"go-dbms/pkg/types"
+	"go-dbms/pkg/types/hashed"
+	"go-dbms/util/helpers"
+
+	"github.com/pkg/errors"
+	"github.com/vahagz/bptree"
+	allocator "github.com/vahagz/disk-allocator/heap"
+)
+
+// Alter changes columns of table. Records aren't rewritten, they're upgraded
+// to new columns on read. Indexes of changed columns are rebuilt.
+func (t *Table) Alter(a *Alteration) error {
+	unlock, err := t.Lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := t.CheckAlter(a); err != nil {
+		return err
+	} else if err := t.AlterMeta(a); err != nil {
+		return err
+	}
+	return t.AlterData()
+}
+
+// CheckAlter checks if data of table can be altered by a. Table must be locked.
+func (t *Table) CheckAlter(a *Alteration) error {
+	if a.Action != MODIFY_COLUMN {
+		return nil
+	}
+
+	col := *a.Column
+	col.Name = a.Name
+	if err := t.checkCast(&col); err != nil {
+		return err
+	}
+	for _, meta := range t.Meta.GetIndexes() {
+		if meta.Uniq && slices.Contains(meta.Columns, a.Name) {
+			if err := t.checkUnique(meta, &col); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkCast checks if values of column can be casted to its new type.
+func (t *Table) checkCast(col *column.Column) error {
+	return t.DF.Scan(func(ptr allocator.Pointable, row []types.DataType) (bool, error) {
+		val := t.Row2map(row)[col.Name]
+		if val == nil {
+			if !col.Nullable {
+				return true, fmt.Errorf("column '%s' has NULL values, it can't be NOT NULL", col.Name)
+			}
+			return false, nil
+		}
+
+		if _, err := castStrict(col, val); err != nil {
+			return true, errors.Wrapf(err, "value '%v' of column '%s' can't be casted to new type", val.Value(), col.Name)
+		}
+		return false, nil
+	})
+}
+
+// castStrict casts val to type of col. Casts, which change value,
+// e.g. of not numeric string to number or overflowing ones, fail.
+func castStrict(col *column.Column, val types.DataType) (res types.DataType, err error) {
+	defer helpers.RecoverOnError(&err)()
+
+	res = helpers.MustVal(col.Cast(val))
+	if back := helpers.MustVal(res.Cast(val.MetaCopy())); back.Compare(val) != 0 {
+		return nil, fmt.Errorf("value is changed to '%v'", res.Value())
+	}
+	return res, nil
+}
+
+// checkUnique checks if keys of unique index stay unique, when values
+// of column are casted to its new type.
+func (t *Table) checkUnique(meta *index.Meta, col *column.Column) error {
+	keys := map[string]struct{}{}
+	vals := make([]types.DataType, len(meta.Columns))
+	return t.DF.Scan(func(ptr allocator.Pointable, row []types.DataType) (bool, error) {
+		rowMap := t.Row2map(row)
+		for i, name := range meta.Columns {
+			vals[i] = rowMap[name]
+			if name == col.Name && vals[i] != nil {
+				vals[i] = helpers.MustVal(col.Cast(vals[i]))
+			}
+		}
+
+		key := hashed.Key(vals...)
+		if _, ok := keys[key]; ok {
+			return true, fmt.Errorf("values of column '%s' become duplicate in unique index '%s'", col.Name, meta.Name)
+		}
+		keys[key] = struct{}{}
+		return false, nil
+	})
+}
+
+// AlterMeta changes metadata of table by a and saves it. Metadata may be shared
+// with other tables, so AlterData must be called for all of them after that.
+func (t *Table) AlterMeta(a *Alteration) error {
+	t.MetaMu.Lock()
+	err := t.Meta.Alter(a)
+	t.MetaMu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	t.writeMeta()
+	return nil
+}
+
+// AlterData changes columns of data file and indexes to ones of metadata.
+// Indexes removed from metadata are removed, indexes with new options are
+// rebuilt. Records of legacy data file are migrated before, they may be
+// moved, so all indexes are rebuilt then. Table must be locked.
+func (t *Table) AlterData() error {
+	migrated := !t.DF.Versioned()
+	if err := t.DF.Migrate(); err != nil {
+		return errors.Wrap(err, "failed to migrate legacy data file")
+	} else if err := t.DF.Alter(t.Meta.GetVersion(), t.Meta.GetColumns()); err != nil {
+		return err
+	}
+
+	metas := map[string]*index.Meta{}
+	for _, meta := range t.Meta.GetIndexes() {
+		metas[meta.Name] = meta
+	}
+
+	indexes := make(map[string]*index.Index, len(metas))
+	rebuilt := []*index.Index{}
+	for name, i := range t.indexes() {
+		meta, ok := metas[name]
+		if !ok {
+			i.Remove()
+			continue
+		}
+
+		columns := make([]*column.Column, 0, len(meta.Columns))
+		for _, colName := range meta.Columns {
+			columns = append(columns, t.Meta.GetColumnsMap()[colName])
+		}
+
+		if meta.Options == i.Meta().Options && !migrated {
+			i.Reset(meta, columns)
+			indexes[name] = i
+			continue
+		}
+
+		i.Remove()
+		tree, err := bptree.Open(t.indexPath(name), meta.Options)
+		if err != nil {
+			return errors.Wrapf(err, "failed to rebuild index '%s'", name)
+		}
+
+		i = index.New(meta, t.DF, tree, columns, meta.Uniq)
+		indexes[name] = i
+		rebuilt = append(rebuilt, i)
+	}
+
+	pk := indexes[t.Meta.GetPrimaryKey()]
+	for name, i := range indexes {
+		if name != t.Meta.GetPrimaryKey() {
+			i.SetPK(pk)
+		}
+	}
+	t.setIndexes(indexes)
+
+	if len(rebuilt) == 0 {
+		return nil
+	}
+
+	return t.DF.Scan(func(ptr allocator.Pointable, row []types.DataType) (bool, error) {
+		rowMap := t.Row2map(row)
+		for _, i := range rebuilt {
+			if err := i.Insert(ptr, rowMap); err != nil {
+				return true, errors.Wrapf(err, "failed to rebuild index '%s'", i.Meta().Name)
+			}
+		}
+		return false, nil
+	})
+}
@@ -13,16 +13,23 @@ import (
 	allocator "github.com/vahagz/disk-allocator/heap"
 )
 
-func (t *Table) Delete(filter *statement.WhereStatement) stream.Reader[types.DataRow] {
+func (t *Table) Delete(filter *statement.WhereStatement) (stream.Reader[types.DataRow], error) {
+	release, ok := t.acquire()
+	if !ok {
+		return nil, ErrClosed
+	}
+
 	s := stream.New[types.DataRow](0)
 	go func ()  {
 		defer s.Close()
-		helpers.Must(t.delete(t.Find(filter).Slice(), t.Indexes, func(row types.DataRow) error {
+		defer release()
+
+		helpers.Must(t.delete(t.findAll(filter), t.indexes(), func(row types.DataRow) error {
 			s.Push(row)
 			return nil
 		}))
 	}()
-	return s
+	return s, nil
 }
 
 func (t *Table) DeleteByIndex(
@@ -30,17 +37,24 @@ func (t *Table) DeleteByIndex(
 	start, end *index.Filter,
 	filter *statement.WhereStatement,
 ) (stream.Reader[types.DataRow], error) {
-	delIndex, ok := t.Indexes[name]
+	release, ok := t.acquire()
+	if !ok {
+		return nil, ErrClosed
+	}
+
+	delIndex, ok := t.indexes()[name]
 	if !ok {
+		release()
 		return nil, fmt.Errorf("index not found => '%s'", name)
 	}
 
 	s := stream.New[types.DataRow](0)
 	go func ()  {
 		defer s.Close()
+		defer release()
 		helpers.Must(t.delete(
 			delIndex.ScanEntries(start, end, filter),
-			t.Indexes,
+			t.indexes(),
 			func(row types.DataRow) error {
 				s.Push(row)
 				return nil
@@ -57,7 +71,7 @@ func (t *Table) delete(
 ) error {
 	for _, e := range entries {
 		t.deleteRow(e.Ptr, e.Row, indexesToUpdate)
-		if err := scanFn(t.row2pk(e.Row)); err != nil {
+		if err := scanFn(e.Row); err != nil {
 			return errors.Wrap(err, "failed to delete row")
 		}
 	}
@@ -2,7 +2,9 @@ package table
 
 import (
 	"fmt"
+	"maps"
 	"os"
+	"slices"
 	"strings"
 
 	"go-dbms/pkg/column"
@@ -15,6 +17,12 @@ import (
 )
 
 func (t *Table) CreateIndex(name *string, opts *index.IndexOptions) error {
+	release, ok := t.acquire()
+	if !ok {
+		return ErrClosed
+	}
+	defer release()
+
 	if !opts.Primary && t.Meta.GetPrimaryKey() == "" {
 		return errors.New("first index must be primary")
 	}
@@ -22,30 +30,31 @@ func (t *Table) CreateIndex(name *string, opts *index.IndexOptions) error {
 		return errors.New("primary index already created")
 	}
 	if name != nil {
-		if _, ok := t.Indexes[*name]; ok {
+		if _, ok := t.indexes()[*name]; ok {
 			return fmt.Errorf("index with name:'%s' already exists", *name)
 		}
 	}
 
-	keySize := 0
 	columnsList := make([]*column.Column, 0, len(opts.Columns))
 	for _, columnName := range opts.Columns {
 		if col, ok := t.Meta.GetColumnsMap()[columnName]; !ok {
 			return fmt.Errorf("unknown column:'%s'", columnName)
-		} else if !col.Meta.IsFixedSize() {
-			return fmt.Errorf("column must be of fixed size")
 		} else {
-			keySize += col.Meta.Size()
 			columnsList = append(columnsList, col)
 		}
 	}
 
+	keySize, err := indexKeySize(columnsList, opts.Primary)
+	if err != nil {
+		return err
+	}
+
 	if name == nil {
 		name = new(string)
 		*name = strings.Join(opts.Columns, "_")
 		for i := 1; i < 100; i++ {
 			postfix := fmt.Sprintf("_%d", i)
-			if _, ok := t.Indexes[*name + postfix]; !ok {
+			if _, ok := t.indexes()[*name + postfix]; !ok {
 				*name += postfix
 				break
 			}
@@ -55,7 +64,7 @@ func (t *Table) CreateIndex(name *string, opts *index.IndexOptions) error {
 	suffixSize := 0
 	suffixCols := 0
 	if !opts.Primary {
-		opts := t.Indexes[t.Meta.GetPrimaryKey()].Options()
+		opts := t.indexes()[t.Meta.GetPrimaryKey()].Options()
 		suffixSize = opts.MaxKeySize
 		suffixCols = opts.KeyCols
 	}
@@ -85,12 +94,16 @@ func (t *Table) CreateIndex(name *string, opts *index.IndexOptions) error {
 	}
 
 	i := index.New(Meta, t.DF, tree, columnsList, opts.Uniq)
-	t.Indexes[*name] = i
+	prev := t.indexes()
+	indexes := maps.Clone(prev)
+	indexes[*name] = i
+	t.setIndexes(indexes)
 
 	err = t.DF.Scan(func(ptr allocator.Pointable, row []types.DataType) (bool, error) {
 		return false, i.Insert(ptr, t.Row2map(row))
 	})
 	if err != nil {
+		t.setIndexes(prev)
 		i.Remove()
 		return err
 	}
@@ -98,10 +111,58 @@ func (t *Table) CreateIndex(name *string, opts *index.IndexOptions) error {
 	if opts.Primary {
 		t.Meta.SetPrimaryKey(*name)
 	} else {
-		i.SetPK(t.Indexes[t.Meta.GetPrimaryKey()])
+		i.SetPK(t.indexes()[t.Meta.GetPrimaryKey()])
 	}
 
 	t.Meta.SetIndexes(append(t.Meta.GetIndexes(), Meta))
 	t.writeMeta()
 	return nil
 }
+
+// indexKeySize returns size of key of index on columns.
+func indexKeySize(columns []*column.Column, primary bool) (int, error) {
+	keySize := 0
+	for _, col := range columns {
+		if !col.Meta.IsFixedSize() {
+			return 0, fmt.Errorf("column must be of fixed size")
+		} else if col.Nullable && primary {
+			return 0, fmt.Errorf("primary key column can't be nullable:'%s'", col.Name)
+		}
+
+		keySize += col.Meta.Size()
+		if col.Nullable {
+			keySize++ // null marker
+		}
+	}
+	return keySize, nil
+}
+
+// DropIndex removes index from table and metadata. Index files are removed
+// after operations, which started before and may use index, are finished.
+func (t *Table) DropIndex(name string) error {
+	var i *index.Index
+	err := t.detach(func() error {
+		var ok bool
+		if i, ok = t.indexes()[name]; !ok {
+			return fmt.Errorf("index not found => '%s'", name)
+		} else if t.isPK(i) {
+			return errors.New("primary index can't be dropped")
+		}
+
+		indexes := maps.Clone(t.indexes())
+		delete(indexes, name)
+		t.setIndexes(indexes)
+
+		t.Meta.SetIndexes(slices.DeleteFunc(slices.Clone(t.Meta.GetIndexes()), func(m *index.Meta) bool {
+			return m.Name == name
+		}))
+		t.writeMeta()
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	i.Remove()
+	return nil
+}
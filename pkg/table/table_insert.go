@@ -2,6 +2,8 @@ package table
 
 import (
 	"fmt"
+	"maps"
+	"slices"
 
 	"go-dbms/pkg/index"
 	"go-dbms/pkg/types"
@@ -12,20 +14,53 @@ import (
 	"golang.org/x/sync/errgroup"
 )
 
+// ConflictAction is what insert does with row, which
+// conflicts with existing row by unique index.
+type ConflictAction uint8
+
+const (
+	CONFLICT_FAIL ConflictAction = iota
+	CONFLICT_IGNORE
+	CONFLICT_REPLACE
+	CONFLICT_UPDATE
+)
+
+// OnConflict tells how to insert conflicting rows. Existing row is updated
+// by Set on CONFLICT_UPDATE, existing rows are deleted on CONFLICT_REPLACE.
+type OnConflict struct {
+	Action ConflictAction
+	Set    Assignment
+}
+
 func (t *Table) Insert(in stream.Reader[types.DataRow]) (stream.Reader[types.DataRow], *errgroup.Group) {
+	return t.Upsert(in, &OnConflict{Action: CONFLICT_FAIL})
+}
+
+// Upsert inserts rows and handles conflicting ones by oc. Inserted or
+// updated row is pushed to out for every row, nil for ignored rows.
+func (t *Table) Upsert(in stream.Reader[types.DataRow], oc *OnConflict) (stream.Reader[types.DataRow], *errgroup.Group) {
 	eg := &errgroup.Group{}
 	out := stream.New[types.DataRow](0)
 
+	release, ok := t.acquire()
 	eg.Go(func () error {
 		defer out.Close()
+		if !ok {
+			return ErrClosed
+		}
+		defer release()
+
 		for row, ok := in.Pop(); ok; row, ok = in.Pop() {
 			t.setDefaults(row)
 			if err := t.validateMap(row); err != nil {
 				return errors.Wrap(err, "validation error")
-			} else if err := t.canInsert(row); err != nil {
+			}
+
+			row, err := t.upsert(row, oc)
+			if err != nil {
 				return errors.Wrapf(err, "can't insert row")
 			}
-			out.Push(t.insert(row))
+			out.Push(row)
 		}
 		return nil
 	})
@@ -33,25 +68,90 @@ func (t *Table) Insert(in stream.Reader[types.DataRow]) (stream.Reader[types.Dat
 	return out, eg
 }
 
+// upsert inserts row or handles its conflict. Conflicts are searched and
+// row is written under write lock, so concurrent inserts don't miss each other.
+func (t *Table) upsert(row types.DataRow, oc *OnConflict) (types.DataRow, error) {
+	t.writeMu.Lock()
+	defer t.writeMu.Unlock()
+
+	if oc.Action == CONFLICT_FAIL {
+		if err := t.canInsert(row); err != nil {
+			return nil, err
+		}
+		return t.insert(row), nil
+	}
+
+	conflicts, err := t.conflicts(row)
+	if err != nil {
+		return nil, err
+	} else if len(conflicts) == 0 {
+		return t.insert(row), nil
+	}
+
+	switch oc.Action {
+		case CONFLICT_IGNORE:
+			return nil, nil
+
+		case CONFLICT_REPLACE:
+			for _, e := range conflicts {
+				t.deleteRow(e.Ptr, e.Row, t.indexes())
+			}
+			return t.insert(row), nil
+
+		case CONFLICT_UPDATE:
+			e := conflicts[0]
+			values, err := oc.Set(e.Row)
+			if err != nil {
+				return nil, err
+			}
+
+			updated := maps.Clone(e.Row)
+			maps.Copy(updated, values)
+			if err := t.updateRow(e.Ptr, e.Row, updated, t.getAffectedIndexes(values)); err != nil {
+				return nil, err
+			}
+			return updated, nil
+	}
+	return nil, fmt.Errorf("invalid conflict action: %d", oc.Action)
+}
+
+// conflicts returns existing rows, which have the same keys
+// as row in unique indexes, in order of primary key first.
+func (t *Table) conflicts(row types.DataRow) ([]index.Entry, error) {
+	indexes := t.indexes()
+	names := make([]string, 0, len(indexes))
+	for name, i := range indexes {
+		if i.Meta().Uniq && !t.isPK(i) {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	names = slices.Insert(names, 0, t.Meta.GetPrimaryKey())
+
+	entries := []index.Entry{}
+	for _, name := range names {
+		ptr, ok, err := indexes[name].Get(row)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to search index '%s'", name)
+		} else if !ok || slices.ContainsFunc(entries, func(e index.Entry) bool { return e.Ptr.Equal(ptr) }) {
+			continue
+		}
+		entries = append(entries, index.Entry{Ptr: ptr, Row: t.DF.GetMap(ptr)})
+	}
+	return entries, nil
+}
+
 func (t *Table) insert(row types.DataRow) types.DataRow {
 	ptr, err := t.DF.InsertMem(t.map2row(row))
 	if err != nil {
 		panic(errors.Wrap(err, "failed to insert into datafile"))
 	}
 
-	var pk types.DataRow
-	for _, index := range t.Indexes {
+	for _, index := range t.indexes() {
 		t.insertIndex(index, ptr, row)
-		if t.isPK(index) {
-			pkCols := index.Columns()
-			pk = make(types.DataRow, len(pkCols))
-			for _, c := range pkCols {
-				pk[c.Name] = row[c.Name]
-			}
-		}
 	}
 
-	return pk
+	return row
 }
 
 func (t *Table) insertIndex(i *index.Index, ptr allocator.Pointable, row types.DataRow) {
@@ -63,7 +163,7 @@ func (t *Table) insertIndex(i *index.Index, ptr allocator.Pointable, row types.D
 func (t *Table) canInsert(row types.DataRow) error {
 	canInsert := true
 	var conflictIndex string
-	for _, i := range t.Indexes {
+	for _, i := range t.indexes() {
 		if !t.canInsertIndex(i, row) {
 			canInsert = false
 			conflictIndex = i.Meta().Name
@@ -15,38 +15,67 @@ import (
 
 func (t *Table) Find(filter *statement.WhereStatement) stream.Reader[index.Entry] {
 	s := stream.New[index.Entry](1)
+	release, ok := t.acquire()
 	go func() {
 		defer s.Close()
-		helpers.Must(t.Indexes[t.Meta.GetPrimaryKey()].Scan(index.ScanOptions{
-			ScanOptions: bptree.ScanOptions{
-				Strict: true,
-			},
-		}, func(key [][]byte, ptr allocator.Pointable) (bool, error) {
-			row := t.get(ptr)
-			if filter == nil || filter.Compare(row) {
-				s.Push(index.Entry{
-					Ptr: ptr,
-					Row: row,
-				})
-			}
-			return false, nil
-		}))
+		if !ok {
+			return
+		}
+		defer release()
+		t.find(filter, func(e index.Entry) {
+			s.Push(e)
+		})
 	}()
 	return s
 }
 
+// find calls fn for entries of rows matching filter. Unlike Find it doesn't
+// register operation, so it's used by operations already registered.
+func (t *Table) find(filter *statement.WhereStatement, fn func(e index.Entry)) {
+	helpers.Must(t.indexes()[t.Meta.GetPrimaryKey()].Scan(index.ScanOptions{
+		ScanOptions: bptree.ScanOptions{
+			Strict: true,
+		},
+	}, func(key [][]byte, ptr allocator.Pointable) (bool, error) {
+		row := t.get(ptr)
+		if filter == nil || filter.Compare(row) {
+			fn(index.Entry{
+				Ptr: ptr,
+				Row: row,
+			})
+		}
+		return false, nil
+	}))
+}
+
+// findAll returns entries of rows matching filter.
+func (t *Table) findAll(filter *statement.WhereStatement) []index.Entry {
+	entries := []index.Entry{}
+	t.find(filter, func(e index.Entry) {
+		entries = append(entries, e)
+	})
+	return entries
+}
+
 func (t *Table) ScanByIndex(
 	name string,
 	start, end *index.Filter,
 ) (stream.ReaderContinue[types.DataRow], error) {
-	index, ok := t.Indexes[name]
+	release, ok := t.acquire()
 	if !ok {
+		return nil, ErrClosed
+	}
+
+	index, ok := t.indexes()[name]
+	if !ok {
+		release()
 		return nil, fmt.Errorf("index not found => '%s'", name)
 	}
 
 	s := stream.New[types.DataRow](1)
 	go func() {
 		defer s.Close()
+		defer release()
 		helpers.Must(index.ScanFilter(start, end, func(ptr allocator.Pointable) (stop bool, err error) {
 			s.Push(t.get(ptr))
 			return !s.ShouldContinue(), nil
@@ -57,8 +86,14 @@ func (t *Table) ScanByIndex(
 
 func (t *Table) FullScan() stream.ReaderContinue[types.DataRow] {
 	s := stream.New[types.DataRow](1)
+	release, ok := t.acquire()
 	go func ()  {
 		defer s.Close()
+		if !ok {
+			return
+		}
+		defer release()
+
 		helpers.Must(t.DF.Scan(func(ptr allocator.Pointable, row []types.DataType) (bool, error) {
 			s.Push(t.Row2map(row))
 			return !s.ShouldContinue(), nil
@@ -71,14 +106,21 @@ func (t *Table) FullScanByIndex(
 	indexName string,
 	reverse bool,
 ) (stream.ReaderContinue[types.DataRow], error) {
-	idx, ok := t.Indexes[indexName]
+	release, ok := t.acquire()
+	if !ok {
+		return nil, ErrClosed
+	}
+
+	idx, ok := t.indexes()[indexName]
 	if !ok {
+		release()
 		return nil, fmt.Errorf("index not found => %v", indexName)
 	}
 
 	s := stream.New[types.DataRow](1)
 	go func ()  {
 		defer s.Close()
+		defer release()
 		helpers.Must(idx.Scan(index.ScanOptions{
 			ScanOptions: bptree.ScanOptions{
 				Reverse: reverse,
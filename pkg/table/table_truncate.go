@@ -0,0 +1,46 @@
+package table
+
+import (
+	"go-dbms/pkg/types"
+
+	"github.com/pkg/errors"
+)
+
+// Truncate removes all rows of table by recreating its data file and indexes.
+// Metadata is kept, values of auto increment columns start from beginning.
+func (t *Table) Truncate() error {
+	unlock, err := t.Lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	for _, i := range t.indexes() {
+		i.Remove()
+	}
+	t.DF.Remove()
+
+	// indexes are linked to data file by pointer, so it's replaced in place
+	DF, err := t.openData()
+	if err != nil {
+		return errors.Wrap(err, "failed to recreate data file")
+	}
+	*t.DF = *DF
+
+	indexes, err := t.openIndexes()
+	if err != nil {
+		return errors.Wrap(err, "failed to recreate indexes")
+	}
+	t.setIndexes(indexes)
+
+	t.MetaMu.Lock()
+	for _, c := range t.Meta.GetColumns() {
+		if m, ok := c.Meta.(*types.DataTypeINTEGERMeta); ok {
+			m.AI.Value = 0
+		}
+	}
+	t.MetaMu.Unlock()
+
+	t.writeMeta()
+	return nil
+}
@@ -2,79 +2,102 @@ package table
 
 import (
 	"fmt"
+	"maps"
 	"slices"
 
 	"go-dbms/pkg/column"
 	"go-dbms/pkg/index"
 	"go-dbms/pkg/statement"
 	"go-dbms/pkg/types"
-	"go-dbms/util/helpers"
 	"go-dbms/util/stream"
 
 	"github.com/pkg/errors"
 	allocator "github.com/vahagz/disk-allocator/heap"
+	"golang.org/x/sync/errgroup"
 )
 
-func (t *Table) Update(filter *statement.WhereStatement, updateValuesMap types.DataRow) stream.Reader[types.DataRow] {
+// Assignment returns new values of updated columns for given row.
+type Assignment func(row types.DataRow) (types.DataRow, error)
+
+// SetValues returns assignment of the same values to every row.
+func SetValues(values types.DataRow) Assignment {
+	return func(types.DataRow) (types.DataRow, error) {
+		return values, nil
+	}
+}
+
+// Update updates rows matching filter by set. Every updated row with new
+// values is pushed to returned stream, update error is returned by group.
+func (t *Table) Update(filter *statement.WhereStatement, set Assignment) (stream.Reader[types.DataRow], *errgroup.Group) {
+	eg := &errgroup.Group{}
 	s := stream.New[types.DataRow](0)
-	go func ()  {
+	release, ok := t.acquire()
+	eg.Go(func () error {
 		defer s.Close()
-		helpers.Must(t.update(t.Find(filter).Slice(), updateValuesMap, t.Indexes, func(row types.DataRow) error {
+		if !ok {
+			return ErrClosed
+		}
+		defer release()
+
+		return t.update(t.findAll(filter), set, func(row types.DataRow) error {
 			s.Push(row)
 			return nil
-		}))
-	}()
-	return s
+		})
+	})
+	return s, eg
 }
 
 func (t *Table) UpdateByIndex(
 	name string,
 	start, end *index.Filter,
 	filter *statement.WhereStatement,
-	updateValuesMap types.DataRow,
-) (stream.Reader[types.DataRow], error) {
-	updIndex, ok := t.Indexes[name]
-	if !ok {
-		return nil, fmt.Errorf("index not found => '%s'", name)
-	}
-
+	set Assignment,
+) (stream.Reader[types.DataRow], *errgroup.Group) {
+	eg := &errgroup.Group{}
 	s := stream.New[types.DataRow](0)
-	go func ()  {
+	release, ok := t.acquire()
+	eg.Go(func () error {
 		defer s.Close()
-		helpers.Must(t.update(
+		if !ok {
+			return ErrClosed
+		}
+		defer release()
+
+		updIndex, ok := t.indexes()[name]
+		if !ok {
+			return fmt.Errorf("index not found => '%s'", name)
+		}
+
+		return t.update(
 			updIndex.ScanEntries(start, end, filter),
-			updateValuesMap,
-			t.getAffectedIndexes(updateValuesMap),
+			set,
 			func(row types.DataRow) error {
 				s.Push(row)
 				return nil
 			},
-		))
-	}()
-	return s, nil
+		)
+	})
+	return s, eg
 }
 
 func (t *Table) update(
 	entries []index.Entry,
-	updateValuesMap types.DataRow,
-	indexesToUpdate map[string]*index.Index,
+	set Assignment,
 	scanFn func(row types.DataRow) error,
 ) error {
 	for _, e := range entries {
-		updated := make(types.DataRow, len(e.Row))
-		for col, oldVal := range e.Row {
-			if newVal, ok := updateValuesMap[col]; ok {
-				updated[col] = newVal
-			} else {
-				updated[col] = oldVal
-			}
+		values, err := set(e.Row)
+		if err != nil {
+			return errors.Wrap(err, "failed to update table")
 		}
 
-		if err := t.updateRow(e.Ptr, e.Row, updated, indexesToUpdate); err != nil {
+		updated := maps.Clone(e.Row)
+		maps.Copy(updated, values)
+		if err := t.updateRow(e.Ptr, e.Row, updated, t.getAffectedIndexes(values)); err != nil {
 			return errors.Wrap(err, "failed to update table")
 		}
 
-		if err := scanFn(t.row2pk(e.Row)); err != nil {
+		if err := scanFn(updated); err != nil {
 			return errors.Wrap(err, "failed to update table")
 		}
 	}
@@ -89,10 +112,10 @@ func (t *Table) updateRow(
 ) error {
 	newPtr := t.DF.UpdateMem(oldPtr, t.map2row(newRow))
 	ptrUpdated := !oldPtr.Equal(newPtr) // pointer in datafile updated
-	updatedIndexes := make([]*index.Index, 0, len(t.Indexes))
+	updatedIndexes := make([]*index.Index, 0, len(t.indexes()))
 	var updateErr error
 
-	for name, i := range t.Indexes {
+	for name, i := range t.indexes() {
 		_, indexShouldUpdate := indexesToUpdate[name]
 		if ptrUpdated || indexShouldUpdate {
 			if updateErr = t.updateIndex(i, newPtr, oldRow, newRow); updateErr != nil {
@@ -124,7 +147,7 @@ func (t *Table) updateIndex(
 
 	if !t.canInsertIndex(i, newRow) {
 		t.insertIndex(i, newPtr, oldRow)
-		return fmt.Errorf("can't update row => %v", oldRow)
+		return fmt.Errorf("can't update, '%s' causes conflict", i.Meta().Name)
 	}
 
 	t.insertIndex(i, newPtr, newRow)
@@ -132,9 +155,9 @@ func (t *Table) updateIndex(
 }
 
 func (t *Table) getAffectedIndexes(row types.DataRow) map[string]*index.Index {
-	indexesToUpdate := make(map[string]*index.Index, len(t.Indexes))
+	indexesToUpdate := make(map[string]*index.Index, len(t.indexes()))
 
-	for _, i := range t.Indexes {
+	for _, i := range t.indexes() {
 		for col := range row {
 			colFound := -1 != slices.IndexFunc(i.Columns(), func(c *column.Column) bool {
 				return c.Name == col
@@ -0,0 +1,107 @@
+package types
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+)
+
+// nullCode marks NULL value in encoded row
+const nullCode = 0xff
+
+// MarshalBinary encodes row with type information of every value, so it
+// can be restored without knowing columns list. Used to spill rows to disk.
+func (dr DataRow) MarshalBinary() ([]byte, error) {
+	buf := make([]byte, 2, 64)
+	count := 0
+
+	for name, val := range dr {
+		if val == nil {
+			buf = binary.BigEndian.AppendUint16(buf, uint16(len(name)))
+			buf = append(buf, name...)
+			buf = append(buf, nullCode)
+			count++
+			continue
+		}
+
+		meta, err := json.Marshal(val.MetaCopy())
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := val.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+
+		buf = binary.BigEndian.AppendUint16(buf, uint16(len(name)))
+		buf = append(buf, name...)
+		buf = append(buf, byte(val.GetCode()))
+		buf = binary.BigEndian.AppendUint16(buf, uint16(len(meta)))
+		buf = append(buf, meta...)
+		buf = binary.BigEndian.AppendUint32(buf, uint32(len(data)))
+		buf = append(buf, data...)
+		count++
+	}
+
+	binary.BigEndian.PutUint16(buf[:2], uint16(count))
+	return buf, nil
+}
+
+// UnmarshalBinary decodes row encoded by MarshalBinary.
+func (dr *DataRow) UnmarshalBinary(d []byte) error {
+	if len(d) < 2 {
+		return fmt.Errorf("invalid row size: %v", len(d))
+	}
+
+	count := int(binary.BigEndian.Uint16(d[:2]))
+	row := make(DataRow, count)
+	offset := 2
+
+	for i := 0; i < count; i++ {
+		nameSize := int(binary.BigEndian.Uint16(d[offset : offset+2]))
+		offset += 2
+		name := string(d[offset : offset+nameSize])
+		offset += nameSize
+
+		code := TypeCode(d[offset])
+		offset++
+		if code == nullCode {
+			row[name] = nil
+			continue
+		}
+
+		metaSize := int(binary.BigEndian.Uint16(d[offset : offset+2]))
+		offset += 2
+		meta := Meta(code)
+		if err := json.Unmarshal(d[offset:offset+metaSize], meta); err != nil {
+			return err
+		}
+		offset += metaSize
+
+		dataSize := int(binary.BigEndian.Uint32(d[offset : offset+4]))
+		offset += 4
+		val := Type(meta)
+		if err := val.UnmarshalBinary(d[offset : offset+dataSize]); err != nil {
+			return err
+		}
+		offset += dataSize
+
+		row[name] = val
+	}
+
+	*dr = row
+	return nil
+}
+
+// MemSize returns approximate amount of memory occupied by row.
+func (dr DataRow) MemSize() int {
+	size := 0
+	for name, val := range dr {
+		size += len(name) + 16
+		if val != nil {
+			size += val.Size()
+		}
+	}
+	return size
+}
@@ -0,0 +1,135 @@
+package hashed
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/fnv"
+
+	"go-dbms/pkg/types"
+	"go-dbms/pkg/types/spill"
+)
+
+// DefaultMemLimit is the amount of memory in bytes set can use before
+// spilling rows to disk.
+const DefaultMemLimit = 32 << 20
+
+const (
+	partitions = 16
+	keyColumn  = "#key"
+)
+
+// Key encodes values to string usable as a map key.
+// Missing values are encoded differently from any other value.
+func Key(vals ...types.DataType) string {
+	buf := &bytes.Buffer{}
+	for _, v := range vals {
+		if v == nil {
+			buf.WriteByte(0)
+			continue
+		}
+
+		b := v.Bytes()
+		buf.WriteByte(1)
+		binary.Write(buf, binary.BigEndian, uint32(len(b)))
+		buf.Write(b)
+	}
+	return buf.String()
+}
+
+// Partition returns partition of key in range [0, n).
+func Partition(key string, n int) int {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return int(h.Sum32() % uint32(n))
+}
+
+// Set deduplicates rows by key. Keys are kept in memory until memory limit
+// is reached, after what new keys can't be checked immediately, so rows
+// with new keys are partitioned to temporary files and deduplicated on flush.
+type Set struct {
+	memLimit int
+	memSize  int
+	keys     map[string]struct{}
+	parts    []*spill.File
+}
+
+func NewSet(memLimit int) *Set {
+	if memLimit <= 0 {
+		memLimit = DefaultMemLimit
+	}
+
+	return &Set{
+		memLimit: memLimit,
+		keys:     map[string]struct{}{},
+	}
+}
+
+// Add adds row by key. Returns row if key is added first time, nil if
+// row is a duplicate or it's check is deferred to Flush.
+func (s *Set) Add(key string, row types.DataRow) (types.DataRow, error) {
+	if _, ok := s.keys[key]; ok {
+		return nil, nil
+	} else if s.parts != nil {
+		cp := make(types.DataRow, len(row)+1)
+		for k, v := range row {
+			cp[k] = v
+		}
+		cp[keyColumn] = types.Type(types.Meta(types.TYPE_STRING)).Set(key)
+		return nil, s.parts[Partition(key, partitions)].Write(cp)
+	}
+
+	s.keys[key] = struct{}{}
+	s.memSize += len(key) + 16
+	if s.memSize >= s.memLimit {
+		return row, s.spill()
+	}
+	return row, nil
+}
+
+// Flush calls fn for every unique row which check was deferred.
+// Stops if fn returns true.
+func (s *Set) Flush(fn func(row types.DataRow) (stop bool)) error {
+	for _, part := range s.parts {
+		if err := part.Rewind(); err != nil {
+			return err
+		}
+
+		keys := map[string]struct{}{}
+		for row, ok, err := part.Next(); ok || err != nil; row, ok, err = part.Next() {
+			if err != nil {
+				return err
+			}
+
+			key := row[keyColumn].Value().(string)
+			delete(row, keyColumn)
+			if _, ok := keys[key]; ok {
+				continue
+			}
+
+			keys[key] = struct{}{}
+			if fn(row) {
+				return nil
+			}
+		}
+	}
+	return nil
+}
+
+// Close removes all temporary files.
+func (s *Set) Close() {
+	for _, part := range s.parts {
+		part.Close()
+	}
+	s.parts, s.keys = nil, nil
+}
+
+func (s *Set) spill() error {
+	s.parts = make([]*spill.File, partitions)
+	for i := range s.parts {
+		var err error
+		if s.parts[i], err = spill.New(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
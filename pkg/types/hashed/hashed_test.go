@@ -0,0 +1,41 @@
+package hashed
+
+import (
+	"testing"
+
+	"go-dbms/pkg/types"
+
+	"github.com/stretchr/testify/require"
+)
+
+func intVal(n int) types.DataType {
+	return types.Type(types.Meta(types.TYPE_INTEGER, true, 4, false)).Set(int32(n))
+}
+
+func TestSet(t *testing.T) {
+	for _, memLimit := range []int{DefaultMemLimit, 256} {
+		s := NewSet(memLimit)
+
+		res := map[int32]int{}
+		for i := 0; i < 200; i++ {
+			row, err := s.Add(Key(intVal(i%70)), types.DataRow{"v": intVal(i % 70)})
+			require.NoError(t, err)
+			if row != nil {
+				res[row["v"].Value().(int32)]++
+			}
+		}
+
+		require.NoError(t, s.Flush(func(row types.DataRow) bool {
+			_, hasKey := row[keyColumn]
+			require.False(t, hasKey)
+			res[row["v"].Value().(int32)]++
+			return false
+		}))
+		s.Close()
+
+		require.Len(t, res, 70)
+		for v, n := range res {
+			require.Equal(t, 1, n, "value %v", v)
+		}
+	}
+}
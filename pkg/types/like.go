@@ -0,0 +1,76 @@
+package types
+
+import (
+	"bytes"
+	"strings"
+	"unicode/utf8"
+)
+
+// like reports whether s matches SQL pattern, where '%' matches any sequence
+// of characters and '_' matches exactly one character.
+func like(s, pattern []byte, fold bool) bool {
+	if fold {
+		s, pattern = bytes.ToLower(s), bytes.ToLower(pattern)
+	}
+
+	// position in s and pattern to backtrack to after last '%'
+	starS, starP := -1, -1
+	i, j := 0, 0
+	for i < len(s) {
+		if j < len(pattern) && pattern[j] == '%' {
+			starS, starP = i, j
+			j++
+			continue
+		}
+
+		if j < len(pattern) {
+			_, sSize := utf8.DecodeRune(s[i:])
+			if pattern[j] == '_' {
+				i, j = i+sSize, j+1
+				continue
+			}
+
+			_, pSize := utf8.DecodeRune(pattern[j:])
+			if bytes.Equal(s[i:i+sSize], pattern[j:j+pSize]) {
+				i, j = i+sSize, j+pSize
+				continue
+			}
+		}
+
+		if starP == -1 {
+			return false
+		}
+
+		_, size := utf8.DecodeRune(s[starS:])
+		starS += size
+		i, j = starS, starP+1
+	}
+
+	for j < len(pattern) && pattern[j] == '%' {
+		j++
+	}
+	return j == len(pattern)
+}
+
+// LikePrefix returns constant prefix of pattern. ok is true if pattern is
+// a prefix followed by single '%', so it can be matched by range scan.
+func LikePrefix(pattern string) (prefix string, ok bool) {
+	i := strings.IndexAny(pattern, "%_")
+	if i == -1 {
+		return pattern, false
+	}
+	return pattern[:i], pattern[i:] == "%"
+}
+
+// PrefixEnd returns smallest string greater than all strings with
+// given prefix. ok is false if there is no such string.
+func PrefixEnd(prefix string) (end string, ok bool) {
+	b := []byte(prefix)
+	for i := len(b) - 1; i >= 0; i-- {
+		if b[i] < 0xff {
+			b[i]++
+			return string(b[:i+1]), true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,56 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLike(t *testing.T) {
+	cases := []struct {
+		s, pattern string
+		fold, match bool
+	}{
+		{"abc", "abc", false, true},
+		{"abc", "ab", false, false},
+		{"abc", "a%", false, true},
+		{"abc", "%c", false, true},
+		{"abc", "%b%", false, true},
+		{"abc", "a_c", false, true},
+		{"abc", "a__c", false, false},
+		{"abc", "%", false, true},
+		{"", "%", false, true},
+		{"", "_", false, false},
+		{"aXbXc", "a%b%c", false, true},
+		{"abcbd", "a%bd", false, true},
+		{"ABC", "a%", false, false},
+		{"ABC", "a%", true, true},
+		{"Ünï", "_n_", false, true},
+	}
+
+	for _, c := range cases {
+		require.Equal(t, c.match, like([]byte(c.s), []byte(c.pattern), c.fold), "%q LIKE %q", c.s, c.pattern)
+	}
+}
+
+func TestLikePrefix(t *testing.T) {
+	prefix, ok := LikePrefix("ab%")
+	require.True(t, ok)
+	require.Equal(t, "ab", prefix)
+
+	_, ok = LikePrefix("ab%c")
+	require.False(t, ok)
+	_, ok = LikePrefix("a_")
+	require.False(t, ok)
+
+	end, ok := PrefixEnd("ab")
+	require.True(t, ok)
+	require.Equal(t, "ac", end)
+
+	end, ok = PrefixEnd("a\xff")
+	require.True(t, ok)
+	require.Equal(t, "b", end)
+
+	_, ok = PrefixEnd("\xff")
+	require.False(t, ok)
+}
@@ -10,13 +10,20 @@ type HeapItem[T interface{}] struct {
 }
 
 type Heap[T interface{}] struct {
-	Keys []string
-	list []*HeapItem[T]
+	Keys    []string
+	Reverse bool
+	list    []*HeapItem[T]
 }
 
 // heap.Interface implementation
 func (h *Heap[T]) Len() int                { return len(h.list) }
-func (h *Heap[T]) Less(i, j int) bool      { return h.list[i].Key.Compare(h.list[j].Key, h.Keys) < 0 }
+func (h *Heap[T]) Less(i, j int) bool {
+	cmp := h.list[i].Key.Compare(h.list[j].Key, h.Keys)
+	if h.Reverse {
+		return cmp > 0
+	}
+	return cmp < 0
+}
 func (h *Heap[T]) Swap(i, j int)           { h.list[i], h.list[j] = h.list[j], h.list[i] }
 func (h *Heap[T]) Push(x interface{})      { h.list = append(h.list, x.(*HeapItem[T])) }
 func (h *Heap[T]) Pop() (last interface{}) { last, h.list = h.list[len(h.list)-1], h.list[:len(h.list)-1]; return }
@@ -0,0 +1,160 @@
+package sorted
+
+import (
+	"container/heap"
+	"slices"
+
+	"go-dbms/pkg/types"
+	"go-dbms/pkg/types/spill"
+	"go-dbms/util/stream"
+)
+
+// DefaultMemLimit is the amount of memory in bytes sorter can use before
+// spilling rows to disk.
+const DefaultMemLimit = 32 << 20
+
+// Sorter implements external merge sort of rows. Rows are collected in memory
+// until memory limit is reached, after what collected rows are sorted and
+// written to temporary file as a sorted run. On flush all runs are k-way merged.
+type Sorter struct {
+	cmp      func(a, b types.DataRow) int
+	memLimit int
+	memSize  int
+	buf      []types.DataRow
+	runs     []*spill.File
+}
+
+func NewSorter(cmp func(a, b types.DataRow) int, memLimit int) *Sorter {
+	if memLimit <= 0 {
+		memLimit = DefaultMemLimit
+	}
+
+	return &Sorter{
+		cmp:      cmp,
+		memLimit: memLimit,
+		buf:      []types.DataRow{},
+		runs:     []*spill.File{},
+	}
+}
+
+// Add adds row to sorter, spills collected rows to disk if memory limit exceeded.
+func (s *Sorter) Add(row types.DataRow) error {
+	s.buf = append(s.buf, row)
+	s.memSize += row.MemSize()
+	if s.memSize >= s.memLimit {
+		return s.spill()
+	}
+	return nil
+}
+
+// Runs returns count of sorted runs spilled to disk.
+func (s *Sorter) Runs() int {
+	return len(s.runs)
+}
+
+// Flush pushes all added rows to dst in sorted order. Stops
+// pushing if dst doesn't want to continue.
+func (s *Sorter) Flush(dst stream.WriterContinue[types.DataRow]) error {
+	if len(s.runs) == 0 {
+		slices.SortStableFunc(s.buf, s.cmp)
+		for _, row := range s.buf {
+			dst.Push(row)
+			if !dst.ShouldContinue() {
+				break
+			}
+		}
+		s.buf = nil
+		return nil
+	}
+
+	if len(s.buf) > 0 {
+		if err := s.spill(); err != nil {
+			return err
+		}
+	}
+
+	h := &mergeHeap{cmp: s.cmp}
+	for i, r := range s.runs {
+		if err := r.Rewind(); err != nil {
+			return err
+		}
+
+		row, ok, err := r.Next()
+		if err != nil {
+			return err
+		} else if ok {
+			heap.Push(h, &mergeItem{row: row, run: i})
+		}
+	}
+
+	for h.Len() > 0 {
+		itm := heap.Pop(h).(*mergeItem)
+		dst.Push(itm.row)
+		if !dst.ShouldContinue() {
+			break
+		}
+
+		row, ok, err := s.runs[itm.run].Next()
+		if err != nil {
+			return err
+		} else if ok {
+			heap.Push(h, &mergeItem{row: row, run: itm.run})
+		}
+	}
+
+	return nil
+}
+
+// Close removes all temporary files.
+func (s *Sorter) Close() {
+	for _, r := range s.runs {
+		r.Close()
+	}
+	s.runs = nil
+	s.buf = nil
+}
+
+func (s *Sorter) spill() error {
+	slices.SortStableFunc(s.buf, s.cmp)
+
+	r, err := spill.New()
+	if err != nil {
+		return err
+	}
+	s.runs = append(s.runs, r)
+
+	for _, row := range s.buf {
+		if err := r.Write(row); err != nil {
+			return err
+		}
+	}
+
+	s.buf = s.buf[:0]
+	s.memSize = 0
+	return nil
+}
+
+type mergeItem struct {
+	row types.DataRow
+	run int
+}
+
+type mergeHeap struct {
+	cmp  func(a, b types.DataRow) int
+	list []*mergeItem
+}
+
+// heap.Interface implementation
+func (h *mergeHeap) Len() int { return len(h.list) }
+func (h *mergeHeap) Less(i, j int) bool {
+	if c := h.cmp(h.list[i].row, h.list[j].row); c != 0 {
+		return c < 0
+	}
+	return h.list[i].run < h.list[j].run // keeps sort stable
+}
+func (h *mergeHeap) Swap(i, j int)      { h.list[i], h.list[j] = h.list[j], h.list[i] }
+func (h *mergeHeap) Push(x interface{}) { h.list = append(h.list, x.(*mergeItem)) }
+func (h *mergeHeap) Pop() (last interface{}) {
+	last, h.list = h.list[len(h.list)-1], h.list[:len(h.list)-1]
+	return
+}
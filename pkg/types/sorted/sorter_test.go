@@ -0,0 +1,42 @@
+package sorted
+
+import (
+	"math/rand"
+	"testing"
+
+	"go-dbms/pkg/types"
+	"go-dbms/util/stream"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSorterSpill(t *testing.T) {
+	cmp := func(a, b types.DataRow) int {
+		return a["n"].Compare(b["n"])
+	}
+
+	s := NewSorter(cmp, 256)
+	defer s.Close()
+
+	for _, n := range rand.Perm(100) {
+		row := types.DataRow{"n": types.Type(types.Meta(types.TYPE_INTEGER, true, 4, true)).Set(int32(n))}
+		require.NoError(t, s.Add(row))
+	}
+	require.Greater(t, s.Runs(), 1)
+
+	dst := stream.New[types.DataRow](1)
+	errCh := make(chan error, 1)
+	go func() {
+		defer dst.Close()
+		errCh <- s.Flush(dst)
+	}()
+
+	expected := int32(0)
+	for row, ok := dst.Pop(); ok; row, ok = dst.Pop() {
+		dst.Continue(true)
+		require.Equal(t, expected, row["n"].Value())
+		expected++
+	}
+	require.NoError(t, <-errCh)
+	require.Equal(t, int32(100), expected)
+}
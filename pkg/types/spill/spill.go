@@ -0,0 +1,81 @@
+package spill
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"os"
+
+	"go-dbms/pkg/types"
+
+	"github.com/pkg/errors"
+)
+
+// File is a temporary file of rows, used by operators which
+// can't keep all rows in memory.
+type File struct {
+	f *os.File
+	w *bufio.Writer
+	r *bufio.Reader
+}
+
+func New() (*File, error) {
+	f, err := os.CreateTemp("", "go-dbms-spill-*")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create spill file")
+	}
+
+	return &File{f: f, w: bufio.NewWriter(f)}, nil
+}
+
+func (f *File) Write(row types.DataRow) error {
+	data, err := row.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	if err := binary.Write(f.w, binary.BigEndian, uint32(len(data))); err != nil {
+		return err
+	}
+	_, err = f.w.Write(data)
+	return err
+}
+
+// Rewind flushes written rows and moves cursor to the first row.
+func (f *File) Rewind() error {
+	if err := f.w.Flush(); err != nil {
+		return err
+	}
+	if _, err := f.f.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	f.r = bufio.NewReader(f.f)
+	return nil
+}
+
+// Next reads next row, returns false if there are no rows left.
+func (f *File) Next() (types.DataRow, bool, error) {
+	var size uint32
+	if err := binary.Read(f.r, binary.BigEndian, &size); err == io.EOF {
+		return nil, false, nil
+	} else if err != nil {
+		return nil, false, err
+	}
+
+	data := make([]byte, size)
+	if _, err := io.ReadFull(f.r, data); err != nil {
+		return nil, false, err
+	}
+
+	row := types.DataRow{}
+	if err := row.UnmarshalBinary(data); err != nil {
+		return nil, false, err
+	}
+	return row, true, nil
+}
+
+// Close closes and removes file.
+func (f *File) Close() {
+	f.f.Close()
+	os.Remove(f.f.Name())
+}
@@ -128,6 +128,10 @@ func (t *DataTypeSTRING) CompareOp(operator Operator, val DataType) bool {
 		case Greater:        return t.Compare(val) > 0
 		case Less:           return t.Compare(val) < 0
 		case NotEqual:       return t.Compare(val) != 0
+		case Like:           return like(t.Bytes(), val.Bytes(), false)
+		case NotLike:        return !like(t.Bytes(), val.Bytes(), false)
+		case ILike:          return like(t.Bytes(), val.Bytes(), true)
+		case NotILike:       return !like(t.Bytes(), val.Bytes(), true)
 	}
 	panic(fmt.Errorf("invalid operator:'%s'", operator))
 }
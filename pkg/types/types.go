@@ -30,6 +30,16 @@ const (
 	Greater        Operator = ">"
 	Less           Operator = "<"
 	NotEqual       Operator = "!="
+	In             Operator = "IN"
+	NotIn          Operator = "NOT IN"
+	Between        Operator = "BETWEEN"
+	NotBetween     Operator = "NOT BETWEEN"
+	Like           Operator = "LIKE"
+	NotLike        Operator = "NOT LIKE"
+	ILike          Operator = "ILIKE"
+	NotILike       Operator = "NOT ILIKE"
+	IsNull         Operator = "IS NULL"
+	IsNotNull      Operator = "IS NOT NULL"
 )
 
 type newable struct {
@@ -77,7 +87,7 @@ type DataSeq []DataType
 
 func (dr DataRow) Compare(dr2 DataRow, keys []string) int {
 	for _, col := range keys {
-		cmpVal := dr[col].Compare(dr2[col])
+		cmpVal := Compare(dr[col], dr2[col])
 		switch cmpVal {
 			case -1, 1: return cmpVal
 		}
@@ -85,6 +95,16 @@ func (dr DataRow) Compare(dr2 DataRow, keys []string) int {
 	return 0
 }
 
+// Compare compares values, NULL (nil) values are less than any other.
+func Compare(a, b DataType) int {
+	switch {
+		case a == nil && b == nil: return 0
+		case a == nil:             return -1
+		case b == nil:             return 1
+		default:                   return a.Compare(b)
+	}
+}
+
 func Type(meta DataTypeMeta) DataType {
 	return typesMap[meta.GetCode()].newInstance(meta)
 }
@@ -104,6 +124,9 @@ func ParseJSONValue(item interface{}) DataType {
 		case string: {
 			return Type(Meta(TYPE_STRING)).Set(v)
 		}
+		case nil: {
+			return nil
+		}
 		default: {
 			panic(errors.New("invalid item type"))
 		}
@@ -127,3 +150,20 @@ func Parse(tokens []string) DataTypeMeta {
 	}
 	return parsers[typeName](tokens)
 }
+
+// Name returns type as it's written in column definition, which is parsed
+// by Parse. AUTO INCREMENT of integer type isn't included.
+func Name(meta DataTypeMeta) string {
+	switch m := meta.(type) {
+		case *DataTypeINTEGERMeta:
+			if m.Signed {
+				return fmt.Sprintf("Int%d", m.ByteSize*8)
+			}
+			return fmt.Sprintf("UInt%d", m.ByteSize*8)
+		case *DataTypeFLOATMeta:    return fmt.Sprintf("Float%d", m.ByteSize*8)
+		case *DataTypeVARCHARMeta:  return fmt.Sprintf("VARCHAR(%d)", m.Cap)
+		case *DataTypeSTRINGMeta:   return "STRING"
+		case *DataTypeDATETIMEMeta: return "DATETIME"
+	}
+	panic(fmt.Errorf("unknown type code: %d", meta.GetCode()))
+}
@@ -0,0 +1,22 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestName(t *testing.T) {
+	tests := map[string][]string{
+		"UInt8":       {"UInt8"},
+		"Int64":       {"Int64", "AUTO", "INCREMENT"},
+		"Float32":     {"Float32"},
+		"VARCHAR(20)": {"VARCHAR", "(", "20", ")"},
+		"STRING":      {"STRING"},
+		"DATETIME":    {"DATETIME"},
+	}
+
+	for name, tokens := range tests {
+		require.Equal(t, name, Name(Parse(tokens)))
+	}
+}
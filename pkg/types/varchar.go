@@ -165,6 +165,10 @@ func (t *DataTypeVARCHAR) CompareOp(operator Operator, val DataType) bool {
 		case Greater:        return t.Compare(val) > 0
 		case Less:           return t.Compare(val) < 0
 		case NotEqual:       return t.Compare(val) != 0
+		case Like:           return like(t.Bytes(), val.Bytes(), false)
+		case NotLike:        return !like(t.Bytes(), val.Bytes(), false)
+		case ILike:          return like(t.Bytes(), val.Bytes(), true)
+		case NotILike:       return !like(t.Bytes(), val.Bytes(), true)
 	}
 	panic(fmt.Errorf("invalid operator:'%s'", operator))
 }
@@ -179,7 +183,7 @@ func (t *DataTypeVARCHAR) Cast(meta DataTypeMeta) (DataType, error) {
 					ByteSize: 4,
 				}
 			}
-			number, _ := strconv.Atoi(string(t.value))
+			number, _ := strconv.Atoi(string(t.Bytes()))
 			return Type(meta).Set(number), nil
 		}
 		case TYPE_STRING, TYPE_VARCHAR: {
@@ -202,13 +206,13 @@ func (t *DataTypeVARCHAR) Cast(meta DataTypeMeta) (DataType, error) {
 					ByteSize: 8,
 				}
 			}
-			return Type(meta).Set(helpers.MustVal(strconv.ParseFloat(string(t.value), 64))), nil
+			return Type(meta).Set(helpers.MustVal(strconv.ParseFloat(string(t.Bytes()), 64))), nil
 		}
 		case TYPE_DATETIME: {
 			if meta == nil {
 				meta = &DataTypeDATETIMEMeta{}
 			}
-			return Type(meta).Set(string(t.value)), nil
+			return Type(meta).Set(string(t.Bytes())), nil
 		}
 	}
 
@@ -0,0 +1,19 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVarcharCast(t *testing.T) {
+	v := Type(Meta(TYPE_VARCHAR, 10)).Set("42")
+
+	i, err := v.Cast(Meta(TYPE_INTEGER, true, 8, false))
+	require.NoError(t, err)
+	require.Equal(t, Type(Meta(TYPE_INTEGER, true, 8, false)).Set(42).Bytes(), i.Bytes())
+
+	f, err := v.Cast(Meta(TYPE_FLOAT, 8))
+	require.NoError(t, err)
+	require.Equal(t, 42.0, f.Value())
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"go-dbms/pkg/pipe"
+	"go-dbms/services/parser/query"
 	"go-dbms/util/response"
 
 	"github.com/pkg/errors"
@@ -16,6 +17,12 @@ import (
 
 type Connection struct {
 	Conn net.Conn
+
+	// database of unqualified table names, selected by USE query
+	Database string
+
+	statements map[uint32]*query.Prepared
+	lastHandle uint32
 }
 
 func (c *Connection) Auth(
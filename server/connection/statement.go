@@ -0,0 +1,92 @@
+package connection
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+
+	"go-dbms/pkg/types"
+	"go-dbms/services/parser/query"
+	"go-dbms/util/helpers"
+
+	"github.com/pkg/errors"
+)
+
+// Commands of prepared statements protocol. Message starting with one of
+// command bytes is command, any other message is text of query.
+//
+//	PREPARE: CmdPrepare <query text>, responds with [<handle>, <count of parameters>]
+//	EXECUTE: CmdExecute <uint32 handle> <JSON array of parameters>, responds as query
+//	CLOSE:   CmdClose <uint32 handle>, responds with empty stream
+const (
+	CmdPrepare byte = iota + 1
+	CmdExecute
+	CmdClose
+)
+
+const handleSize = 4
+
+// Prepare caches prepared query and returns handle to execute it.
+func (c *Connection) Prepare(p *query.Prepared) uint32 {
+	if c.statements == nil {
+		c.statements = map[uint32]*query.Prepared{}
+	}
+
+	c.lastHandle++
+	c.statements[c.lastHandle] = p
+	return c.lastHandle
+}
+
+// Statement returns prepared query by handle.
+func (c *Connection) Statement(handle uint32) (*query.Prepared, error) {
+	p, ok := c.statements[handle]
+	if !ok {
+		return nil, fmt.Errorf("prepared statement not found: %d", handle)
+	}
+	return p, nil
+}
+
+// CloseStatement removes prepared query from cache.
+func (c *Connection) CloseStatement(handle uint32) error {
+	if _, ok := c.statements[handle]; !ok {
+		return fmt.Errorf("prepared statement not found: %d", handle)
+	}
+	delete(c.statements, handle)
+	return nil
+}
+
+func (c *Connection) SendPrepared(handle uint32, p *query.Prepared) error {
+	_, err := c.Send(helpers.MustVal(json.Marshal([]any{handle, p.NumParams()})))
+	if err == nil {
+		err = c.EOS()
+	}
+	return err
+}
+
+// ParseHandle reads statement handle from payload of EXECUTE or CLOSE command,
+// rest of payload is returned.
+func ParseHandle(payload []byte) (handle uint32, rest []byte, err error) {
+	if len(payload) < handleSize {
+		return 0, nil, errors.New("invalid statement handle")
+	}
+	return binary.BigEndian.Uint32(payload[:handleSize]), payload[handleSize:], nil
+}
+
+// ParseParams decodes JSON array of parameters. Numbers and strings are
+// converted same way as query literals, null is bound as NULL.
+func ParseParams(data []byte) (params []types.DataType, err error) {
+	defer helpers.RecoverOnError(&err)()
+
+	vals := []interface{}{}
+	if len(data) != 0 {
+		if err := json.Unmarshal(data, &vals); err != nil {
+			return nil, errors.Wrap(err, "invalid parameters")
+		}
+	}
+
+	params = make([]types.DataType, len(vals))
+	for i, val := range vals {
+		params[i] = types.ParseJSONValue(val)
+	}
+	return params, nil
+}
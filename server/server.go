@@ -1,12 +1,13 @@
 package server
 
 import (
-	"bytes"
+	"cmp"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net"
-	"text/scanner"
+	"slices"
+	"sync"
 	"time"
 
 	"go-dbms/config"
@@ -15,6 +16,8 @@ import (
 	"go-dbms/server/connection"
 	"go-dbms/services/auth"
 	"go-dbms/services/executor"
+	"go-dbms/services/executor/parent"
+	"go-dbms/services/executor/system"
 	"go-dbms/services/parser"
 	"go-dbms/services/parser/query"
 	"go-dbms/util/helpers"
@@ -32,6 +35,11 @@ type Server struct {
 	executorService *executor.ExecutorService
 
 	listen *net.TCPListener
+
+	// authed connections, shown by system.connections
+	connsMu    *sync.Mutex
+	conns      map[*connection.Connection]*system.Connection
+	lastConnID uint64
 }
 
 func New(
@@ -46,7 +54,10 @@ func New(
 		authService: authService,
 		parserService: parserService,
 		executorService: executorService,
+		connsMu: &sync.Mutex{},
+		conns: map[*connection.Connection]*system.Connection{},
 	}
+	executorService.SetConnections(s.connections)
 
 	url := fmt.Sprintf("%v:%v", configs.Host, configs.Port)
 	addr, err := net.ResolveTCPAddr(PROTOCOL, url)
@@ -116,6 +127,9 @@ func (s *Server) handleConnection(c *connection.Connection) {
 	fmt.Println("client authed!")
 	c.SendAuthSuccess()
 
+	s.addConnection(c)
+	defer s.removeConnection(c)
+
 	for {
 		buf, err := req.ReadLine()
 		if err != nil {
@@ -131,16 +145,65 @@ func (s *Server) handleConnection(c *connection.Connection) {
 		}
 
 		start := time.Now()
-		sc := &scanner.Scanner{}
-		sc.Init(bytes.NewReader(buf))
-		sc.Scan()
-		q, err := s.parserService.ParseQuery(sc)
-		if err != nil {
-			err = c.SendSyntaxError(err)
-			if err != nil {
-				fmt.Println("[Parse] unexpected error while responding:", err)
-			}
-			break
+		var cmd byte
+		if len(buf) > 0 {
+			cmd = buf[0]
+		}
+
+		var q query.Querier
+		switch cmd {
+			case connection.CmdPrepare:
+				p, err := s.parserService.Use(c.Database).Prepare(buf[1:])
+				if err != nil {
+					err = c.SendSyntaxError(err)
+					if err != nil {
+						fmt.Println("[Prepare] unexpected error while responding:", err)
+					}
+					return
+				}
+
+				if err := c.SendPrepared(c.Prepare(p), p); err != nil {
+					fmt.Println("[Prepare] unexpected error while responding:", err)
+					return
+				}
+				continue
+
+			case connection.CmdExecute:
+				p, err := s.bind(c, buf[1:])
+				if err != nil {
+					err = c.SendError(err)
+					if err != nil {
+						fmt.Println("[Execute] unexpected error while responding:", err)
+					}
+					return
+				}
+				q = p.Querier
+
+			case connection.CmdClose:
+				handle, _, err := connection.ParseHandle(buf[1:])
+				if err == nil {
+					err = c.CloseStatement(handle)
+				}
+				if err != nil {
+					err = c.SendError(err)
+				} else {
+					err = c.EOS()
+				}
+				if err != nil {
+					fmt.Println("[Close] unexpected error while responding:", err)
+					return
+				}
+				continue
+
+			default:
+				q, err = s.parserService.Use(c.Database).Parse(buf)
+				if err != nil {
+					err = c.SendSyntaxError(err)
+					if err != nil {
+						fmt.Println("[Parse] unexpected error while responding:", err)
+					}
+					return
+				}
 		}
 
 		r, pr, err := s.executorService.Exec(q)
@@ -153,6 +216,11 @@ func (s *Server) handleConnection(c *connection.Connection) {
 			break
 		}
 
+		if qu, ok := q.(*query.QueryUse); ok {
+			c.Database = qu.DB
+		}
+		s.trackQuery(c)
+
 		p := pipe.NewPipe(nil)
 		go func ()  {
 			if r != nil {
@@ -182,3 +250,70 @@ func (s *Server) handleConnection(c *connection.Connection) {
 		fmt.Printf("Duration %v\n", time.Since(start))
 	}
 }
+
+// bind finds prepared query by handle of EXECUTE command and binds parameters to it.
+func (s *Server) bind(c *connection.Connection, payload []byte) (*query.Prepared, error) {
+	handle, data, err := connection.ParseHandle(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := c.Statement(handle)
+	if err != nil {
+		return nil, err
+	}
+
+	params, err := connection.ParseParams(data)
+	if err != nil {
+		return nil, err
+	}
+	return p, p.Bind(params)
+}
+
+// addConnection registers authed connection.
+func (s *Server) addConnection(c *connection.Connection) {
+	s.connsMu.Lock()
+	defer s.connsMu.Unlock()
+
+	s.lastConnID++
+	s.conns[c] = &system.Connection{
+		ID:        s.lastConnID,
+		Addr:      c.Conn.RemoteAddr().String(),
+		Database:  parent.DefaultDatabase,
+		Connected: time.Now(),
+	}
+}
+
+func (s *Server) removeConnection(c *connection.Connection) {
+	s.connsMu.Lock()
+	defer s.connsMu.Unlock()
+	delete(s.conns, c)
+}
+
+// trackQuery counts executed query of connection and
+// updates its database, which may be changed by USE.
+func (s *Server) trackQuery(c *connection.Connection) {
+	s.connsMu.Lock()
+	defer s.connsMu.Unlock()
+
+	info := s.conns[c]
+	info.Queries++
+	if c.Database != "" {
+		info.Database = c.Database
+	}
+}
+
+// connections returns copies of states of connections ordered by ID.
+func (s *Server) connections() []system.Connection {
+	s.connsMu.Lock()
+	defer s.connsMu.Unlock()
+
+	conns := make([]system.Connection, 0, len(s.conns))
+	for _, info := range s.conns {
+		conns = append(conns, *info)
+	}
+	slices.SortFunc(conns, func(a, b system.Connection) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+	return conns
+}
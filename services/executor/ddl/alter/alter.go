@@ -0,0 +1,40 @@
+package alter
+
+import (
+	"go-dbms/pkg/table"
+	"go-dbms/pkg/types"
+	"go-dbms/services/executor/parent"
+	"go-dbms/services/parser/query/ddl/alter"
+	"go-dbms/services/parser/query/dml/projection"
+	"go-dbms/util/stream"
+
+	"github.com/pkg/errors"
+)
+
+type DDLAlter struct {
+	*parent.ExecutorService
+}
+
+func New(es *parent.ExecutorService) *DDLAlter {
+	return &DDLAlter{ExecutorService: es}
+}
+
+// Alter changes columns of table, rows aren't rewritten,
+// they're upgraded to new columns on read.
+func (ddl *DDLAlter) Alter(q *alter.QueryAlterTable) (
+	stream.ReaderContinue[types.DataRow],
+	*projection.Projections,
+	error,
+) {
+	if err := ddl.ddlAlterValidate(q); err != nil {
+		return nil, nil, errors.Wrapf(err, "validation error")
+	}
+
+	return nil, nil, ddl.Table(q.DB, q.Table).Alter(&table.Alteration{
+		Action:      q.Action,
+		Name:        q.Name,
+		NewName:     q.NewName,
+		Column:      q.Column,
+		Aggregation: string(q.Aggregation),
+	})
+}
@@ -0,0 +1,42 @@
+package alter
+
+import (
+	"fmt"
+
+	"go-dbms/pkg/table"
+	"go-dbms/services/parser/query/ddl/alter"
+	"go-dbms/services/parser/query/dml/eval"
+)
+
+// ddlAlterValidate validates query and sets default value of column,
+// which is evaluated and casted to column type.
+func (ddl *DDLAlter) ddlAlterValidate(q *alter.QueryAlterTable) error {
+	t, err := ddl.LookupWritableTable(q.DB, q.Table)
+	if err != nil {
+		return err
+	}
+
+	switch q.Action {
+		case table.DROP_COLUMN, table.RENAME_COLUMN:
+			if t.Column(q.Name) == nil {
+				return fmt.Errorf("column not found: '%s'", q.Name)
+			}
+			return nil
+		case table.MODIFY_COLUMN:
+			if t.Column(q.Name) == nil {
+				return fmt.Errorf("column not found: '%s'", q.Name)
+			}
+		case table.ADD_COLUMN:
+			if t.Column(q.Name) != nil {
+				return fmt.Errorf("column already exists: '%s'", q.Name)
+			}
+	}
+
+	if q.Aggregation != "" && t.Engine() != table.AggregatingMergeTree {
+		return fmt.Errorf("AggregateFunction column is allowed only in %s", table.AggregatingMergeTree)
+	} else if q.Default == nil {
+		return nil
+	}
+
+	return q.Column.SetDefault(eval.Eval(nil, q.Default))
+}
@@ -20,18 +20,20 @@ func New(es *parent.ExecutorService) *DDLCreate {
 	return &DDLCreate{ExecutorService: es}
 }
 
-func (ddl *DDLCreate) Create(q create.Creater) (
+func (ddl *DDLCreate) Create(q create.Creater, es parent.Executor) (
 	stream.ReaderContinue[types.DataRow],
 	*projection.Projections,
 	error,
 ) {
-	if err := ddl.ddlCreateValidate(q); err != nil {
+	if err := ddl.ddlCreateValidate(q, es); err != nil {
 		return nil, nil, errors.Wrapf(err, "validation error")
 	}
 
 	switch q.GetTarget() {
-		case create.TABLE: return ddl.CreateTable(q.(*create.QueryCreateTable))
-		case create.INDEX: return ddl.CreateIndex(q.(*create.QueryCreateIndex))
-		default:           panic(fmt.Errorf("invalid create target: '%s'", q.GetTarget()))
+		case create.DATABASE: return ddl.CreateDatabase(q.(*create.QueryCreateDatabase))
+		case create.TABLE:    return ddl.CreateTable(q.(*create.QueryCreateTable))
+		case create.INDEX:    return ddl.CreateIndex(q.(*create.QueryCreateIndex))
+		case create.VIEW:     return ddl.CreateView(q.(*create.QueryCreateView))
+		default:              panic(fmt.Errorf("invalid create target: '%s'", q.GetTarget()))
 	}
 }
@@ -3,13 +3,16 @@ package create
 import (
 	"fmt"
 
+	"go-dbms/services/executor/parent"
 	"go-dbms/services/parser/query/ddl/create"
 )
 
-func (ddl *DDLCreate) ddlCreateValidate(q create.Creater) error {
+func (ddl *DDLCreate) ddlCreateValidate(q create.Creater, es parent.Executor) error {
 	switch q.GetTarget() {
+		case create.DATABASE: return ddl.ddlCreateDatabaseValidate(q.(*create.QueryCreateDatabase))
 		case create.TABLE:    return ddl.ddlCreateTableValidate(q.(*create.QueryCreateTable))
 		case create.INDEX:    return ddl.ddlCreateIndexValidate(q.(*create.QueryCreateIndex))
+		case create.VIEW:     return ddl.ddlCreateViewValidate(q.(*create.QueryCreateView), es)
 		default:              panic(fmt.Errorf("invalid create target: '%s'", q.GetTarget()))
 	}
 }
@@ -0,0 +1,19 @@
+package create
+
+import (
+	"go-dbms/pkg/types"
+	"go-dbms/services/parser/query/ddl/create"
+	"go-dbms/services/parser/query/dml/projection"
+	"go-dbms/util/stream"
+)
+
+func (ddl *DDLCreate) CreateDatabase(q *create.QueryCreateDatabase) (
+	stream.ReaderContinue[types.DataRow],
+	*projection.Projections,
+	error,
+) {
+	if _, ok := ddl.DB(q.Name); ok && q.IfNotExists {
+		return nil, nil, nil
+	}
+	return nil, nil, ddl.ExecutorService.CreateDatabase(q.Name)
+}
@@ -0,0 +1,18 @@
+package create
+
+import (
+	"fmt"
+	"strings"
+
+	"go-dbms/services/parser/query/ddl/create"
+)
+
+func (ddl *DDLCreate) ddlCreateDatabaseValidate(q *create.QueryCreateDatabase) error {
+	// name of database is name of its directory
+	if q.Name == "" || q.Name == "." || q.Name == ".." || strings.ContainsAny(q.Name, `/\`) {
+		return fmt.Errorf("invalid database name: '%s'", q.Name)
+	} else if _, ok := ddl.DB(q.Name); ok && !q.IfNotExists {
+		return fmt.Errorf("database already exists: '%s'", q.Name)
+	}
+	return nil
+}
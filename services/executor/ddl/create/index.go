@@ -12,5 +12,5 @@ func (ddl *DDLCreate) CreateIndex(q *create.QueryCreateIndex) (
 	*projection.Projections,
 	error,
 ) {
-	return nil, nil, ddl.Tables[q.Table].CreateIndex(&q.Name, q.IndexOptions)
+	return nil, nil, ddl.Table(q.DB, q.Table).CreateIndex(&q.Name, q.IndexOptions)
 }
@@ -7,9 +7,9 @@ import (
 )
 
 func (ddl *DDLCreate) ddlCreateIndexValidate(q *create.QueryCreateIndex) error {
-	t, ok := ddl.Tables[q.Table]
-	if !ok {
-		return fmt.Errorf("table not found: '%s'", q.Table)
+	t, err := ddl.LookupWritableTable(q.DB, q.Table)
+	if err != nil {
+		return err
 	}
 
 	if t.HasIndex(q.Name) {
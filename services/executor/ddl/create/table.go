@@ -1,6 +1,7 @@
 package create
 
 import (
+	"fmt"
 	"path/filepath"
 
 	"go-dbms/pkg/engine/aggregatingmergetree"
@@ -20,7 +21,12 @@ func (ddl *DDLCreate) CreateTable(q *create.QueryCreateTable) (
 	*projection.Projections,
 	error,
 ) {
-	tablePath := ddl.TablePath(q.Name)
+	db, ok := ddl.DB(q.Database)
+	if !ok {
+		return nil, nil, fmt.Errorf("database not found: '%s'", q.Database)
+	}
+
+	tablePath := db.TablePath(q.Name)
 	opts := &table.Options{
 		Engine:       q.Engine,
 		Columns:      q.Columns,
@@ -49,7 +55,10 @@ func (ddl *DDLCreate) CreateTable(q *create.QueryCreateTable) (
 		}
 	}
 
-	ddl.Tables[q.Name] = t
+	if err = db.AddTable(q.Name, t); err != nil {
+		t.Drop()
+		return nil, nil, errors.Wrapf(err, "failed to create table: '%s'", q.Name)
+	}
 
 	return nil, nil, nil
 }
@@ -2,13 +2,32 @@ package create
 
 import (
 	"fmt"
+	"slices"
 
+	"go-dbms/pkg/column"
 	"go-dbms/services/parser/query/ddl/create"
+	"go-dbms/services/parser/query/dml/eval"
 )
 
+// ddlCreateTableValidate validates query and sets default values of columns,
+// which are evaluated and casted to column types.
 func (ddl *DDLCreate) ddlCreateTableValidate(q *create.QueryCreateTable) error {
-	if _, ok := ddl.Tables[q.Name]; ok {
+	db, ok := ddl.DB(q.Database)
+	if !ok {
+		return fmt.Errorf("database not found: '%s'", q.Database)
+	} else if err := db.Writable(); err != nil {
+		return err
+	} else if _, ok := db.Tables()[q.Name]; ok {
 		return fmt.Errorf("table already exists")
+	} else if _, ok := db.Views[q.Name]; ok {
+		return fmt.Errorf("view already exists: '%s'", q.Name)
+	}
+
+	for name, p := range q.Defaults {
+		i := slices.IndexFunc(q.Columns, func(c *column.Column) bool { return c.Name == name })
+		if err := q.Columns[i].SetDefault(eval.Eval(nil, p)); err != nil {
+			return err
+		}
 	}
 	return nil
 }
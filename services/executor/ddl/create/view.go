@@ -0,0 +1,30 @@
+package create
+
+import (
+	"fmt"
+
+	"go-dbms/pkg/types"
+	"go-dbms/services/executor/parent"
+	"go-dbms/services/parser/query/ddl/create"
+	"go-dbms/services/parser/query/dml/projection"
+	"go-dbms/util/stream"
+
+	"github.com/pkg/errors"
+)
+
+// CreateView stores text of query of view in catalog of database.
+func (ddl *DDLCreate) CreateView(q *create.QueryCreateView) (
+	stream.ReaderContinue[types.DataRow],
+	*projection.Projections,
+	error,
+) {
+	db, ok := ddl.DB(q.DB)
+	if !ok {
+		return nil, nil, fmt.Errorf("database not found: '%s'", q.DB)
+	} else if _, ok := db.Views[q.Name]; ok && q.IfNotExists {
+		return nil, nil, nil
+	}
+
+	err := db.AddView(q.Name, &parent.View{Query: q.Text, Database: q.Database})
+	return nil, nil, errors.Wrapf(err, "failed to create view: '%s'", q.Name)
+}
@@ -0,0 +1,26 @@
+package create
+
+import (
+	"fmt"
+
+	"go-dbms/services/executor/parent"
+	"go-dbms/services/parser/query/ddl/create"
+)
+
+// ddlCreateViewValidate checks that name of view is free and validates
+// its query, so view referencing not existing tables or columns isn't created.
+func (ddl *DDLCreate) ddlCreateViewValidate(q *create.QueryCreateView, es parent.Executor) error {
+	db, ok := ddl.DB(q.DB)
+	if !ok {
+		return fmt.Errorf("database not found: '%s'", q.DB)
+	} else if err := db.Writable(); err != nil {
+		return err
+	} else if _, ok := db.Tables()[q.Name]; ok {
+		return fmt.Errorf("table already exists: '%s'", q.Name)
+	} else if _, ok := db.Views[q.Name]; ok && q.IfNotExists {
+		return nil
+	} else if ok {
+		return fmt.Errorf("view already exists: '%s'", q.Name)
+	}
+	return es.Validate(q.Query)
+}
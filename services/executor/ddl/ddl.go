@@ -2,19 +2,37 @@ package ddl
 
 import (
 	"go-dbms/pkg/types"
+	"go-dbms/services/executor/ddl/alter"
 	"go-dbms/services/executor/ddl/create"
+	"go-dbms/services/executor/ddl/drop"
+	"go-dbms/services/executor/ddl/rename"
+	"go-dbms/services/executor/ddl/truncate"
 	"go-dbms/services/executor/parent"
+	palter "go-dbms/services/parser/query/ddl/alter"
 	pcreate "go-dbms/services/parser/query/ddl/create"
+	pdrop "go-dbms/services/parser/query/ddl/drop"
+	prename "go-dbms/services/parser/query/ddl/rename"
+	ptruncate "go-dbms/services/parser/query/ddl/truncate"
 	"go-dbms/services/parser/query/dml/projection"
 	"go-dbms/util/stream"
 )
 
 type DDL struct {
-	create *create.DDLCreate
+	create   *create.DDLCreate
+	alter    *alter.DDLAlter
+	drop     *drop.DDLDrop
+	truncate *truncate.DDLTruncate
+	rename   *rename.DDLRename
 }
 
 func New(es *parent.ExecutorService) *DDL {
-	return &DDL{create: create.New(es)}
+	return &DDL{
+		create:   create.New(es),
+		alter:    alter.New(es),
+		drop:     drop.New(es),
+		truncate: truncate.New(es),
+		rename:   rename.New(es),
+	}
 }
 
 func (ddl *DDL) Create(q pcreate.Creater, es parent.Executor) (
@@ -22,5 +40,37 @@ func (ddl *DDL) Create(q pcreate.Creater, es parent.Executor) (
 	*projection.Projections,
 	error,
 ) {
-	return ddl.create.Create(q)
+	return ddl.create.Create(q, es)
+}
+
+func (ddl *DDL) Alter(q *palter.QueryAlterTable, es parent.Executor) (
+	stream.ReaderContinue[types.DataRow],
+	*projection.Projections,
+	error,
+) {
+	return ddl.alter.Alter(q)
+}
+
+func (ddl *DDL) Drop(q pdrop.Dropper, es parent.Executor) (
+	stream.ReaderContinue[types.DataRow],
+	*projection.Projections,
+	error,
+) {
+	return ddl.drop.Drop(q)
+}
+
+func (ddl *DDL) Truncate(q *ptruncate.QueryTruncateTable, es parent.Executor) (
+	stream.ReaderContinue[types.DataRow],
+	*projection.Projections,
+	error,
+) {
+	return ddl.truncate.Truncate(q)
+}
+
+func (ddl *DDL) Rename(q *prename.QueryRenameTable, es parent.Executor) (
+	stream.ReaderContinue[types.DataRow],
+	*projection.Projections,
+	error,
+) {
+	return ddl.rename.Rename(q)
 }
@@ -0,0 +1,21 @@
+package drop
+
+import (
+	"go-dbms/pkg/types"
+	"go-dbms/services/parser/query/ddl/drop"
+	"go-dbms/services/parser/query/dml/projection"
+	"go-dbms/util/stream"
+)
+
+// DropDatabase removes database from executor first, so new queries
+// can't find it, then drops its tables and removes its directory.
+func (ddl *DDLDrop) DropDatabase(q *drop.QueryDropDatabase) (
+	stream.ReaderContinue[types.DataRow],
+	*projection.Projections,
+	error,
+) {
+	if db, ok := ddl.RemoveDatabase(q.DB); ok {
+		return nil, nil, db.Drop()
+	}
+	return nil, nil, nil
+}
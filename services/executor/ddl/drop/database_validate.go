@@ -0,0 +1,22 @@
+package drop
+
+import (
+	"fmt"
+
+	"go-dbms/services/executor/parent"
+	"go-dbms/services/parser/query/ddl/drop"
+)
+
+func (ddl *DDLDrop) ddlDropDatabaseValidate(q *drop.QueryDropDatabase) error {
+	if q.DB == parent.DefaultDatabase {
+		return fmt.Errorf("default database can't be dropped")
+	}
+
+	db, ok := ddl.DB(q.DB)
+	if !ok && !q.IfExists {
+		return fmt.Errorf("database not found: '%s'", q.DB)
+	} else if ok {
+		return db.Writable()
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package drop
+
+import (
+	"fmt"
+
+	"go-dbms/pkg/types"
+	"go-dbms/services/executor/parent"
+	"go-dbms/services/parser/query/ddl/drop"
+	"go-dbms/services/parser/query/dml/projection"
+	"go-dbms/util/stream"
+
+	"github.com/pkg/errors"
+)
+
+type DDLDrop struct {
+	*parent.ExecutorService
+}
+
+func New(es *parent.ExecutorService) *DDLDrop {
+	return &DDLDrop{ExecutorService: es}
+}
+
+func (ddl *DDLDrop) Drop(q drop.Dropper) (
+	stream.ReaderContinue[types.DataRow],
+	*projection.Projections,
+	error,
+) {
+	if err := ddl.ddlDropValidate(q); err != nil {
+		return nil, nil, errors.Wrapf(err, "validation error")
+	}
+
+	switch q.GetTarget() {
+		case drop.DATABASE: return ddl.DropDatabase(q.(*drop.QueryDropDatabase))
+		case drop.TABLE:    return ddl.DropTable(q.(*drop.QueryDropTable))
+		case drop.INDEX:    return ddl.DropIndex(q.(*drop.QueryDropIndex))
+		case drop.VIEW:     return ddl.DropView(q.(*drop.QueryDropView))
+		default:            panic(fmt.Errorf("invalid drop target: '%s'", q.GetTarget()))
+	}
+}
@@ -0,0 +1,17 @@
+package drop
+
+import (
+	"fmt"
+
+	"go-dbms/services/parser/query/ddl/drop"
+)
+
+func (ddl *DDLDrop) ddlDropValidate(q drop.Dropper) error {
+	switch q.GetTarget() {
+		case drop.DATABASE: return ddl.ddlDropDatabaseValidate(q.(*drop.QueryDropDatabase))
+		case drop.TABLE:    return ddl.ddlDropTableValidate(q.(*drop.QueryDropTable))
+		case drop.INDEX:    return ddl.ddlDropIndexValidate(q.(*drop.QueryDropIndex))
+		case drop.VIEW:     return ddl.ddlDropViewValidate(q.(*drop.QueryDropView))
+		default:            panic(fmt.Errorf("invalid drop target: '%s'", q.GetTarget()))
+	}
+}
@@ -0,0 +1,16 @@
+package drop
+
+import (
+	"go-dbms/pkg/types"
+	"go-dbms/services/parser/query/ddl/drop"
+	"go-dbms/services/parser/query/dml/projection"
+	"go-dbms/util/stream"
+)
+
+func (ddl *DDLDrop) DropIndex(q *drop.QueryDropIndex) (
+	stream.ReaderContinue[types.DataRow],
+	*projection.Projections,
+	error,
+) {
+	return nil, nil, ddl.Table(q.DB, q.Table).DropIndex(q.Index)
+}
@@ -0,0 +1,22 @@
+package drop
+
+import (
+	"fmt"
+
+	"go-dbms/services/parser/query/ddl/drop"
+)
+
+func (ddl *DDLDrop) ddlDropIndexValidate(q *drop.QueryDropIndex) error {
+	t, err := ddl.LookupWritableTable(q.DB, q.Table)
+	if err != nil {
+		return err
+	}
+
+	if !t.HasIndex(q.Index) {
+		return fmt.Errorf("index not found: '%s'", q.Index)
+	} else if t.PrimaryKey() == q.Index {
+		return fmt.Errorf("primary index can't be dropped: '%s'", q.Index)
+	}
+
+	return nil
+}
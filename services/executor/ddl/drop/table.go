@@ -0,0 +1,23 @@
+package drop
+
+import (
+	"go-dbms/pkg/types"
+	"go-dbms/services/parser/query/ddl/drop"
+	"go-dbms/services/parser/query/dml/projection"
+	"go-dbms/util/stream"
+)
+
+// DropTable removes table from executor first, so new queries can't find it,
+// then drops it after queries in progress are finished.
+func (ddl *DDLDrop) DropTable(q *drop.QueryDropTable) (
+	stream.ReaderContinue[types.DataRow],
+	*projection.Projections,
+	error,
+) {
+	if db, ok := ddl.DB(q.DB); ok {
+		if t, ok := db.RemoveTable(q.Table); ok {
+			t.Drop()
+		}
+	}
+	return nil, nil, nil
+}
@@ -0,0 +1,12 @@
+package drop
+
+import "go-dbms/services/parser/query/ddl/drop"
+
+func (ddl *DDLDrop) ddlDropTableValidate(q *drop.QueryDropTable) error {
+	if _, err := ddl.LookupTable(q.DB, q.Table); err != nil && !q.IfExists {
+		return err
+	} else if db, ok := ddl.DB(q.DB); ok {
+		return db.Writable()
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+package drop
+
+import (
+	"go-dbms/pkg/types"
+	"go-dbms/services/parser/query/ddl/drop"
+	"go-dbms/services/parser/query/dml/projection"
+	"go-dbms/util/stream"
+
+	"github.com/pkg/errors"
+)
+
+// DropView removes view from catalog of database, queries
+// already expanded by view aren't affected.
+func (ddl *DDLDrop) DropView(q *drop.QueryDropView) (
+	stream.ReaderContinue[types.DataRow],
+	*projection.Projections,
+	error,
+) {
+	if db, ok := ddl.DB(q.DB); ok {
+		err := db.RemoveView(q.Name)
+		return nil, nil, errors.Wrapf(err, "failed to drop view: '%s'", q.Name)
+	}
+	return nil, nil, nil
+}
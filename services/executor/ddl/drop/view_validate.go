@@ -0,0 +1,17 @@
+package drop
+
+import (
+	"fmt"
+
+	"go-dbms/services/parser/query/ddl/drop"
+)
+
+func (ddl *DDLDrop) ddlDropViewValidate(q *drop.QueryDropView) error {
+	db, ok := ddl.DB(q.DB)
+	if !ok {
+		return fmt.Errorf("database not found: '%s'", q.DB)
+	} else if _, ok := db.Views[q.Name]; !ok && !q.IfExists {
+		return fmt.Errorf("view not found: '%s'", q.Name)
+	}
+	return db.Writable()
+}
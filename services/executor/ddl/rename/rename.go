@@ -0,0 +1,34 @@
+package rename
+
+import (
+	"go-dbms/pkg/types"
+	"go-dbms/services/executor/parent"
+	"go-dbms/services/parser/query/ddl/rename"
+	"go-dbms/services/parser/query/dml/projection"
+	"go-dbms/util/stream"
+
+	"github.com/pkg/errors"
+)
+
+type DDLRename struct {
+	*parent.ExecutorService
+}
+
+func New(es *parent.ExecutorService) *DDLRename {
+	return &DDLRename{ExecutorService: es}
+}
+
+// Rename moves directory of table and replaces
+// it by new name in tables of database.
+func (ddl *DDLRename) Rename(q *rename.QueryRenameTable) (
+	stream.ReaderContinue[types.DataRow],
+	*projection.Projections,
+	error,
+) {
+	if err := ddl.ddlRenameValidate(q); err != nil {
+		return nil, nil, errors.Wrapf(err, "validation error")
+	}
+
+	db, _ := ddl.DB(q.DB)
+	return nil, nil, db.RenameTable(q.Table, q.NewTable)
+}
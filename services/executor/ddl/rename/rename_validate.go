@@ -0,0 +1,23 @@
+package rename
+
+import (
+	"fmt"
+
+	"go-dbms/services/parser/query/ddl/rename"
+)
+
+func (ddl *DDLRename) ddlRenameValidate(q *rename.QueryRenameTable) error {
+	if _, err := ddl.LookupWritableTable(q.DB, q.Table); err != nil {
+		return err
+	}
+
+	db, _ := ddl.DB(q.DB)
+	if newDB, ok := ddl.DB(q.NewDB); !ok || newDB != db {
+		return fmt.Errorf("table can't be moved to another database: '%s'", q.NewDB)
+	} else if ddl.Table(q.NewDB, q.NewTable) != nil {
+		return fmt.Errorf("table already exists: '%s'", q.NewTable)
+	} else if _, ok := db.Views[q.NewTable]; ok {
+		return fmt.Errorf("view already exists: '%s'", q.NewTable)
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package truncate
+
+import (
+	"go-dbms/pkg/types"
+	"go-dbms/services/executor/parent"
+	"go-dbms/services/parser/query/ddl/truncate"
+	"go-dbms/services/parser/query/dml/projection"
+	"go-dbms/util/stream"
+
+	"github.com/pkg/errors"
+)
+
+type DDLTruncate struct {
+	*parent.ExecutorService
+}
+
+func New(es *parent.ExecutorService) *DDLTruncate {
+	return &DDLTruncate{ExecutorService: es}
+}
+
+// Truncate removes all rows of table, metadata of table is kept.
+func (ddl *DDLTruncate) Truncate(q *truncate.QueryTruncateTable) (
+	stream.ReaderContinue[types.DataRow],
+	*projection.Projections,
+	error,
+) {
+	t, err := ddl.LookupWritableTable(q.DB, q.Table)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "validation error")
+	}
+	return nil, nil, t.Truncate()
+}
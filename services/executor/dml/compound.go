@@ -0,0 +1,186 @@
+package dml
+
+import (
+	"go-dbms/pkg/types"
+	"go-dbms/pkg/types/sorted"
+	"go-dbms/services/executor/parent"
+	"go-dbms/services/parser/query"
+	"go-dbms/services/parser/query/dml"
+	"go-dbms/services/parser/query/dml/projection"
+	"go-dbms/util/helpers"
+	"go-dbms/util/stream"
+
+	"github.com/pkg/errors"
+)
+
+// compoundSide is a column of rows of set operations,
+// which tells from which side of operation row came.
+const compoundSide = "#side"
+
+func (dmlt *DML) Compound(q *dml.QueryCompound, es parent.Executor) (
+	stream.ReaderContinue[types.DataRow],
+	*projection.Projections,
+	error,
+) {
+	if err := dmlt.dmlCompoundValidate(q); err != nil {
+		return nil, nil, errors.Wrapf(err, "validation error")
+	}
+
+	out := stream.New[types.DataRow](1)
+	dst := stream.WriterContinue[types.DataRow](out)
+
+	if q.Limit != nil {
+		src := stream.New[types.DataRow](1)
+		go dmlt.limit(q.Limit, src, probeOf(es, q, opLimit).writer(dst))
+		dst = src
+	}
+
+	if len(q.OrderBy) > 0 {
+		src := stream.New[types.DataRow](1)
+		go dmlt.order(q.OrderBy, src, probeOf(es, q, opSort).writer(dst))
+		dst = src
+	}
+	dst = probeOf(es, q, "").writer(dst)
+
+	prs := dml.Projections(q)
+	cr := &compoundRows{
+		aliases: make([]string, 0, len(prs.Iterator())),
+		metas:   make([]types.DataTypeMeta, len(prs.Iterator())),
+	}
+	for _, p := range prs.Iterator() {
+		cr.aliases = append(cr.aliases, p.Alias)
+	}
+
+	go func() {
+		defer dst.Close()
+
+		if q.Op == dml.UNION && q.All {
+			for _, sq := range []query.Querier{q.Left, q.Right} {
+				s, p, err := es.Exec(sq)
+				helpers.Must(err)
+
+				for row, ok := s.Pop(); ok; row, ok = s.Pop() {
+					dst.Push(cr.normalize(row, p))
+					if !dst.ShouldContinue() {
+						s.Continue(false)
+						return
+					}
+					s.Continue(true)
+				}
+			}
+			return
+		}
+
+		sorter := sorted.NewSorter(cr.compareWithSide, sorted.DefaultMemLimit)
+		defer sorter.Close()
+
+		for side, sq := range []query.Querier{q.Left, q.Right} {
+			s, p, err := es.Exec(sq)
+			helpers.Must(err)
+
+			for row, ok := s.Pop(); ok; row, ok = s.Pop() {
+				s.Continue(true)
+				row = cr.normalize(row, p)
+				row[compoundSide] = types.Type(types.Meta(types.TYPE_INTEGER, false, 1, false)).Set(uint8(side))
+				helpers.Must(sorter.Add(row))
+			}
+		}
+
+		sw := &setWriter{op: q.Op, cr: cr, dst: dst}
+		helpers.Must(sorter.Flush(sw))
+		sw.flush()
+	}()
+
+	return out, prs, nil
+}
+
+// compoundRows converts rows of both sides of set operation
+// to the same aliases and types.
+type compoundRows struct {
+	aliases []string
+	metas   []types.DataTypeMeta
+}
+
+func (cr *compoundRows) normalize(row types.DataRow, prs *projection.Projections) types.DataRow {
+	res := make(types.DataRow, len(cr.aliases)+1)
+	for i, p := range prs.Iterator() {
+		val := row[p.Alias]
+		if val != nil && cr.metas[i] == nil {
+			cr.metas[i] = val.MetaCopy()
+		} else if val != nil {
+			if casted, err := val.Cast(cr.metas[i]); err == nil {
+				val = casted
+			}
+		}
+		res[cr.aliases[i]] = val
+	}
+	return res
+}
+
+func (cr *compoundRows) compare(a, b types.DataRow) int {
+	for _, alias := range cr.aliases {
+		if cmp := types.Compare(a[alias], b[alias]); cmp != 0 {
+			return cmp
+		}
+	}
+	return 0
+}
+
+func (cr *compoundRows) compareWithSide(a, b types.DataRow) int {
+	if cmp := cr.compare(a, b); cmp != 0 {
+		return cmp
+	}
+	return types.Compare(a[compoundSide], b[compoundSide])
+}
+
+// setWriter receives sorted rows of both sides of set operation,
+// and pushes rows to dst according to operation.
+type setWriter struct {
+	op      dml.SetOperation
+	cr      *compoundRows
+	dst     stream.WriterContinue[types.DataRow]
+	prev    types.DataRow
+	sides   [2]bool
+	stopped bool
+}
+
+func (sw *setWriter) Push(row types.DataRow) {
+	side := row[compoundSide].Value().(uint8)
+	delete(row, compoundSide)
+
+	if sw.prev != nil && sw.cr.compare(sw.prev, row) == 0 {
+		sw.sides[side] = true
+		return
+	}
+
+	sw.flush()
+	sw.prev = row
+	sw.sides = [2]bool{}
+	sw.sides[side] = true
+}
+
+func (sw *setWriter) ShouldContinue() bool {
+	return !sw.stopped
+}
+
+func (sw *setWriter) Close() {}
+
+// flush pushes previous group of equal rows, if operation allows it.
+func (sw *setWriter) flush() {
+	if sw.prev == nil || sw.stopped {
+		return
+	}
+
+	var push bool
+	switch sw.op {
+		case dml.UNION:     push = true
+		case dml.INTERSECT: push = sw.sides[0] && sw.sides[1]
+		case dml.EXCEPT:    push = sw.sides[0] && !sw.sides[1]
+	}
+
+	if push {
+		sw.dst.Push(sw.prev)
+		sw.stopped = !sw.dst.ShouldContinue()
+	}
+	sw.prev = nil
+}
@@ -0,0 +1,142 @@
+package dml
+
+import (
+	"fmt"
+	"strings"
+
+	"go-dbms/pkg/types"
+	"go-dbms/services/parser/query"
+	"go-dbms/services/parser/query/dml"
+	"go-dbms/services/parser/query/dml/aggregator"
+	"go-dbms/services/parser/query/dml/projection"
+	"go-dbms/util/helpers"
+)
+
+func (dmlt *DML) dmlCompoundValidate(q *dml.QueryCompound) (err error) {
+	defer helpers.RecoverOnError(&err)()
+
+	dmlt.validateQuery(q.Left)
+	dmlt.validateQuery(q.Right)
+
+	left, right := dmlt.resultMetas(q.Left), dmlt.resultMetas(q.Right)
+	if len(left) != len(right) {
+		panic(fmt.Errorf("each %s query must have the same number of columns: %d != %d", q.Op, len(left), len(right)))
+	}
+
+	for i := range left {
+		if left[i] != nil && right[i] != nil && typeClass(left[i].GetCode()) != typeClass(right[i].GetCode()) {
+			panic(fmt.Errorf("%s types of column %d are not compatible", q.Op, i+1))
+		}
+	}
+
+	prs := dml.Projections(q)
+	for _, it := range q.OrderBy {
+		dmlt.validateCompoundOrder(prs, it.Projection)
+	}
+	return nil
+}
+
+func (dmlt *DML) validateQuery(q query.Querier) {
+	var err error
+	switch q := q.(type) {
+		case *dml.QuerySelect:   err = dmlt.dmlSelectValidate(q)
+		case *dml.QueryCompound: err = dmlt.dmlCompoundValidate(q)
+	}
+	if err != nil {
+		panic(err)
+	}
+}
+
+func (dmlt *DML) validateCompoundOrder(prs *projection.Projections, p *projection.Projection) {
+	if prs.Has(p.Alias) {
+		return
+	}
+
+	switch p.Type {
+		case projection.IDENTIFIER:
+			panic(fmt.Errorf("identifier not found: '%s'", p.Name))
+		case projection.FUNCTION, projection.CASE:
+			for _, pa := range p.Operands() {
+				dmlt.validateCompoundOrder(prs, pa)
+			}
+		case projection.AGGREGATOR, projection.SUBQUERY:
+			panic(fmt.Errorf("order item must be a projection: '%s'", p.Alias))
+	}
+}
+
+// typeClass groups types which values can be compared with each other.
+func typeClass(code types.TypeCode) types.TypeCode {
+	switch code {
+		case types.TYPE_FLOAT:   return types.TYPE_INTEGER
+		case types.TYPE_VARCHAR: return types.TYPE_STRING
+		default:                 return code
+	}
+}
+
+// resultMetas returns types of query result columns,
+// nil for columns which type can't be determined before execution.
+func (dmlt *DML) resultMetas(q query.Querier) []types.DataTypeMeta {
+	switch q := q.(type) {
+		case *dml.QueryCompound:
+			return dmlt.resultMetas(q.Left)
+		case *dml.QuerySelect:
+			metas := make([]types.DataTypeMeta, 0, len(q.Projections.Iterator()))
+			for _, p := range q.Projections.Iterator() {
+				metas = append(metas, dmlt.projectionMeta(q, p))
+			}
+			return metas
+	}
+	return nil
+}
+
+func (dmlt *DML) projectionMeta(q *dml.QuerySelect, p *projection.Projection) types.DataTypeMeta {
+	switch p.Type {
+		case projection.LITERAL:
+			if p.Literal == nil {
+				return nil // NULL is compatible with any type
+			}
+			return p.Literal.MetaCopy()
+
+		case projection.AGGREGATOR:
+			switch aggregator.AggregatorType(p.Name) {
+				case aggregator.COUNT: return types.Meta(types.TYPE_INTEGER, false, 8, false)
+				case aggregator.AVG:   return types.Meta(types.TYPE_FLOAT, 8)
+				default:               return dmlt.projectionMeta(q, p.Arguments[0])
+			}
+
+		case projection.IDENTIFIER:
+			if pr, _, ok := q.Projections.GetByAlias(p.Name); ok && pr != p {
+				return dmlt.projectionMeta(q, pr)
+			}
+			return dmlt.columnMeta(q, p.Name)
+	}
+	return nil
+}
+
+// columnMeta returns type of column read from query sources.
+func (dmlt *DML) columnMeta(q *dml.QuerySelect, name string) types.DataTypeMeta {
+	sources := []dml.From{q.From}
+	for _, j := range q.From.Joins {
+		sources = append(sources, j.Source)
+	}
+
+	for _, src := range sources {
+		col := name
+		if len(q.From.Joins) != 0 {
+			if prefix, c, ok := strings.Cut(name, "."); ok && prefix == src.Name() {
+				col = c
+			}
+		}
+
+		if src.Type == dml.FROM_SCHEMA {
+			if c := dmlt.Table(src.DB, src.Table).Column(col); c != nil {
+				return c.Meta
+			}
+		} else if sq, ok := src.SubQuery.(*dml.QuerySelect); ok {
+			if pr, _, ok := sq.Projections.GetByAlias(col); ok {
+				return dmlt.projectionMeta(sq, pr)
+			}
+		}
+	}
+	return nil
+}
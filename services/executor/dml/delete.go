@@ -5,7 +5,6 @@ import (
 	"go-dbms/services/executor/parent"
 	"go-dbms/services/parser/query/dml"
 	"go-dbms/services/parser/query/dml/projection"
-	"go-dbms/util/helpers"
 	"go-dbms/util/stream"
 
 	"github.com/pkg/errors"
@@ -16,37 +15,40 @@ func (dml *DML) Delete(q *dml.QueryDelete, es parent.Executor) (
 	*projection.Projections,
 	error,
 ) {
+	if err := resolveLists(es, q.Where, q.WhereIndex); err != nil {
+		return nil, nil, err
+	}
 	if err := dml.dmlDeleteValidate(q); err != nil {
 		return nil, nil, errors.Wrapf(err, "validation error")
 	}
 
-	t := dml.Tables[q.Table]
-	dst := stream.New[types.DataRow](1)
+	t := dml.Table(q.DB, q.Table)
+	var s stream.Reader[types.DataRow]
+	var err error
+	if q.WhereIndex != nil {
+		s, err = t.DeleteByIndex(
+			q.UseIndex,
+			q.WhereIndex.FilterStart,
+			q.WhereIndex.FilterEnd,
+			q.Where,
+		)
+	} else {
+		s, err = t.Delete(q.Where)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
 
-	go func() {
-		process := func(s stream.Reader[types.DataRow]) error {
-			defer dst.Close()
-			for row, ok := s.Pop(); ok; row, ok = s.Pop() {
-				dst.Push(row)
-				dst.ShouldContinue() // have no effect but must call because return type is stream.ReaderContinue
-			}
-			return nil
-		}
+	out := stream.New[types.DataRow](1)
+	dst := probeOf(es, q, "").writer(out)
 
-		var s stream.Reader[types.DataRow]
-		if q.WhereIndex != nil {
-			s = helpers.MustVal(t.DeleteByIndex(
-				q.UseIndex,
-				q.WhereIndex.FilterStart,
-				q.WhereIndex.FilterEnd,
-				q.Where,
-			))
-		} else {
-			s = t.Delete(q.Where)
+	go func() {
+		defer dst.Close()
+		for row, ok := s.Pop(); ok; row, ok = s.Pop() {
+			dst.Push(evalReturning(q.Returning, row))
+			dst.ShouldContinue() // have no effect but must call because return type is stream.ReaderContinue
 		}
-
-		helpers.Must(process(s))
 	}()
 
-	return dst, projection.FromCols(t.PrimaryColumns()), nil
+	return out, returnedProjections(t, q.Returning), nil
 }
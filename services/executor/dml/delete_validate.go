@@ -1,19 +1,15 @@
 package dml
 
-import (
-	"fmt"
-
-	"go-dbms/services/parser/query/dml"
-)
+import "go-dbms/services/parser/query/dml"
 
 func (dml *DML) dmlDeleteValidate(q *dml.QueryDelete) error {
-	table, ok := dml.Tables[q.Table]
-	if !ok {
-		return fmt.Errorf("table not found: '%s'", q.Table)
+	table, err := dml.LookupWritableTable(q.DB, q.Table)
+	if err != nil {
+		return err
 	}
 
 	dml.validateWhereIndex(table, q.WhereIndex)
 	dml.validateWhere(q.Where)
 
-	return nil
+	return dml.validateReturning(table, q.Returning)
 }
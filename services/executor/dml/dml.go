@@ -1,11 +1,18 @@
 package dml
 
-import "go-dbms/services/executor/parent"
+import (
+	"go-dbms/services/executor/parent"
+	"go-dbms/services/parser"
+	"go-dbms/services/parser/query"
+)
 
 type DML struct {
 	*parent.ExecutorService
+
+	// parser of queries of views
+	parser query.Parser
 }
 
 func New(es *parent.ExecutorService) *DML {
-	return &DML{ExecutorService: es}
+	return &DML{ExecutorService: es, parser: parser.New()}
 }
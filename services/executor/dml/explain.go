@@ -0,0 +1,235 @@
+package dml
+
+import (
+	"slices"
+	"sync/atomic"
+	"time"
+
+	"go-dbms/pkg/types"
+	"go-dbms/services/executor/parent"
+	"go-dbms/services/parser/query"
+	"go-dbms/services/parser/query/dml"
+	"go-dbms/services/parser/query/dml/projection"
+	"go-dbms/util/stream"
+
+	"github.com/pkg/errors"
+)
+
+// columns of EXPLAIN result
+var explainColumns = []string{"id", "parent", "operator", "detail"}
+
+// columns added by EXPLAIN ANALYZE
+var analyzeColumns = []string{"rows", "time"}
+
+func (dmlt *DML) Explain(q *dml.QueryExplain, es parent.Executor) (
+	stream.ReaderContinue[types.DataRow],
+	*projection.Projections,
+	error,
+) {
+	if err := dmlt.dmlExplainValidate(q); err != nil {
+		return nil, nil, errors.Wrapf(err, "validation error")
+	}
+
+	pl := &planner{dmlt: dmlt, nodes: map[planKey]*planNode{}}
+	root := pl.plan(q.Target)
+
+	columns := explainColumns
+	if q.Analyze {
+		columns = slices.Concat(explainColumns, analyzeColumns)
+
+		an := &analyzer{Executor: es, dml: dmlt, nodes: pl.nodes}
+		r, _, err := an.Exec(q.Target)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, ok := r.Pop(); ok; _, ok = r.Pop() {
+			r.Continue(true)
+		}
+	}
+
+	prs := projection.New()
+	for _, col := range columns {
+		prs.Add(&projection.Projection{Alias: col, Name: col, Type: projection.IDENTIFIER})
+	}
+
+	out := stream.New[types.DataRow](1)
+	go func() {
+		defer out.Close()
+		root.walk(func(id, parent int, n *planNode) bool {
+			out.Push(n.row(id, parent, q.Analyze))
+			return out.ShouldContinue()
+		})
+	}()
+
+	return out, prs, nil
+}
+
+// planKey identifies operator of query, operator of query itself has empty name.
+type planKey struct {
+	q  query.Querier
+	op string
+}
+
+// planNode is operator of query plan. Operators read rows from their first
+// child, other children are subqueries or inner sides of joins.
+type planNode struct {
+	operator string
+	detail   string
+	children []*planNode
+
+	analyzed atomic.Bool
+	rows     atomic.Int64
+	elapsed  atomic.Int64
+}
+
+// walk calls fn for node and its descendants in depth-first order. Nodes are
+// numbered starting from 1, parent of root is 0. Stops if fn returns false.
+func (n *planNode) walk(fn func(id, parent int, n *planNode) bool) {
+	id := 0
+	var visit func(parent int, n *planNode) bool
+	visit = func(parent int, n *planNode) bool {
+		id++
+		self := id
+		if !fn(self, parent, n) {
+			return false
+		}
+		for _, child := range n.children {
+			if !visit(self, child) {
+				return false
+			}
+		}
+		return true
+	}
+	visit(0, n)
+}
+
+func (n *planNode) row(id, parent int, analyze bool) types.DataRow {
+	row := types.DataRow{
+		"id":       integer(int64(id)),
+		"parent":   nil,
+		"operator": types.Type(types.Meta(types.TYPE_STRING)).Set(n.operator),
+		"detail":   types.Type(types.Meta(types.TYPE_STRING)).Set(n.detail),
+	}
+	if parent != 0 {
+		row["parent"] = integer(int64(parent))
+	}
+
+	if analyze {
+		row["rows"], row["time"] = nil, nil
+		if n.analyzed.Load() {
+			row["rows"] = integer(n.rows.Load())
+			row["time"] = types.Type(types.Meta(types.TYPE_STRING)).Set(time.Duration(n.elapsed.Load()).String())
+		}
+	}
+	return row
+}
+
+func integer(v int64) types.DataType {
+	return types.Type(types.Meta(types.TYPE_INTEGER, true, 8, false)).Set(v)
+}
+
+// analyzer executes queries of EXPLAIN ANALYZE, so operators
+// of queries and their subqueries report their statistics.
+type analyzer struct {
+	parent.Executor
+	dml   *DML
+	nodes map[planKey]*planNode
+}
+
+func (an *analyzer) Exec(q query.Querier) (
+	stream.ReaderContinue[types.DataRow],
+	*projection.Projections,
+	error,
+) {
+	switch q := q.(type) {
+		case *dml.QuerySelect:   return an.dml.Select(q, an)
+		case *dml.QueryCompound: return an.dml.Compound(q, an)
+		case *dml.QueryUpdate:   return an.dml.Update(q, an)
+		case *dml.QueryDelete:   return an.dml.Delete(q, an)
+	}
+	return an.Executor.Exec(q)
+}
+
+func (an *analyzer) probe(q query.Querier, op string) *probe {
+	n, ok := an.nodes[planKey{q, op}]
+	if !ok {
+		return nil
+	}
+
+	n.analyzed.Store(true)
+	return &probe{node: n, start: time.Now()}
+}
+
+// probeOf returns probe of operator op of query q, nil if query isn't
+// executed by EXPLAIN ANALYZE. Empty op is operator of query itself.
+func probeOf(es parent.Executor, q query.Querier, op string) *probe {
+	if an, ok := es.(*analyzer); ok {
+		return an.probe(q, op)
+	}
+	return nil
+}
+
+// probe counts rows produced by operator and time passed from start
+// of operator till it's finished. Methods of nil probe do nothing.
+type probe struct {
+	node  *planNode
+	start time.Time
+}
+
+func (p *probe) add() {
+	if p != nil {
+		p.node.rows.Add(1)
+	}
+}
+
+func (p *probe) done() {
+	if p != nil {
+		p.node.elapsed.Store(int64(time.Since(p.start)))
+	}
+}
+
+// writer counts rows pushed to dst.
+func (p *probe) writer(dst stream.WriterContinue[types.DataRow]) stream.WriterContinue[types.DataRow] {
+	if p == nil {
+		return dst
+	}
+	return &probeWriter{WriterContinue: dst, p: p}
+}
+
+// reader counts rows popped from src.
+func (p *probe) reader(src stream.ReaderContinue[types.DataRow]) stream.ReaderContinue[types.DataRow] {
+	if p == nil {
+		return src
+	}
+	return &probeReader{ReaderContinue: src, p: p}
+}
+
+type probeWriter struct {
+	stream.WriterContinue[types.DataRow]
+	p *probe
+}
+
+func (w *probeWriter) Push(row types.DataRow) {
+	w.p.add()
+	w.WriterContinue.Push(row)
+}
+
+func (w *probeWriter) Close() {
+	w.p.done()
+	w.WriterContinue.Close()
+}
+
+type probeReader struct {
+	stream.ReaderContinue[types.DataRow]
+	p *probe
+}
+
+func (r *probeReader) Pop() (types.DataRow, bool) {
+	row, ok := r.ReaderContinue.Pop()
+	if ok {
+		r.p.add()
+	} else {
+		r.p.done()
+	}
+	return row, ok
+}
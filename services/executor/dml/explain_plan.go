@@ -0,0 +1,421 @@
+package dml
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"go-dbms/pkg/engine/mergetree"
+	"go-dbms/pkg/index"
+	"go-dbms/pkg/statement"
+	"go-dbms/pkg/table"
+	"go-dbms/pkg/types"
+	"go-dbms/services/parser/query"
+	"go-dbms/services/parser/query/dml"
+	"go-dbms/services/parser/query/dml/function"
+	"go-dbms/services/parser/query/dml/order"
+	"go-dbms/services/parser/query/dml/projection"
+)
+
+// operators of query plan, which are probed by EXPLAIN ANALYZE
+const (
+	opScan     = "SCAN"
+	opFilter   = "FILTER"
+	opGroup    = "GROUP"
+	opDistinct = "DISTINCT"
+	opSort     = "SORT"
+	opLimit    = "LIMIT"
+)
+
+// opJoin is name of operator joining i-th source of select query.
+func opJoin(i int) string {
+	return fmt.Sprintf("JOIN %d", i)
+}
+
+// planner builds plan of query the same way as executor decides how
+// to execute it. Nodes of operators are registered, so EXPLAIN ANALYZE
+// can find them by query and operator name.
+type planner struct {
+	dmlt  *DML
+	nodes map[planKey]*planNode
+}
+
+func (pl *planner) node(q query.Querier, op, operator, detail string, children ...*planNode) *planNode {
+	n := &planNode{operator: operator, detail: detail, children: children}
+	pl.nodes[planKey{q, op}] = n
+	return n
+}
+
+func (pl *planner) plan(q query.Querier) *planNode {
+	switch q := q.(type) {
+		case *dml.QuerySelect:   return pl.selectPlan(q)
+		case *dml.QueryCompound: return pl.compoundPlan(q)
+		case *dml.QueryUpdate:   return pl.modifyPlan(q, q.DB, q.Table, q.UseIndex, q.WhereIndex, q.Where, updateDetail(q))
+		case *dml.QueryDelete:   return pl.modifyPlan(q, q.DB, q.Table, q.UseIndex, q.WhereIndex, q.Where, "table "+q.Table)
+	}
+	panic(fmt.Errorf("query can't be explained: '%s'", q.GetType()))
+}
+
+func (pl *planner) selectPlan(q *dml.QuerySelect) *planNode {
+	var n *planNode
+	orderIdx, reverse, ordered := pl.dmlt.orderIndex(q)
+	if q.From.Type == dml.FROM_SUBQUERY {
+		n = pl.node(q, opScan, "SUBQUERY SCAN", q.From.Alias, pl.plan(q.From.SubQuery))
+	} else {
+		t := pl.dmlt.Table(q.From.DB, q.From.Table)
+		switch {
+			case ordered:
+				detail := tableDetail(q.From.Table, t, orderIdx) + ", ordered"
+				if reverse {
+					detail += " reverse"
+				}
+				n = pl.node(q, opScan, "INDEX SCAN", detail)
+			case q.WhereIndex != nil:
+				n = pl.indexScan(q, q.From.Table, t, q.UseIndex, q.WhereIndex)
+			default:
+				n = pl.node(q, opScan, "INDEX SCAN", tableDetail(q.From.Table, t, cmp.Or(q.UseIndex, t.PrimaryKey())))
+		}
+	}
+
+	if len(q.From.Joins) != 0 {
+		js := pl.dmlt.joinSources(q)
+		for i, j := range q.From.Joins {
+			n = pl.joinPlan(q, js, j, i+1, n)
+		}
+	}
+
+	if q.Where != nil {
+		n = pl.node(q, opFilter, "FILTER", formatWhere(q.Where), append([]*planNode{n}, pl.whereSubqueries(q.Where)...)...)
+	}
+
+	if prs := selectProjections(q); len(prs.Aggregators()) != 0 || len(q.GroupBy) != 0 {
+		keys := groupKeys(q)
+		groupBy := make([]string, 0, len(keys))
+		for _, k := range keys {
+			groupBy = append(groupBy, k.Alias)
+		}
+
+		details := []string{}
+		if len(groupBy) != 0 {
+			details = append(details, "by "+strings.Join(groupBy, ", "))
+		}
+		if q.Having != nil {
+			details = append(details, "having "+formatWhere(q.Having))
+		}
+		n = pl.node(q, opGroup, "GROUP", strings.Join(details, ", "), n)
+	}
+
+	if q.Distinct {
+		n = pl.node(q, opDistinct, "DISTINCT", "", n)
+	}
+	if len(q.OrderBy) > 0 && !ordered {
+		n = pl.node(q, opSort, "SORT", formatOrder(q.OrderBy), n)
+	}
+	if q.Limit != nil {
+		n = pl.node(q, opLimit, "LIMIT", formatLimit(q.Limit), n)
+	}
+
+	columns := []string{}
+	children := []*planNode{n}
+	for _, p := range q.Projections.Iterator() {
+		columns = append(columns, formatProjection(p))
+		children = append(children, pl.projectionSubqueries(p)...)
+	}
+	return pl.node(q, "", "SELECT", strings.Join(columns, ", "), children...)
+}
+
+func (pl *planner) compoundPlan(q *dml.QueryCompound) *planNode {
+	operator := string(q.Op)
+	if q.All {
+		operator += " ALL"
+	}
+
+	n := pl.node(q, "", operator, "", pl.plan(q.Left), pl.plan(q.Right))
+	if len(q.OrderBy) > 0 {
+		n = pl.node(q, opSort, "SORT", formatOrder(q.OrderBy), n)
+	}
+	if q.Limit != nil {
+		n = pl.node(q, opLimit, "LIMIT", formatLimit(q.Limit), n)
+	}
+	return n
+}
+
+// modifyPlan builds plan of UPDATE and DELETE queries. Table filters
+// rows by itself while scanning, so filter is part of scan.
+func (pl *planner) modifyPlan(
+	q query.Querier,
+	dbName, tableName, useIndex string,
+	wi *dml.WhereIndex,
+	ws *statement.WhereStatement,
+	detail string,
+) *planNode {
+	t := pl.dmlt.Table(dbName, tableName)
+
+	var n *planNode
+	if wi != nil {
+		n = pl.indexScan(q, tableName, t, useIndex, wi)
+	} else {
+		n = pl.node(q, opScan, "FULL SCAN", tableDetail(tableName, t, ""))
+	}
+	if ws != nil {
+		n.detail += ", filter " + formatWhere(ws)
+		n.children = append(n.children, pl.whereSubqueries(ws)...)
+	}
+	return pl.node(q, "", string(q.GetType()), detail, n)
+}
+
+func (pl *planner) indexScan(q query.Querier, tableName string, t table.ITable, name string, wi *dml.WhereIndex) *planNode {
+	operator := "INDEX RANGE SCAN"
+	if wi.FilterStart.Operator == types.In {
+		operator = "INDEX POINT SCAN"
+	}
+
+	detail := tableDetail(tableName, t, name) + ", start " + formatFilter(wi.FilterStart)
+	if wi.FilterEnd != nil {
+		detail += ", end " + formatFilter(wi.FilterEnd)
+	}
+
+	children := []*planNode{}
+	for _, f := range []*index.Filter{wi.FilterStart, wi.FilterEnd} {
+		if f == nil {
+			continue
+		}
+		for _, cond := range f.Conditions {
+			children = append(children, pl.projectionSubqueries(cond.Right)...)
+		}
+	}
+	return pl.node(q, opScan, operator, detail, children...)
+}
+
+func (pl *planner) joinPlan(q *dml.QuerySelect, js *joinSources, j *dml.Join, i int, left *planNode) *planNode {
+	joinType := "INNER"
+	if j.Type == dml.JOIN_LEFT {
+		joinType = "LEFT"
+	}
+
+	detail := fmt.Sprintf("%s %s on %s", joinType, j.Source.Name(), formatWhere(j.On))
+	eqs, _ := js.splitOn(j.On, i)
+	if j.Source.Type == dml.FROM_SCHEMA {
+		t := pl.dmlt.Table(j.Source.DB, j.Source.Table)
+		if name, _ := js.lookupIndex(t, js.list[i], eqs); name != "" {
+			detail += ", " + tableDetail(j.Source.Table, t, name)
+			return pl.node(q, opJoin(i), "INDEX JOIN", detail, left)
+		}
+	}
+
+	var right *planNode
+	if j.Source.Type == dml.FROM_SUBQUERY {
+		right = pl.plan(j.Source.SubQuery)
+	} else {
+		t := pl.dmlt.Table(j.Source.DB, j.Source.Table)
+		right = &planNode{operator: "INDEX SCAN", detail: tableDetail(j.Source.Table, t, t.PrimaryKey())}
+	}
+	return pl.node(q, opJoin(i), "HASH JOIN", detail, left, right)
+}
+
+// whereSubqueries returns plans of subqueries of where statement.
+func (pl *planner) whereSubqueries(ws *statement.WhereStatement) []*planNode {
+	nodes := []*planNode{}
+	for _, p := range ws.Operands() {
+		nodes = append(nodes, pl.projectionSubqueries(p)...)
+	}
+	return nodes
+}
+
+// projectionSubqueries returns plans of subqueries of projection and its operands.
+func (pl *planner) projectionSubqueries(p *projection.Projection) []*planNode {
+	if p == nil {
+		return nil
+	} else if p.Subquery != nil {
+		return []*planNode{pl.plan(p.Subquery)}
+	}
+
+	nodes := []*planNode{}
+	for _, arg := range p.Operands() {
+		nodes = append(nodes, pl.projectionSubqueries(arg)...)
+	}
+	return nodes
+}
+
+// tableDetail describes table read by index, count of parts is added for
+// merge trees, because every part is scanned separately.
+func tableDetail(name string, t table.ITable, indexName string) string {
+	detail := "table " + name
+	if indexName != "" {
+		detail += " using index " + indexName
+	}
+	if mt, ok := t.(mergetree.IMergeTree); ok {
+		detail += fmt.Sprintf(", parts %d", mt.PartsCount())
+	}
+	return detail
+}
+
+func updateDetail(q *dml.QueryUpdate) string {
+	cols := make([]string, 0, len(q.Values))
+	for col := range q.Values {
+		cols = append(cols, col)
+	}
+	slices.Sort(cols)
+
+	set := make([]string, 0, len(cols))
+	for _, col := range cols {
+		set = append(set, col+" = "+formatProjection(q.Values[col]))
+	}
+	return "table " + q.Table + ", set " + strings.Join(set, ", ")
+}
+
+func formatLimit(l *dml.Limit) string {
+	if l.Offset == 0 {
+		return strconv.Itoa(l.Count)
+	}
+	return fmt.Sprintf("%d offset %d", l.Count, l.Offset)
+}
+
+func formatOrder(items []*order.Item) string {
+	res := make([]string, 0, len(items))
+	for _, it := range items {
+		s := formatProjection(it.Projection)
+		if it.Desc {
+			s += " DESC"
+		}
+		res = append(res, s)
+	}
+	return strings.Join(res, ", ")
+}
+
+// formatFilter formats filter of index scan. All conditions of filter
+// are compared with the same operator as prefix of index key.
+func formatFilter(f *index.Filter) string {
+	if f.Operator == types.In {
+		conds := make([]string, 0, len(f.Conditions))
+		for _, cond := range f.Conditions {
+			op := types.Equal
+			if cond.Right.Type == projection.LIST || cond.Right.Type == projection.SUBQUERY {
+				op = types.In
+			}
+			conds = append(conds, fmt.Sprintf("%s %s %s", formatProjection(cond.Left), op, formatProjection(cond.Right)))
+		}
+		return strings.Join(conds, " AND ")
+	}
+
+	if len(f.Conditions) == 1 {
+		cond := f.Conditions[0]
+		return fmt.Sprintf("%s %s %s", formatProjection(cond.Left), f.Operator, formatProjection(cond.Right))
+	}
+
+	left := make([]string, 0, len(f.Conditions))
+	right := make([]string, 0, len(f.Conditions))
+	for _, cond := range f.Conditions {
+		left = append(left, formatProjection(cond.Left))
+		right = append(right, formatProjection(cond.Right))
+	}
+	return fmt.Sprintf("(%s) %s (%s)", strings.Join(left, ", "), f.Operator, strings.Join(right, ", "))
+}
+
+// infix functions implementing arithmetic operators
+var infix = map[function.FunctionType]string{
+	function.ADD: "+",
+	function.SUB: "-",
+	function.MUL: "*",
+	function.DIV: "/",
+	function.RES: "%",
+}
+
+func formatProjection(p *projection.Projection) string {
+	if p.Subquery != nil {
+		return "(subquery)"
+	}
+
+	switch p.Type {
+		case projection.IDENTIFIER:
+			return p.Name
+
+		case projection.LITERAL:
+			if p.Literal == nil {
+				return "NULL"
+			} else if code := p.Literal.GetCode(); code == types.TYPE_STRING || code == types.TYPE_VARCHAR {
+				return strconv.Quote(fmt.Sprint(p.Literal.Value()))
+			}
+			return fmt.Sprint(p.Literal.Value())
+
+		case projection.LIST:
+			return "(" + formatProjections(p.Arguments) + ")"
+
+		case projection.FUNCTION:
+			if op, ok := infix[function.FunctionType(p.Name)]; ok && len(p.Arguments) == 2 {
+				return fmt.Sprintf("(%s %s %s)", formatProjection(p.Arguments[0]), op, formatProjection(p.Arguments[1]))
+			}
+			return p.Name + "(" + formatProjections(p.Arguments) + ")"
+
+		case projection.AGGREGATOR:
+			args := formatProjections(p.Arguments)
+			if p.Distinct {
+				args = "DISTINCT " + args
+			}
+			return p.Name + "(" + args + ")"
+
+		case projection.CASE:
+			b := &strings.Builder{}
+			b.WriteString("CASE")
+			for i, cond := range p.Conditions {
+				fmt.Fprintf(b, " WHEN %s THEN %s", formatWhere(cond.(*statement.WhereStatement)), formatProjection(p.Arguments[i]))
+			}
+			if len(p.Arguments) > len(p.Conditions) {
+				fmt.Fprintf(b, " ELSE %s", formatProjection(p.Arguments[len(p.Conditions)]))
+			}
+			b.WriteString(" END")
+			return b.String()
+	}
+	return p.Alias
+}
+
+func formatProjections(prs []*projection.Projection) string {
+	res := make([]string, 0, len(prs))
+	for _, p := range prs {
+		res = append(res, formatProjection(p))
+	}
+	return strings.Join(res, ", ")
+}
+
+func formatWhere(ws *statement.WhereStatement) string {
+	var s string
+	switch {
+		case ws.Statement != nil:
+			s = formatStatement(ws.Statement)
+		case len(ws.And) != 0:
+			conds := make([]string, 0, len(ws.And))
+			for _, w := range ws.And {
+				if len(w.Or) != 0 && !w.Not {
+					conds = append(conds, "("+formatWhere(w)+")")
+				} else {
+					conds = append(conds, formatWhere(w))
+				}
+			}
+			s = strings.Join(conds, " AND ")
+		default:
+			conds := make([]string, 0, len(ws.Or))
+			for _, w := range ws.Or {
+				conds = append(conds, formatWhere(w))
+			}
+			s = strings.Join(conds, " OR ")
+	}
+
+	if ws.Not {
+		return "NOT (" + s + ")"
+	}
+	return s
+}
+
+func formatStatement(st *statement.Statement) string {
+	left := formatProjection(st.Left)
+	switch st.Op {
+		case types.IsNull, types.IsNotNull:
+			return fmt.Sprintf("%s %s", left, st.Op)
+		case types.Between, types.NotBetween:
+			return fmt.Sprintf("%s %s %s AND %s", left, st.Op,
+				formatProjection(st.Right.Arguments[0]), formatProjection(st.Right.Arguments[1]))
+	}
+	return fmt.Sprintf("%s %s %s", left, st.Op, formatProjection(st.Right))
+}
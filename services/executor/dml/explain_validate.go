@@ -0,0 +1,17 @@
+package dml
+
+import (
+	"go-dbms/services/parser/query/dml"
+	"go-dbms/util/helpers"
+)
+
+func (dmlt *DML) dmlExplainValidate(q *dml.QueryExplain) (err error) {
+	defer helpers.RecoverOnError(&err)()
+
+	switch t := q.Target.(type) {
+		case *dml.QueryUpdate: _, err = dmlt.dmlUpdateValidate(t)
+		case *dml.QueryDelete: err = dmlt.dmlDeleteValidate(t)
+		default:               dmlt.validateQuery(t)
+	}
+	return err
+}
@@ -1,8 +1,12 @@
 package dml
 
 import (
+	"fmt"
+
+	"go-dbms/pkg/table"
 	"go-dbms/pkg/types"
 	"go-dbms/services/executor/parent"
+	"go-dbms/services/parser/query"
 	"go-dbms/services/parser/query/dml"
 	"go-dbms/services/parser/query/dml/projection"
 	"go-dbms/util/stream"
@@ -10,48 +14,202 @@ import (
 	"github.com/pkg/errors"
 )
 
+// column of INSERT IGNORE result with count of skipped rows
+const skippedColumn = "skipped"
+
 func (dml *DML) Insert(q *dml.QueryInsert, es parent.Executor) (
 	stream.ReaderContinue[types.DataRow],
 	*projection.Projections,
 	error,
 ) {
-	if err := dml.dmlInsertValidate(q); err != nil {
+	if q.Select != nil {
+		return dml.insertSelect(q, es)
+	}
+
+	rows, oc, err := dml.dmlInsertValidate(q)
+	if err != nil {
 		return nil, nil, errors.Wrapf(err, "validation error")
 	}
 
-	t := dml.Tables[q.Table]
-	dst := stream.New[types.DataRow](1)
-	in := stream.New[types.DataRow](1)
-	out, eg := t.Insert(in)
+	return dml.insert(dml.Table(q.DB, q.Table), oc, q.Returning, func() (types.DataRow, bool, error) {
+		if len(rows) == 0 {
+			return nil, false, nil
+		}
+		row := rows[0]
+		rows = rows[1:]
+		return row, true, nil
+	})
+}
+
+// insertSelect streams rows of select into table, values are casted to types
+// of columns. Rows are read before insertion, if select reads the table
+// itself, so inserted rows aren't selected again.
+func (dmlt *DML) insertSelect(q *dml.QueryInsert, es parent.Executor) (
+	stream.ReaderContinue[types.DataRow],
+	*projection.Projections,
+	error,
+) {
+	oc, err := dmlt.dmlInsertSelectValidate(q)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "validation error")
+	}
 
-	go func ()  {
+	t := dmlt.Table(q.DB, q.Table)
+	src, prs, err := es.Exec(q.Select)
+	if err != nil {
+		return nil, nil, err
+	}
 
-		eg.Go(func() error {
-			defer in.Close()
-			defer dst.Close()
+	done := false
+	defer func() {
+		if !done {
+			src.Continue(false)
+		}
+	}()
 
-			for _, v := range q.Values {
-				row := types.DataRow{}
-				for j, col := range q.Columns {
-					row[col] = v[j]
-				}
-	
-				in.Push(row)
-				pk, ok := out.Pop()
-				if !ok {
-					return errors.New("unexpected error while reading insertion result")
+	pop := func() (types.DataRow, bool) {
+		row, ok := src.Pop()
+		if ok {
+			src.Continue(true)
+		} else {
+			done = true
+		}
+		return row, ok
+	}
+	if dmlt.readsTable(q.Select, t) {
+		rows := []types.DataRow{}
+		for row, ok := pop(); ok; row, ok = pop() {
+			rows = append(rows, row)
+		}
+		pop = func() (types.DataRow, bool) {
+			if len(rows) == 0 {
+				return nil, false
+			}
+			row := rows[0]
+			rows = rows[1:]
+			return row, true
+		}
+	}
+
+	columns := t.ColumnsMap()
+	aliases := prs.Iterator()
+	return dmlt.insert(t, oc, q.Returning, func() (types.DataRow, bool, error) {
+		row, ok := pop()
+		if !ok {
+			return nil, false, nil
+		}
+
+		insertRow := make(types.DataRow, len(q.Columns))
+		for i, colName := range q.Columns {
+			col := columns[colName]
+			val := row[aliases[i].Alias]
+			if val == nil {
+				if !col.Nullable {
+					return nil, false, fmt.Errorf("column can't be null: '%s'", colName)
 				}
+				insertRow[colName] = nil
+				continue
+			}
 
-				dst.Push(pk)
-				dst.ShouldContinue() // have no effect but must call because return type is stream.ReaderContinue
+			casted, err := col.Cast(val)
+			if err != nil {
+				return nil, false, errors.Wrapf(err, "failed to cast '%v' to type '%v'", val.Value(), col.Typ)
+			}
+			insertRow[colName] = casted
+		}
+		return insertRow, true, nil
+	})
+}
+
+// insert inserts rows returned by next into table. Insertion is finished before
+// result is returned, so its error is returned by query. Result has projections
+// of returning evaluated on inserted rows, or their primary keys without it.
+// On IGNORE result has column skipped, which is NULL in inserted rows, and last
+// row has count of skipped rows in it with other columns NULL.
+func (dmlt *DML) insert(
+	t table.ITable,
+	oc *table.OnConflict,
+	returning *projection.Projections,
+	next func() (types.DataRow, bool, error),
+) (
+	stream.ReaderContinue[types.DataRow],
+	*projection.Projections,
+	error,
+) {
+	in := stream.New[types.DataRow](1)
+	out, eg := t.Upsert(in, oc)
+
+	inserted := []types.DataRow{}
+	skipped := 0
+	eg.Go(func() error {
+		defer in.Close()
+
+		for row, ok, err := next(); ok || err != nil; row, ok, err = next() {
+			if err != nil {
+				return err
 			}
-			return nil
-		})
 
-		if err := eg.Wait(); err != nil {
-			panic(err)
+			in.Push(row)
+			row, ok := out.Pop()
+			if !ok {
+				return nil // insertion failed, its error is returned by group
+			} else if row == nil {
+				skipped++
+			} else {
+				inserted = append(inserted, evalReturning(returning, row))
+			}
+		}
+		return nil
+	})
+
+	if err := eg.Wait(); err != nil {
+		return nil, nil, err
+	}
+
+	prs := returnedProjections(t, returning)
+	if oc.Action != table.CONFLICT_IGNORE {
+		return rowsStream(inserted), prs, nil
+	}
+
+	prs = prs.Copy()
+	prs.Add(&projection.Projection{Alias: skippedColumn, Name: skippedColumn, Type: projection.IDENTIFIER})
+	count := types.Type(types.Meta(types.TYPE_INTEGER, false, 8, false)).Set(skipped)
+	return rowsStream(append(inserted, types.DataRow{skippedColumn: count})), prs, nil
+}
+
+// rowsStream returns stream of rows.
+func rowsStream(rows []types.DataRow) stream.ReaderContinue[types.DataRow] {
+	out := stream.New[types.DataRow](1)
+	go func() {
+		defer out.Close()
+		for _, row := range rows {
+			out.Push(row)
+			if !out.ShouldContinue() {
+				return
+			}
 		}
 	}()
+	return out
+}
+
+// readsTable checks if query reads rows of table t.
+func (dmlt *DML) readsTable(q query.Querier, t table.ITable) bool {
+	switch q := q.(type) {
+		case *dml.QueryCompound:
+			return dmlt.readsTable(q.Left, t) || dmlt.readsTable(q.Right, t)
+		case *dml.QuerySelect:
+			sources := []dml.From{q.From}
+			for _, j := range q.From.Joins {
+				sources = append(sources, j.Source)
+			}
 
-	return dst, projection.FromCols(t.PrimaryColumns()), nil
+			for _, src := range sources {
+				if src.Type == dml.FROM_SCHEMA && dmlt.Table(src.DB, src.Table) == t {
+					return true
+				} else if src.Type == dml.FROM_SUBQUERY && dmlt.readsTable(src.SubQuery, t) {
+					return true
+				}
+			}
+	}
+	return false
 }
@@ -3,38 +3,118 @@ package dml
 import (
 	"fmt"
 
+	"go-dbms/pkg/table"
+	"go-dbms/pkg/types"
 	"go-dbms/services/parser/query/dml"
+	"go-dbms/services/parser/query/dml/eval"
+	"go-dbms/util/helpers"
 
 	"github.com/pkg/errors"
 )
 
-func (dml *DML) dmlInsertValidate(q *dml.QueryInsert) error {
-	table, ok := dml.Tables[q.Table]
-	if !ok {
-		return fmt.Errorf("table not found: '%s'", q.Table)
+// dmlInsertValidate validates query and returns rows to insert, values
+// are evaluated and casted to column types.
+func (dml *DML) dmlInsertValidate(q *dml.QueryInsert) ([]types.DataRow, *table.OnConflict, error) {
+	table, err := dml.LookupWritableTable(q.DB, q.Table)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	oc, err := dml.onConflict(table, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := dml.validateReturning(table, q.Returning); err != nil {
+		return nil, nil, err
+	}
+
+	rows := make([]types.DataRow, len(q.Values))
+	for j := range rows {
+		if len(q.Values[j]) != len(q.Columns) {
+			return nil, nil, fmt.Errorf(
+				"count of values on row %v is %v, must be %v",
+				j, len(q.Values[j]), len(q.Columns),
+			)
+		}
+		rows[j] = types.DataRow{}
 	}
 
 	columns := table.ColumnsMap()
 	for i, colName := range q.Columns {
 		if col, ok := columns[colName]; !ok {
-			return fmt.Errorf("column not found: '%s'", colName)
+			return nil, nil, fmt.Errorf("column not found: '%s'", colName)
 		} else {
 			for j := 0; j < len(q.Values); j++ {
-				if len(q.Values[j]) != len(q.Columns) {
-					return fmt.Errorf(
-						"count of values on row %v is %v, must be %v",
-						j, len(q.Values[j]), len(q.Columns),
-					)
+				val := eval.Eval(nil, q.Values[j][i])
+				if val == nil {
+					if !col.Nullable {
+						return nil, nil, fmt.Errorf("column can't be null: '%s'", colName)
+					}
+					rows[j][colName] = nil
+					continue
 				}
 
-				casted, err := q.Values[j][i].Cast(col.Meta)
+				casted, err := val.Cast(col.Meta)
 				if err != nil {
-					return errors.Wrapf(err, "failed to cast '%v' to type '%v'", q.Values[j][i].Value(), col.Typ)
+					return nil, nil, errors.Wrapf(err, "failed to cast '%v' to type '%v'", val.Value(), col.Typ)
 				}
-				q.Values[j][i] = casted
+				rows[j][colName] = casted
 			}
 		}
 	}
 
-	return nil
+	return rows, oc, nil
+}
+
+// dmlInsertSelectValidate validates columns of query, they
+// must match projections of select by count.
+func (dmlt *DML) dmlInsertSelectValidate(q *dml.QueryInsert) (*table.OnConflict, error) {
+	t, err := dmlt.LookupWritableTable(q.DB, q.Table)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, colName := range q.Columns {
+		if t.Column(colName) == nil {
+			return nil, fmt.Errorf("column not found: '%s'", colName)
+		}
+	}
+
+	if prs := dml.Projections(q.Select); prs == nil {
+		return nil, fmt.Errorf("invalid query of insert: '%s'", q.Select.GetType())
+	} else if len(prs.Iterator()) != len(q.Columns) {
+		return nil, fmt.Errorf(
+			"count of select columns is %v, must be %v",
+			len(prs.Iterator()), len(q.Columns),
+		)
+	}
+	if err := dmlt.validateReturning(t, q.Returning); err != nil {
+		return nil, err
+	}
+	return dmlt.onConflict(t, q)
+}
+
+// onConflict returns handling of conflicting rows by query, rows
+// conflict only in tables, which don't allow duplicates of keys.
+// New values of conflicting row are evaluated on existing row.
+func (dmlt *DML) onConflict(t table.ITable, q *dml.QueryInsert) (_ *table.OnConflict, err error) {
+	defer helpers.RecoverOnError(&err)()
+
+	if q.OnConflict == table.CONFLICT_FAIL {
+		return &table.OnConflict{Action: table.CONFLICT_FAIL}, nil
+	} else if t.Engine() != table.InnoDB {
+		return nil, fmt.Errorf("IGNORE, REPLACE and ON DUPLICATE KEY UPDATE are not supported by engine %s", t.Engine())
+	}
+
+	columns := tableColumns(t)
+	for colName, p := range q.Update {
+		if _, ok := columns[colName]; !ok {
+			return nil, fmt.Errorf("column not found: '%s'", colName)
+		}
+		dmlt.validateColumns(columns, p)
+	}
+
+	return &table.OnConflict{Action: q.OnConflict, Set: func(row types.DataRow) (types.DataRow, error) {
+		return castValues(t, q.Update, row)
+	}}, nil
 }
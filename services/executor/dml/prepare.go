@@ -19,7 +19,7 @@ func (dml *DML) Prepare(q *dml.QueryPrepare, es parent.Executor) (
 		return nil, nil, errors.Wrapf(err, "validation error")
 	}
 
-	dml.Tables[q.Table].PrepareSpace(q.Rows)
+	dml.Table(q.DB, q.Table).PrepareSpace(q.Rows)
 
 	return nil, nil, nil
 }
@@ -7,9 +7,8 @@ import (
 )
 
 func (dml *DML) dmlPrepareValidate(q *dml.QueryPrepare) error {
-	_, ok := dml.Tables[q.Table]
-	if !ok {
-		return fmt.Errorf("table not found: '%s'", q.Table)
+	if _, err := dml.LookupWritableTable(q.DB, q.Table); err != nil {
+		return err
 	}
 
 	if q.Rows < 0 {
@@ -0,0 +1,55 @@
+package dml
+
+import (
+	"go-dbms/pkg/table"
+	"go-dbms/pkg/types"
+	"go-dbms/services/parser/query/dml/eval"
+	"go-dbms/services/parser/query/dml/projection"
+	"go-dbms/util/helpers"
+)
+
+// tableColumns returns set of names of columns of table.
+func tableColumns(t table.ITable) map[string]struct{} {
+	columns := map[string]struct{}{}
+	for _, col := range t.Columns() {
+		columns[col.Name] = struct{}{}
+	}
+	return columns
+}
+
+// validateReturning checks that projections of RETURNING reference only columns of table.
+func (dmlt *DML) validateReturning(t table.ITable, prs *projection.Projections) (err error) {
+	defer helpers.RecoverOnError(&err)()
+
+	if prs == nil {
+		return nil
+	}
+
+	columns := tableColumns(t)
+	for _, p := range prs.Iterator() {
+		dmlt.validateColumns(columns, p)
+	}
+	return nil
+}
+
+// returnedProjections returns projections of rows returned by
+// INSERT, UPDATE or DELETE, primary key without RETURNING.
+func returnedProjections(t table.ITable, prs *projection.Projections) *projection.Projections {
+	if prs == nil {
+		return projection.FromCols(t.PrimaryColumns())
+	}
+	return prs
+}
+
+// evalReturning evaluates projections of RETURNING on row of table. Result is
+// new row, so alias equal to name of column doesn't change value of column.
+func evalReturning(prs *projection.Projections, row types.DataRow) types.DataRow {
+	if prs == nil {
+		return row
+	}
+	res := make(types.DataRow, len(prs.Iterator()))
+	for _, p := range prs.Iterator() {
+		res[p.Alias] = eval.Eval(row, p)
+	}
+	return res
+}
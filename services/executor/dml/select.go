@@ -2,12 +2,16 @@ package dml
 
 import (
 	"cmp"
+	"slices"
 
+	"go-dbms/pkg/statement"
 	"go-dbms/pkg/types"
+	"go-dbms/pkg/types/hashed"
 	"go-dbms/services/executor/parent"
 	"go-dbms/services/parser/query/dml"
 	"go-dbms/services/parser/query/dml/eval"
 	"go-dbms/services/parser/query/dml/group"
+	"go-dbms/services/parser/query/dml/order"
 	"go-dbms/services/parser/query/dml/projection"
 	"go-dbms/util/helpers"
 	"go-dbms/util/stream"
@@ -20,21 +24,53 @@ func (dmlt *DML) Select(q *dml.QuerySelect, es parent.Executor) (
 	*projection.Projections,
 	error,
 ) {
+	if err := resolveLists(es, q.Where, q.WhereIndex, q.Projections.Iterator()...); err != nil {
+		return nil, nil, err
+	}
 	if err := dmlt.dmlSelectValidate(q); err != nil {
 		return nil, nil, errors.Wrapf(err, "validation error")
 	}
 
-	dst := stream.New[types.DataRow](1)
+	// source is opened before rows are processed, so error of opening it is returned
+	orderIdx, reverse, ordered := dmlt.orderIndex(q)
+	s, err := dmlt.selectSource(q, es, orderIdx, reverse, ordered)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	out := stream.New[types.DataRow](1)
+	dst := probeOf(es, q, "").writer(out)
+
+	if q.Limit != nil {
+		src := stream.New[types.DataRow](1)
+		go dmlt.limit(q.Limit, src, probeOf(es, q, opLimit).writer(dst))
+		dst = src
+	}
+
+	if len(q.OrderBy) > 0 && !ordered {
+		src := stream.New[types.DataRow](1)
+		go dmlt.order(q.OrderBy, src, probeOf(es, q, opSort).writer(dst))
+		dst = src
+	}
+
+	prs := selectProjections(q)
+	if q.Distinct {
+		src := stream.New[types.DataRow](1)
+		go dmlt.distinct(q.Projections, prs, src, probeOf(es, q, opDistinct).writer(dst))
+		dst = src
+	}
 
 	var gr *group.Group
-	if len(q.Projections.Aggregators()) != 0 {
-		gr = group.New(q.Projections, q.GroupBy, dst)
+	grProbe := probeOf(es, q, opGroup)
+	if len(prs.Aggregators()) != 0 || len(q.GroupBy) != 0 {
+		gr = group.New(prs, groupKeys(q), q.Having, grProbe.writer(dst))
 	}
+	filter := probeOf(es, q, opFilter)
 
 	go func() {
 		defer dst.Close()
 
-		nonAggr := q.Projections.NonAggregators()
+		nonAggr := prs.NonAggregators()
 		prList := q.Projections.Iterator()
 
 		for _, pr := range prList {
@@ -53,7 +89,6 @@ func (dmlt *DML) Select(q *dml.QuerySelect, es parent.Executor) (
 					val = row[p.GetByIndex(0).Alias]
 				}
 
-				pr.Type = projection.LITERAL
 				pr.Literal = val
 			}
 		}
@@ -68,14 +103,17 @@ func (dmlt *DML) Select(q *dml.QuerySelect, es parent.Executor) (
 					}
 				} else {
 					for _, i := range nonAggr {
-						p := q.Projections.GetByIndex(i)
+						p := prs.GetByIndex(i)
 						row[p.Alias] = eval.Eval(row, p)
 					}
 				}
 
 				if q.Where != nil && !q.Where.Compare(row) {
 					continue
-				} else if gr != nil {
+				}
+
+				filter.add()
+				if gr != nil {
 					gr.Add(row)
 					continue
 				}
@@ -86,34 +124,208 @@ func (dmlt *DML) Select(q *dml.QuerySelect, es parent.Executor) (
 					s.Continue(false)
 				}
 			}
+			filter.done()
 			return nil
 		}
 
-		var s stream.ReaderContinue[types.DataRow]
-		if q.From.Type == dml.FROM_SUBQUERY {
-			var err error
-			s, _, err = es.Exec(q.From.SubQuery)
-			if err != nil {
-				panic(err)
-			}
-		} else {
-			t := dmlt.Tables[q.From.Table]
-			if q.WhereIndex != nil {
-				s = helpers.MustVal(t.ScanByIndex(
-					q.UseIndex,
-					q.WhereIndex.FilterStart,
-					q.WhereIndex.FilterEnd,
-				))
-			} else {
-				s = helpers.MustVal(t.FullScanByIndex(cmp.Or(q.UseIndex, t.PrimaryKey()), false))
+		s = probeOf(es, q, opScan).reader(s)
+
+		if len(q.From.Joins) != 0 {
+			js := dmlt.joinSources(q)
+			for i, j := range q.From.Joins {
+				joined := stream.New[types.DataRow](1)
+				go dmlt.join(js, j, i+1, s, probeOf(es, q, opJoin(i+1)).writer(joined), es)
+				s = joined
 			}
 		}
 
 		helpers.Must(process(s))
 		if gr != nil {
 			gr.Flush()
+			gr.Close()
+			grProbe.done()
 		}
 	}()
 
-	return dst, q.Projections, nil
+	return out, q.Projections, nil
+}
+
+// order sorts rows from src and pushes them to dst.
+func (dmlt *DML) order(items []*order.Item, src stream.ReaderContinue[types.DataRow], dst stream.WriterContinue[types.DataRow]) {
+	defer dst.Close()
+
+	o := order.New(items, dst)
+	for row, ok := src.Pop(); ok; row, ok = src.Pop() {
+		src.Continue(true)
+		helpers.Must(o.Add(row))
+	}
+	helpers.Must(o.Flush())
+}
+
+// distinct pushes rows from src to dst skipping rows with duplicate
+// projection values. Rows are trimmed to prs values.
+func (dmlt *DML) distinct(
+	projections, prs *projection.Projections,
+	src stream.ReaderContinue[types.DataRow],
+	dst stream.WriterContinue[types.DataRow],
+) {
+	defer dst.Close()
+
+	set := hashed.NewSet(hashed.DefaultMemLimit)
+	defer set.Close()
+
+	vals := make([]types.DataType, len(projections.Iterator()))
+	for row, ok := src.Pop(); ok; row, ok = src.Pop() {
+		for i, p := range projections.Iterator() {
+			vals[i] = row[p.Alias]
+		}
+
+		trimmed := make(types.DataRow, len(prs.Iterator()))
+		for _, p := range prs.Iterator() {
+			trimmed[p.Alias] = row[p.Alias]
+		}
+
+		if r := helpers.MustVal(set.Add(hashed.Key(vals...), trimmed)); r != nil {
+			dst.Push(r)
+			if !dst.ShouldContinue() {
+				src.Continue(false)
+				return
+			}
+		}
+		src.Continue(true)
+	}
+
+	helpers.Must(set.Flush(func(row types.DataRow) bool {
+		dst.Push(row)
+		return !dst.ShouldContinue()
+	}))
+}
+
+// limit skips first l.Offset rows from src and pushes next l.Count rows
+// to dst, after what src is stopped.
+func (dmlt *DML) limit(l *dml.Limit, src stream.ReaderContinue[types.DataRow], dst stream.WriterContinue[types.DataRow]) {
+	defer dst.Close()
+
+	offset, n := l.Offset, 0
+	for row, ok := src.Pop(); ok; row, ok = src.Pop() {
+		if offset > 0 {
+			offset--
+			src.Continue(true)
+			continue
+		} else if n >= l.Count {
+			src.Continue(false)
+			continue
+		}
+
+		dst.Push(row)
+		n++
+		src.Continue(dst.ShouldContinue() && n < l.Count)
+	}
+}
+
+// selectSource opens stream of rows read from source of query.
+func (dmlt *DML) selectSource(
+	q *dml.QuerySelect,
+	es parent.Executor,
+	orderIdx string,
+	reverse, ordered bool,
+) (stream.ReaderContinue[types.DataRow], error) {
+	if q.From.Type == dml.FROM_SUBQUERY {
+		s, _, err := es.Exec(q.From.SubQuery)
+		return s, err
+	}
+
+	t := dmlt.Table(q.From.DB, q.From.Table)
+	if ordered {
+		return t.FullScanByIndex(orderIdx, reverse)
+	} else if q.WhereIndex != nil {
+		return t.ScanByIndex(q.UseIndex, q.WhereIndex.FilterStart, q.WhereIndex.FilterEnd)
+	}
+	return t.FullScanByIndex(cmp.Or(q.UseIndex, t.PrimaryKey()), false)
+}
+
+// orderIndex checks if rows can be read already ordered from
+// the index used by query, so sorting can be skipped.
+func (dmlt *DML) orderIndex(q *dml.QuerySelect) (name string, reverse bool, ok bool) {
+	if len(q.OrderBy) == 0 || q.Distinct || q.From.Type != dml.FROM_SCHEMA || len(q.From.Joins) != 0 ||
+		q.WhereIndex != nil || len(q.Projections.Aggregators()) != 0 || len(q.GroupBy) != 0 {
+		return "", false, false
+	}
+
+	t := dmlt.Table(q.From.DB, q.From.Table)
+	name = cmp.Or(q.UseIndex, t.PrimaryKey())
+	meta := t.IndexMeta(name)
+	if meta == nil || len(q.OrderBy) > len(meta.Columns) {
+		return "", false, false
+	}
+
+	reverse = q.OrderBy[0].Desc
+	for i, it := range q.OrderBy {
+		p := it.Projection
+		if pr, _, found := q.Projections.GetByAlias(p.Name); found && p.Type == projection.IDENTIFIER {
+			p = pr
+		}
+
+		if p.Type != projection.IDENTIFIER || p.Name != meta.Columns[i] || it.Desc != reverse {
+			return "", false, false
+		}
+	}
+
+	return name, reverse, true
+}
+
+// groupKeys returns expressions rows are grouped by, non aggregated
+// projections are added to them, their values are evaluated into row before grouping.
+func groupKeys(q *dml.QuerySelect) []*projection.Projection {
+	keys := slices.Clone(q.GroupBy)
+	for _, p := range q.Projections.Iterator() {
+		if p.Type == projection.AGGREGATOR {
+			continue
+		}
+
+		if !slices.ContainsFunc(keys, func(k *projection.Projection) bool { return k.Alias == p.Alias }) {
+			keys = append(keys, &projection.Projection{Alias: p.Alias, Name: p.Alias, Type: projection.IDENTIFIER})
+		}
+	}
+	return keys
+}
+
+// selectProjections returns projections of query with hidden aggregators
+// of ORDER BY and HAVING, which are calculated by group but not returned.
+func selectProjections(q *dml.QuerySelect) *projection.Projections {
+	prs := q.Projections.Copy()
+	for _, it := range q.OrderBy {
+		addHiddenAggregators(prs, it.Projection)
+	}
+	addHiddenAggregatorsWhere(prs, q.Having)
+	return prs
+}
+
+func addHiddenAggregators(prs *projection.Projections, p *projection.Projection) {
+	if prs.Has(p.Alias) {
+		return
+	} else if p.Type == projection.AGGREGATOR {
+		prs.Add(p)
+		return
+	}
+
+	for _, arg := range p.Operands() {
+		addHiddenAggregators(prs, arg)
+	}
+}
+
+func addHiddenAggregatorsWhere(prs *projection.Projections, ws *statement.WhereStatement) {
+	if ws == nil {
+		return
+	} else if ws.Statement != nil {
+		addHiddenAggregators(prs, ws.Statement.Left)
+		addHiddenAggregators(prs, ws.Statement.Right)
+	}
+
+	for _, w := range ws.And {
+		addHiddenAggregatorsWhere(prs, w)
+	}
+	for _, w := range ws.Or {
+		addHiddenAggregatorsWhere(prs, w)
+	}
 }
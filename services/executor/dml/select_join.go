@@ -0,0 +1,378 @@
+package dml
+
+import (
+	"slices"
+
+	"go-dbms/pkg/index"
+	"go-dbms/pkg/statement"
+	"go-dbms/pkg/table"
+	"go-dbms/pkg/types"
+	"go-dbms/services/executor/parent"
+	"go-dbms/services/parser/query/dml"
+	"go-dbms/services/parser/query/dml/eval"
+	"go-dbms/services/parser/query/dml/join"
+	"go-dbms/services/parser/query/dml/projection"
+	"go-dbms/util/helpers"
+	"go-dbms/util/stream"
+)
+
+// source describes columns of one of FROM/JOIN sources of select query.
+type source struct {
+	name    string
+	columns []string
+}
+
+// joinSources holds sources of select query with joins. Joined rows contain
+// columns qualified by source name and unqualified ones if they are unambiguous.
+type joinSources struct {
+	list   []*source
+	counts map[string]int
+}
+
+// equiCondition is an equality of expressions from outer and inner sides of join.
+type equiCondition struct {
+	outer, inner *projection.Projection
+	statement    *statement.Statement
+}
+
+func (dmlt *DML) joinSources(q *dml.QuerySelect) *joinSources {
+	js := &joinSources{
+		list:   []*source{dmlt.source(q.From)},
+		counts: map[string]int{},
+	}
+	for _, j := range q.From.Joins {
+		js.list = append(js.list, dmlt.source(j.Source))
+	}
+
+	for _, src := range js.list {
+		for _, col := range src.columns {
+			js.counts[col]++
+		}
+	}
+	return js
+}
+
+func (dmlt *DML) source(f dml.From) *source {
+	src := &source{name: f.Name(), columns: []string{}}
+	if f.Type == dml.FROM_SUBQUERY {
+		for _, p := range dml.Projections(f.SubQuery).Iterator() {
+			src.columns = append(src.columns, p.Alias)
+		}
+	} else {
+		for _, col := range dmlt.Table(f.DB, f.Table).Columns() {
+			src.columns = append(src.columns, col.Name)
+		}
+	}
+	return src
+}
+
+// sourceColumns returns set of column names available in rows read from
+// query sources.
+func (dmlt *DML) sourceColumns(q *dml.QuerySelect) map[string]struct{} {
+	columns := map[string]struct{}{}
+	if len(q.From.Joins) == 0 {
+		for _, col := range dmlt.source(q.From).columns {
+			columns[col] = struct{}{}
+		}
+		return columns
+	}
+
+	js := dmlt.joinSources(q)
+	for _, src := range js.list {
+		for _, col := range src.columns {
+			columns[src.name+"."+col] = struct{}{}
+			if js.counts[col] == 1 {
+				columns[col] = struct{}{}
+			}
+		}
+	}
+	return columns
+}
+
+func (js *joinSources) qualify(row types.DataRow, src *source) types.DataRow {
+	res := make(types.DataRow, 2*len(src.columns))
+	for _, col := range src.columns {
+		res[src.name+"."+col] = row[col]
+		if js.counts[col] == 1 {
+			res[col] = row[col]
+		}
+	}
+	return res
+}
+
+// sourceOf returns index of source containing column, -1 if not found.
+func (js *joinSources) sourceOf(name string) int {
+	for i, src := range js.list {
+		for _, col := range src.columns {
+			if name == src.name+"."+col || name == col && js.counts[col] == 1 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// side returns lowest and highest indexes of sources referenced by projection,
+// ok is false if projection can't be evaluated against single row.
+func (js *joinSources) side(p *projection.Projection) (low, high int, ok bool) {
+	switch p.Type {
+		case projection.IDENTIFIER:
+			i := js.sourceOf(p.Name)
+			return i, i, i != -1
+		case projection.LITERAL:
+			return len(js.list), -1, true
+		case projection.FUNCTION, projection.CASE:
+			low, high = len(js.list), -1
+			for _, arg := range p.Operands() {
+				l, h, ok := js.side(arg)
+				if !ok {
+					return 0, 0, false
+				}
+				low, high = min(low, l), max(high, h)
+			}
+			return low, high, true
+	}
+	return 0, 0, false
+}
+
+// splitOn splits ON condition of i-th source into equality conditions between
+// inner and outer sides and rest of conditions.
+func (js *joinSources) splitOn(on *statement.WhereStatement, i int) ([]*equiCondition, []*statement.WhereStatement) {
+	leaves := []*statement.WhereStatement{on}
+	if on.Statement == nil && len(on.And) != 0 && !on.Not {
+		leaves = on.And
+	}
+
+	eqs := []*equiCondition{}
+	rest := []*statement.WhereStatement{}
+	for _, ws := range leaves {
+		if ws.Statement == nil || ws.Not || ws.Statement.Op != types.Equal {
+			rest = append(rest, ws)
+			continue
+		}
+
+		st := ws.Statement
+		lLow, lHigh, lOk := js.side(st.Left)
+		rLow, rHigh, rOk := js.side(st.Right)
+		if !lOk || !rOk || lHigh == -1 || rHigh == -1 {
+			rest = append(rest, ws)
+		} else if lHigh < i && rLow == i && rHigh == i {
+			eqs = append(eqs, &equiCondition{outer: st.Left, inner: st.Right, statement: st})
+		} else if rHigh < i && lLow == i && lHigh == i {
+			eqs = append(eqs, &equiCondition{outer: st.Right, inner: st.Left, statement: st})
+		} else {
+			rest = append(rest, ws)
+		}
+	}
+	return eqs, rest
+}
+
+// lookupIndex searches index of inner table which prefix columns are compared
+// with outer side, so rows can be looked up for every outer row.
+func (js *joinSources) lookupIndex(
+	t table.ITable,
+	src *source,
+	eqs []*equiCondition,
+) (name string, used []*equiCondition) {
+	byColumn := map[string]*equiCondition{}
+	for _, eq := range eqs {
+		if eq.inner.Type != projection.IDENTIFIER {
+			continue
+		}
+		for _, col := range src.columns {
+			if eq.inner.Name == src.name+"."+col || eq.inner.Name == col {
+				byColumn[col] = eq
+			}
+		}
+	}
+
+	for _, meta := range t.IndexesMeta() {
+		prefix := []*equiCondition{}
+		for _, col := range meta.Columns {
+			eq, ok := byColumn[col]
+			if !ok {
+				break
+			}
+			prefix = append(prefix, eq)
+		}
+
+		if len(prefix) > len(used) {
+			name, used = meta.Name, prefix
+		}
+	}
+	return name, used
+}
+
+func residual(eqs []*equiCondition, rest []*statement.WhereStatement) *statement.WhereStatement {
+	list := append([]*statement.WhereStatement{}, rest...)
+	for _, eq := range eqs {
+		list = append(list, statement.WhereS(eq.statement))
+	}
+
+	switch len(list) {
+		case 0:  return nil
+		case 1:  return list[0]
+		default: return &statement.WhereStatement{And: list}
+	}
+}
+
+func merge(left, right types.DataRow) types.DataRow {
+	row := make(types.DataRow, len(left)+len(right))
+	for k, v := range left {
+		row[k] = v
+	}
+	for k, v := range right {
+		row[k] = v
+	}
+	return row
+}
+
+// join joins rows from left with i-th source of query and pushes them to dst.
+// Uses index nested loop join if inner table has index on join keys,
+// hash join otherwise.
+func (dmlt *DML) join(
+	js *joinSources,
+	j *dml.Join,
+	i int,
+	left stream.ReaderContinue[types.DataRow],
+	dst stream.WriterContinue[types.DataRow],
+	es parent.Executor,
+) {
+	defer dst.Close()
+
+	src := js.list[i]
+	outer := j.Type == dml.JOIN_LEFT
+	eqs, rest := js.splitOn(j.On, i)
+
+	next := func() (types.DataRow, bool) {
+		row, ok := left.Pop()
+		if ok && i == 1 {
+			row = js.qualify(row, js.list[0])
+		}
+		return row, ok
+	}
+
+	if j.Source.Type == dml.FROM_SCHEMA {
+		t := dmlt.Table(j.Source.DB, j.Source.Table)
+		if name, used := js.lookupIndex(t, src, eqs); name != "" {
+			unused := []*equiCondition{}
+			for _, eq := range eqs {
+				if !slices.Contains(used, eq) {
+					unused = append(unused, eq)
+				}
+			}
+
+			on := residual(unused, rest)
+			cols := t.IndexMeta(name).Columns[:len(used)]
+			for row, ok := next(); ok; row, ok = next() {
+				stop := dmlt.lookupJoin(js, src, t, name, cols, used, on, outer, row, dst)
+				left.Continue(!stop)
+				if stop {
+					break
+				}
+			}
+			return
+		}
+	}
+
+	leftKeys := make([]*projection.Projection, 0, len(eqs))
+	rightKeys := make([]*projection.Projection, 0, len(eqs))
+	for _, eq := range eqs {
+		leftKeys = append(leftKeys, eq.outer)
+		rightKeys = append(rightKeys, eq.inner)
+	}
+
+	h := join.NewHash(leftKeys, rightKeys, residual(nil, rest), outer, join.DefaultMemLimit, dst)
+	defer h.Close()
+
+	var right stream.ReaderContinue[types.DataRow]
+	if j.Source.Type == dml.FROM_SUBQUERY {
+		var err error
+		right, _, err = es.Exec(j.Source.SubQuery)
+		helpers.Must(err)
+	} else {
+		t := dmlt.Table(j.Source.DB, j.Source.Table)
+		right = helpers.MustVal(t.FullScanByIndex(t.PrimaryKey(), false))
+	}
+	for row, ok := right.Pop(); ok; row, ok = right.Pop() {
+		right.Continue(true)
+		helpers.Must(h.Build(js.qualify(row, src)))
+	}
+
+	for row, ok := next(); ok; row, ok = next() {
+		stop := helpers.MustVal(h.Probe(row))
+		left.Continue(!stop)
+		if stop {
+			return
+		}
+	}
+	helpers.Must(h.Flush())
+}
+
+// lookupJoin joins outer row with rows of inner table found by index.
+// Returns true if dst doesn't want to continue.
+func (dmlt *DML) lookupJoin(
+	js *joinSources,
+	src *source,
+	t table.ITable,
+	name string,
+	cols []string,
+	eqs []*equiCondition,
+	on *statement.WhereStatement,
+	outer bool,
+	row types.DataRow,
+	dst stream.WriterContinue[types.DataRow],
+) (stop bool) {
+	matched := false
+	if filter, ok := lookupFilter(t, cols, eqs, row); ok {
+		s := helpers.MustVal(t.ScanByIndex(name, filter, nil))
+		for r, ok := s.Pop(); ok; r, ok = s.Pop() {
+			joined := merge(row, js.qualify(r, src))
+			if on != nil && !on.Compare(joined) {
+				s.Continue(true)
+				continue
+			}
+
+			matched = true
+			dst.Push(joined)
+			if !dst.ShouldContinue() {
+				s.Continue(false)
+				return true
+			}
+			s.Continue(true)
+		}
+	}
+
+	if !matched && outer {
+		dst.Push(row)
+		return !dst.ShouldContinue()
+	}
+	return false
+}
+
+// lookupFilter builds index filter from outer row values,
+// ok is false if some of values can't match any row.
+func lookupFilter(t table.ITable, cols []string, eqs []*equiCondition, row types.DataRow) (*index.Filter, bool) {
+	filter := &index.Filter{
+		Operator:   types.Equal,
+		Conditions: make([]index.FilterCondition, len(cols)),
+	}
+
+	for k, col := range cols {
+		val := eval.Eval(row, eqs[k].outer)
+		if val == nil {
+			return nil, false
+		}
+
+		casted, err := val.Cast(t.Column(col).Meta)
+		if err != nil {
+			return nil, false
+		}
+
+		filter.Conditions[k] = index.FilterCondition{
+			Left:  &projection.Projection{Alias: col, Name: col, Type: projection.IDENTIFIER},
+			Right: &projection.Projection{Type: projection.LITERAL, Literal: casted},
+		}
+	}
+	return filter, true
+}
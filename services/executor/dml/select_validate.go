@@ -4,8 +4,11 @@ import (
 	"fmt"
 
 	"go-dbms/pkg/column"
+	"go-dbms/pkg/index"
 	"go-dbms/pkg/statement"
 	"go-dbms/pkg/table"
+	"go-dbms/pkg/types"
+	"go-dbms/services/parser/kwords"
 	"go-dbms/services/parser/query/dml"
 	"go-dbms/services/parser/query/dml/eval"
 	"go-dbms/services/parser/query/dml/projection"
@@ -19,25 +22,78 @@ func (dmlt *DML) dmlSelectValidate(q *dml.QuerySelect) (err error) {
 
 	dmlt.validateFrom(q)
 	if q.From.Type == dml.FROM_SCHEMA {
-		dmlt.validateUseIndex(dmlt.Tables[q.From.Table], q)
-		dmlt.validateWhereIndex(dmlt.Tables[q.From.Table], q.WhereIndex)
+		dmlt.validateUseIndex(dmlt.Table(q.From.DB, q.From.Table), q)
+		dmlt.validateWhereIndex(dmlt.Table(q.From.DB, q.From.Table), q.WhereIndex)
 	}
 	dmlt.validateProjections(q)
 	dmlt.validateWhere(q.Where)
 	dmlt.validateGroupBy(q)
+	dmlt.validateHaving(q)
+	dmlt.validateOrderBy(q)
 	return nil
 }
 
 func (dmlt *DML) validateFrom(q *dml.QuerySelect) {
-	if q.From.Type == dml.FROM_SCHEMA {
-		if _, ok := dmlt.Tables[q.From.Table]; !ok {
-			panic(fmt.Errorf("table not found: '%s'", q.From.Table))
+	dmlt.validateSource(q.From)
+	if len(q.From.Joins) == 0 {
+		return
+	}
+
+	names := map[string]struct{}{}
+	sources := []dml.From{q.From}
+	for _, j := range q.From.Joins {
+		sources = append(sources, j.Source)
+	}
+
+	for _, src := range sources {
+		if src.Name() == "" {
+			panic(fmt.Errorf("joined subquery must have an alias"))
+		} else if _, ok := names[src.Name()]; ok {
+			panic(fmt.Errorf("not unique table/alias: '%s'", src.Name()))
 		}
-	} else if q.From.Type == dml.FROM_SUBQUERY {
-		if err := dmlt.dmlSelectValidate(q.From.SubQuery.(*dml.QuerySelect)); err != nil {
-			panic(err)
+		names[src.Name()] = struct{}{}
+	}
+
+	for _, j := range q.From.Joins {
+		dmlt.validateSource(j.Source)
+		dmlt.validateOn(q, j.On)
+	}
+}
+
+func (dmlt *DML) validateSource(f dml.From) {
+	if f.Type == dml.FROM_SCHEMA {
+		helpers.MustVal(dmlt.LookupTable(f.DB, f.Table))
+	} else if f.Type == dml.FROM_SUBQUERY {
+		dmlt.validateQuery(f.SubQuery)
+	}
+}
+
+func (dmlt *DML) validateOn(q *dml.QuerySelect, ws *statement.WhereStatement) {
+	if ws.Statement != nil {
+		columns := dmlt.sourceColumns(q)
+		for _, p := range []*projection.Projection{ws.Statement.Left, ws.Statement.Right} {
+			if hasAggregator(p) {
+				panic(fmt.Errorf("aggregators are not allowed in join condition: '%s'", p.Alias))
+			}
+			dmlt.validateColumns(columns, p)
 		}
 	}
+
+	for _, w := range ws.And {
+		dmlt.validateOn(q, w)
+	}
+	for _, w := range ws.Or {
+		dmlt.validateOn(q, w)
+	}
+}
+
+func (dmlt *DML) validateColumns(columns map[string]struct{}, p *projection.Projection) {
+	if _, ok := columns[p.Name]; p.Type == projection.IDENTIFIER && !ok {
+		panic(fmt.Errorf("identifier not found: '%s'", p.Name))
+	}
+	for _, pa := range p.Operands() {
+		dmlt.validateColumns(columns, pa)
+	}
 }
 
 func (dmlt *DML) validateUseIndex(t table.ITable, q *dml.QuerySelect) {
@@ -59,16 +115,12 @@ func (dmlt *DML) validateProjection(
 	p *projection.Projection,
 	index int,
 ) {
-	var t table.ITable
-	var columns map[string]*column.Column
-	if q.From.Type != dml.FROM_SUBQUERY {
-		t = dmlt.Tables[q.From.Table]
-		columns = t.ColumnsMap()
-	}
-	
+	columns := dmlt.sourceColumns(q)
+
 	switch p.Type {
 		case projection.IDENTIFIER:
-			isAlias := q.Projections.Has(p.Name)
+			pr, _, isAlias := q.Projections.GetByAlias(p.Name)
+			isAlias = isAlias && pr != p
 			_, isColumn := columns[p.Name]
 			if !isAlias && !isColumn {
 				panic(fmt.Errorf("identifier not found: '%s'", p.Name))
@@ -76,8 +128,12 @@ func (dmlt *DML) validateProjection(
 
 		case projection.LITERAL: break // do nothing
 
-		case projection.AGGREGATOR, projection.FUNCTION:
-			for _, pa := range p.Arguments {
+		case projection.AGGREGATOR, projection.FUNCTION, projection.LIST, projection.CASE:
+			if p.Distinct && len(p.Arguments) == 0 {
+				panic(fmt.Errorf("distinct aggregator must have arguments: '%s'", p.Alias))
+			}
+
+			for _, pa := range p.Operands() {
 				_, isColumn := columns[pa.Name]
 				paIndex, found := q.Projections.Index(pa.Alias)
 				if !isColumn && found && index <= paIndex {
@@ -87,9 +143,7 @@ func (dmlt *DML) validateProjection(
 			}
 		
 		case projection.SUBQUERY:
-			if err := dmlt.dmlSelectValidate(p.Subquery.(*dml.QuerySelect)); err != nil {
-				panic(err)
-			}
+			dmlt.validateQuery(p.Subquery)
 	}
 }
 
@@ -98,35 +152,41 @@ func (dmlt *DML) validateWhereIndex(t table.ITable, wi *dml.WhereIndex) {
 		return
 	}
 
-	fs := wi.FilterStart
-	if fs != nil {
-		for _, cond := range fs.Conditions {
-			col := t.Column(cond.Left.Alias)
-			casted, err := eval.Eval(nil, cond.Right).Cast(col.Meta)
-			if err != nil {
-				panic(errors.Wrapf(err, "failed to cast %v to %v", col.Meta.GetCode(), col.Typ))
-			}
-	
-			cond.Right.Type = projection.LITERAL
-			cond.Right.Literal = casted
+	for _, f := range []*index.Filter{wi.FilterStart, wi.FilterEnd} {
+		if f == nil {
+			continue
+		} else if _, ok := kwords.IndexOperators[f.Operator]; !ok && f.Operator != types.In {
+			panic(fmt.Errorf("operator '%s' is not supported in where_index", f.Operator))
+		} else if f.Operator == types.In && wi.FilterEnd != nil {
+			panic(fmt.Errorf("operator '%s' can't be used with range in where_index", f.Operator))
 		}
-	}
 
-	fe := wi.FilterEnd
-	if fe != nil {
-		for _, cond := range fe.Conditions {
+		for _, cond := range f.Conditions {
 			col := t.Column(cond.Left.Alias)
-			casted, err := eval.Eval(nil, cond.Right).Cast(col.Meta)
-			if err != nil {
-				panic(errors.Wrapf(err, "failed to cast %v to %v", col.Meta.GetCode(), col.Typ))
+			switch cond.Right.Type {
+				case projection.SUBQUERY: continue // resolved to list before execution
+				case projection.LIST:
+					for _, arg := range cond.Right.Arguments {
+						validateLiteral(arg, col)
+					}
+				default: validateLiteral(cond.Right, col)
 			}
-
-			cond.Right.Type = projection.LITERAL
-			cond.Right.Literal = casted
 		}
 	}
 }
 
+// validateLiteral checks that constant projection can be casted to column type.
+// Projection isn't replaced by casted value, because it can contain query
+// parameters, index scan casts it on every execution.
+func validateLiteral(p *projection.Projection, col *column.Column) {
+	val := eval.Eval(nil, p)
+	if val == nil {
+		panic(fmt.Errorf("NULL can't be used in where_index condition of column '%s'", col.Name))
+	} else if _, err := val.Cast(col.Meta); err != nil {
+		panic(errors.Wrapf(err, "failed to cast %v to %v", col.Meta.GetCode(), col.Typ))
+	}
+}
+
 func (dmlt *DML) validateWhere(w *statement.WhereStatement) {
 	// if w == nil {
 	// 	return
@@ -152,34 +212,113 @@ func (dmlt *DML) validateWhere(w *statement.WhereStatement) {
 }
 
 func (dmlt *DML) validateGroupBy(q *dml.QuerySelect) {
-	if q.From.Type == dml.FROM_SUBQUERY {
-		return
-	}
-
-	t := dmlt.Tables[q.From.Table]
-	columns := t.ColumnsMap()
+	columns := dmlt.sourceColumns(q)
 
-	for groupItem := range q.GroupBy {
-		if pr, _, found := q.Projections.GetByAlias(groupItem); found {
+	for _, item := range q.GroupBy {
+		if pr, _, found := q.Projections.GetByAlias(item.Name); found && item.Type == projection.IDENTIFIER {
 			if pr.Type == projection.AGGREGATOR {
 				panic(fmt.Errorf("can't group by aggregator:'%s'", pr.Alias))
 			}
 		} else {
-			_, isColumn := columns[groupItem]
-			if !isColumn {
-				panic(fmt.Errorf("unknown group item:'%s'", groupItem))
-			}
+			dmlt.validateColumns(columns, item)
 		}
 	}
+}
 
-	for _, p := range q.Projections.Iterator() {
-		if p.Type != projection.AGGREGATOR {
-			if _, ok := q.GroupBy[p.Alias]; !ok {
-				if q.GroupBy == nil {
-					q.GroupBy = map[string]struct{}{}
-				}
-				q.GroupBy[p.Alias] = struct{}{}
+func (dmlt *DML) validateHaving(q *dml.QuerySelect) {
+	if q.Having == nil {
+		return
+	} else if len(q.Projections.Aggregators()) == 0 && !whereHasAggregator(q.Having) {
+		panic(fmt.Errorf("having is allowed only in aggregating queries"))
+	}
+
+	dmlt.validateResultWhere(q, q.Having)
+}
+
+func (dmlt *DML) validateResultWhere(q *dml.QuerySelect, ws *statement.WhereStatement) {
+	if ws.Statement != nil {
+		dmlt.validateResultItem(q, ws.Statement.Left, "having", true, false)
+		dmlt.validateResultItem(q, ws.Statement.Right, "having", true, false)
+	}
+
+	for _, w := range ws.And {
+		dmlt.validateResultWhere(q, w)
+	}
+	for _, w := range ws.Or {
+		dmlt.validateResultWhere(q, w)
+	}
+}
+
+func (dmlt *DML) validateOrderBy(q *dml.QuerySelect) {
+	grouped := len(q.Projections.Aggregators()) != 0 || len(q.GroupBy) != 0 || whereHasAggregator(q.Having) || q.Distinct
+	for _, it := range q.OrderBy {
+		if hasAggregator(it.Projection) {
+			grouped = true
+		}
+	}
+
+	for _, it := range q.OrderBy {
+		dmlt.validateResultItem(q, it.Projection, "order", grouped, false)
+	}
+}
+
+// validateResultItem validates projection evaluated against result rows,
+// in grouped queries identifiers outside of aggregators must be projections.
+func (dmlt *DML) validateResultItem(
+	q *dml.QuerySelect,
+	p *projection.Projection,
+	clause string,
+	grouped, inAggregator bool,
+) {
+	if q.Projections.Has(p.Alias) {
+		return
+	}
+
+	switch p.Type {
+		case projection.IDENTIFIER:
+			if grouped && !inAggregator {
+				panic(fmt.Errorf("%s item must be a projection or an aggregator: '%s'", clause, p.Name))
+			}
+
+			if _, found := dmlt.sourceColumns(q)[p.Name]; !found {
+				panic(fmt.Errorf("identifier not found: '%s'", p.Name))
 			}
+
+		case projection.LITERAL: break // do nothing
+
+		case projection.AGGREGATOR, projection.FUNCTION, projection.LIST, projection.CASE:
+			for _, pa := range p.Operands() {
+				dmlt.validateResultItem(q, pa, clause, grouped, inAggregator || p.Type == projection.AGGREGATOR)
+			}
+
+		case projection.SUBQUERY:
+			panic(fmt.Errorf("subqueries are not supported in %s", clause))
+	}
+}
+
+func hasAggregator(p *projection.Projection) bool {
+	if p.Type == projection.AGGREGATOR {
+		return true
+	}
+	for _, pa := range p.Operands() {
+		if hasAggregator(pa) {
+			return true
+		}
+	}
+	return false
+}
+
+func whereHasAggregator(ws *statement.WhereStatement) bool {
+	if ws == nil {
+		return false
+	} else if ws.Statement != nil && (hasAggregator(ws.Statement.Left) || hasAggregator(ws.Statement.Right)) {
+		return true
+	}
+
+	for _, w := range append(ws.And, ws.Or...) {
+		if whereHasAggregator(w) {
+			return true
 		}
 	}
+	return false
 }
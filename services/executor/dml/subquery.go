@@ -0,0 +1,98 @@
+package dml
+
+import (
+	"fmt"
+
+	"go-dbms/pkg/statement"
+	"go-dbms/pkg/types"
+	"go-dbms/services/executor/parent"
+	"go-dbms/services/parser/query/dml"
+	"go-dbms/services/parser/query/dml/projection"
+	"go-dbms/util/helpers"
+
+	"github.com/pkg/errors"
+)
+
+// resolveLists executes IN subqueries of where statement, where index
+// filters and CASE conditions of projections prs and replaces them with
+// lists of returned values.
+func resolveLists(
+	es parent.Executor,
+	ws *statement.WhereStatement,
+	wi *dml.WhereIndex,
+	prs ...*projection.Projection,
+) (err error) {
+	defer helpers.RecoverOnError(&err)()
+
+	resolveWhereLists(es, ws)
+	for _, p := range prs {
+		resolveProjectionLists(es, p)
+	}
+	if wi != nil {
+		if wi.FilterStart != nil {
+			for _, cond := range wi.FilterStart.Conditions {
+				resolveList(es, cond.Right)
+			}
+		}
+		if wi.FilterEnd != nil {
+			for _, cond := range wi.FilterEnd.Conditions {
+				resolveList(es, cond.Right)
+			}
+		}
+	}
+	return nil
+}
+
+func resolveWhereLists(es parent.Executor, ws *statement.WhereStatement) {
+	if ws == nil {
+		return
+	} else if ws.Statement != nil && (ws.Statement.Op == types.In || ws.Statement.Op == types.NotIn) {
+		resolveList(es, ws.Statement.Right)
+	}
+	if ws.Statement != nil {
+		resolveProjectionLists(es, ws.Statement.Left)
+	}
+
+	for _, w := range append(ws.And, ws.Or...) {
+		resolveWhereLists(es, w)
+	}
+}
+
+func resolveProjectionLists(es parent.Executor, p *projection.Projection) {
+	for _, cond := range p.Conditions {
+		resolveWhereLists(es, cond.(*statement.WhereStatement))
+	}
+	for _, arg := range p.Arguments {
+		resolveProjectionLists(es, arg)
+	}
+}
+
+// resolveList keeps subquery of resolved list, so it's executed
+// again on next execution of prepared query.
+func resolveList(es parent.Executor, p *projection.Projection) {
+	if p.Subquery == nil {
+		return
+	} else if prs := dml.Projections(p.Subquery); prs == nil || len(prs.Iterator()) != 1 {
+		panic(fmt.Errorf("subquery must return exactly one column"))
+	}
+
+	r, prs, err := es.Exec(p.Subquery)
+	if err != nil {
+		panic(errors.Wrap(err, "failed to execute subquery"))
+	}
+
+	alias := prs.GetByIndex(0).Alias
+	args := []*projection.Projection{}
+	for row, ok := r.Pop(); ok; row, ok = r.Pop() {
+		r.Continue(true)
+		args = append(args, &projection.Projection{
+			Alias:   p.Alias,
+			Name:    p.Name,
+			Type:    projection.LITERAL,
+			Literal: row[alias],
+		})
+	}
+
+	p.Type = projection.LIST
+	p.Arguments = args
+}
@@ -5,10 +5,10 @@ import (
 	"go-dbms/services/executor/parent"
 	"go-dbms/services/parser/query/dml"
 	"go-dbms/services/parser/query/dml/projection"
-	"go-dbms/util/helpers"
 	"go-dbms/util/stream"
 
 	"github.com/pkg/errors"
+	"golang.org/x/sync/errgroup"
 )
 
 func (dml *DML) Update(q *dml.QueryUpdate, es parent.Executor) (
@@ -16,39 +16,42 @@ func (dml *DML) Update(q *dml.QueryUpdate, es parent.Executor) (
 	*projection.Projections,
 	error,
 ) {
-	if err := dml.dmlUpdateValidate(q); err != nil {
+	if err := resolveLists(es, q.Where, q.WhereIndex); err != nil {
+		return nil, nil, err
+	}
+	set, err := dml.dmlUpdateValidate(q)
+	if err != nil {
 		return nil, nil, errors.Wrapf(err, "validation error")
 	}
 
-	t := dml.Tables[q.Table]
-	dst := stream.New[types.DataRow](1)
-
-	go func() {
-		defer dst.Close()
-
-		process := func(s stream.Reader[types.DataRow]) error {
-			for row, ok := s.Pop(); ok; row, ok = s.Pop() {
-				dst.Push(row)
-				dst.ShouldContinue() // have no effect but must call because return type is stream.ReaderContinue
-			}
-			return nil
-		}
-
-		var s stream.Reader[types.DataRow]
-		if q.WhereIndex != nil {
-			s = helpers.MustVal(t.UpdateByIndex(
-				q.UseIndex,
-				q.WhereIndex.FilterStart,
-				q.WhereIndex.FilterEnd,
-				q.Where,
-				q.Values,
-			))
-		} else {
-			s = t.Update(q.Where, q.Values)
-		}
+	t := dml.Table(q.DB, q.Table)
+	var (
+		s  stream.Reader[types.DataRow]
+		eg *errgroup.Group
+	)
+	if q.WhereIndex != nil {
+		s, eg = t.UpdateByIndex(
+			q.UseIndex,
+			q.WhereIndex.FilterStart,
+			q.WhereIndex.FilterEnd,
+			q.Where,
+			set,
+		)
+	} else {
+		s, eg = t.Update(q.Where, set)
+	}
 
-		helpers.Must(process(s))
-	}()
+	// update is finished before result is returned, so its error is returned by query
+	rows := []types.DataRow{}
+	p := probeOf(es, q, "")
+	for row, ok := s.Pop(); ok; row, ok = s.Pop() {
+		p.add()
+		rows = append(rows, evalReturning(q.Returning, row))
+	}
+	p.done()
+	if err := eg.Wait(); err != nil {
+		return nil, nil, err
+	}
 
-	return dst, projection.FromCols(t.PrimaryColumns()), nil
+	return rowsStream(rows), returnedProjections(t, q.Returning), nil
 }
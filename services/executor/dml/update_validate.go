@@ -3,33 +3,66 @@ package dml
 import (
 	"fmt"
 
+	"go-dbms/pkg/table"
+	"go-dbms/pkg/types"
 	"go-dbms/services/parser/query/dml"
+	"go-dbms/services/parser/query/dml/eval"
+	"go-dbms/services/parser/query/dml/projection"
+	"go-dbms/util/helpers"
 
 	"github.com/pkg/errors"
 )
 
-func (dml *DML) dmlUpdateValidate(q *dml.QueryUpdate) error {
-	table, ok := dml.Tables[q.Table]
-	if !ok {
-		return fmt.Errorf("table not found: '%s'", q.Table)
+// dmlUpdateValidate validates query and returns assignment, which
+// evaluates new values of columns for each row and casts them to column types.
+func (dmlt *DML) dmlUpdateValidate(q *dml.QueryUpdate) (_ table.Assignment, err error) {
+	defer helpers.RecoverOnError(&err)()
+
+	t, err := dmlt.LookupWritableTable(q.DB, q.Table)
+	if err != nil {
+		return nil, err
+	}
+
+	columns := tableColumns(t)
+	for colName, p := range q.Values {
+		if _, ok := columns[colName]; !ok {
+			return nil, fmt.Errorf("column not found: '%s'", colName)
+		}
+		dmlt.validateColumns(columns, p)
+	}
+
+	dmlt.validateWhereIndex(t, q.WhereIndex)
+	dmlt.validateWhere(q.Where)
+	if err := dmlt.validateReturning(t, q.Returning); err != nil {
+		return nil, err
 	}
 
-	columns := table.ColumnsMap()
-	for colName, v := range q.Values {
+	return func(row types.DataRow) (types.DataRow, error) {
+		return castValues(t, q.Values, row)
+	}, nil
+}
+
+// castValues evaluates values of columns on row and casts them to column types.
+func castValues(t table.ITable, values map[string]*projection.Projection, row types.DataRow) (types.DataRow, error) {
+	casted := types.DataRow{}
+	columns := t.ColumnsMap()
+	for colName, p := range values {
+		v := eval.Eval(row, p)
 		if col, ok := columns[colName]; !ok {
-			return fmt.Errorf("column not found: '%s'", colName)
+			return nil, fmt.Errorf("column not found: '%s'", colName)
+		} else if v == nil {
+			if !col.Nullable {
+				return nil, fmt.Errorf("column can't be null: '%s'", colName)
+			}
+			casted[colName] = nil
 		} else {
-			casted, err := v.Cast(col.Meta)
+			cv, err := v.Cast(col.Meta)
 			if err != nil {
-				return errors.Wrapf(err, "failed to cast %v to %v", v.GetCode(), col.Typ)
+				return nil, errors.Wrapf(err, "failed to cast %v to %v", v.GetCode(), col.Typ)
 			}
 
-			q.Values[colName] = casted
+			casted[colName] = cv
 		}
 	}
-
-	dml.validateWhereIndex(table, q.WhereIndex)
-	dml.validateWhere(q.Where)
-
-	return nil
+	return casted, nil
 }
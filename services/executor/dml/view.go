@@ -0,0 +1,259 @@
+package dml
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+
+	"go-dbms/pkg/statement"
+	"go-dbms/services/executor/parent"
+	"go-dbms/services/parser/query"
+	"go-dbms/services/parser/query/dml"
+	"go-dbms/services/parser/query/dml/projection"
+	"go-dbms/util/helpers"
+
+	"github.com/pkg/errors"
+)
+
+// ExpandViews returns query with views used in FROM of query and its subqueries
+// replaced by subqueries parsed from text of views. Query itself isn't changed,
+// so prepared query sees views as they are at each execution.
+func (dmlt *DML) ExpandViews(q query.Querier) (expanded query.Querier, err error) {
+	defer helpers.RecoverOnError(&err)()
+
+	return dmlt.expandViews(q, nil), nil
+}
+
+// Validate expands views of select query and validates it without execution.
+func (dmlt *DML) Validate(q query.Querier) (err error) {
+	defer helpers.RecoverOnError(&err)()
+
+	return dmlt.validate(dmlt.expandViews(q, nil))
+}
+
+func (dmlt *DML) validate(q query.Querier) (err error) {
+	defer helpers.RecoverOnError(&err)()
+
+	dmlt.validateQuery(q)
+	return nil
+}
+
+// expandViews returns copy of query with expanded views, views are names of
+// views being expanded, so view referencing itself is detected. Only parts of
+// query containing views are copied, query is returned as is without views.
+func (dmlt *DML) expandViews(q query.Querier, views []string) query.Querier {
+	switch q := q.(type) {
+		case *dml.QuerySelect:
+			from := dmlt.expandSource(&q.From, views)
+			prs := dmlt.expandProjections(q.Projections, views)
+			where := dmlt.expandWhere(q.Where, views)
+			having := dmlt.expandWhere(q.Having, views)
+			if from == &q.From && prs == q.Projections && where == q.Where && having == q.Having {
+				return q
+			}
+			cp := *q
+			cp.From, cp.Projections, cp.Where, cp.Having = *from, prs, where, having
+			return &cp
+
+		case *dml.QueryCompound:
+			left, right := dmlt.expandViews(q.Left, views), dmlt.expandViews(q.Right, views)
+			if left == q.Left && right == q.Right {
+				return q
+			}
+			cp := *q
+			cp.Left, cp.Right = left, right
+			return &cp
+
+		case *dml.QueryInsert:
+			if q.Select == nil {
+				return q
+			}
+			sel := dmlt.expandViews(q.Select, views)
+			if sel == q.Select {
+				return q
+			}
+			cp := *q
+			cp.Select = sel
+			return &cp
+
+		case *dml.QueryUpdate:
+			values, changed := q.Values, false
+			for col, p := range q.Values {
+				if e := dmlt.expandProjection(p, views); e != p {
+					if !changed {
+						values, changed = maps.Clone(q.Values), true
+					}
+					values[col] = e
+				}
+			}
+			where := dmlt.expandWhere(q.Where, views)
+			if !changed && where == q.Where {
+				return q
+			}
+			cp := *q
+			cp.Values, cp.Where = values, where
+			return &cp
+
+		case *dml.QueryDelete:
+			where := dmlt.expandWhere(q.Where, views)
+			if where == q.Where {
+				return q
+			}
+			cp := *q
+			cp.Where = where
+			return &cp
+
+		case *dml.QueryExplain:
+			target := dmlt.expandViews(q.Target, views)
+			if target == q.Target {
+				return q
+			}
+			cp := *q
+			cp.Target = target
+			return &cp
+	}
+	return q
+}
+
+// expandSource returns copy of source with view replaced by subquery aliased
+// by name of view, f is returned if there are no views in source and its joins.
+// Query of view is validated, so errors of view are reported with its name.
+func (dmlt *DML) expandSource(f *dml.From, views []string) *dml.From {
+	cp := *f
+	var changed bool
+	cp.Joins, changed = expandEach(f.Joins, func(j *dml.Join) *dml.Join {
+		src := dmlt.expandSource(&j.Source, views)
+		if src == &j.Source {
+			return j
+		}
+		jc := *j
+		jc.Source = *src
+		return &jc
+	})
+
+	if f.Type == dml.FROM_SUBQUERY {
+		cp.SubQuery = dmlt.expandViews(f.SubQuery, views)
+		if !changed && cp.SubQuery == f.SubQuery {
+			return f
+		}
+		return &cp
+	}
+
+	v, name, ok := dmlt.view(f)
+	if !ok && !changed {
+		return f
+	} else if !ok {
+		return &cp
+	} else if slices.Contains(views, name) {
+		panic(fmt.Errorf("view references itself: '%s'", f.Table))
+	}
+
+	sq, err := dmlt.parser.Use(v.Database).Parse([]byte(v.Query))
+	if err != nil {
+		panic(errors.Wrapf(err, "failed to parse view: '%s'", f.Table))
+	}
+	sq = dmlt.expandViews(sq, append(views, name))
+	if err := dmlt.validate(sq); err != nil {
+		panic(errors.Wrapf(err, "invalid view: '%s'", f.Table))
+	}
+
+	cp.Type, cp.SubQuery, cp.Alias = dml.FROM_SUBQUERY, sq, cmp.Or(f.Alias, f.Table)
+	cp.DB, cp.Table = "", ""
+	return &cp
+}
+
+// view returns view used as source and its name qualified by database.
+func (dmlt *DML) view(f *dml.From) (v *parent.View, name string, ok bool) {
+	db, ok := dmlt.DB(f.DB)
+	if !ok {
+		return nil, "", false
+	}
+	v, ok = db.Views[f.Table]
+	return v, db.Name + "." + f.Table, ok
+}
+
+func (dmlt *DML) expandWhere(ws *statement.WhereStatement, views []string) *statement.WhereStatement {
+	if ws == nil {
+		return nil
+	}
+
+	cp := *ws
+	changed := false
+	if st := ws.Statement; st != nil {
+		left, right := dmlt.expandProjection(st.Left, views), dmlt.expandProjection(st.Right, views)
+		if left != st.Left || right != st.Right {
+			cp.Statement = &statement.Statement{Left: left, Op: st.Op, Right: right}
+			changed = true
+		}
+	}
+
+	expand := func(w *statement.WhereStatement) *statement.WhereStatement { return dmlt.expandWhere(w, views) }
+	and, andChanged := expandEach(ws.And, expand)
+	or, orChanged := expandEach(ws.Or, expand)
+	if !changed && !andChanged && !orChanged {
+		return ws
+	}
+	cp.And, cp.Or = and, or
+	return &cp
+}
+
+func (dmlt *DML) expandProjection(p *projection.Projection, views []string) *projection.Projection {
+	if p == nil {
+		return nil
+	}
+
+	cp := *p
+	changed := false
+	if p.Subquery != nil {
+		cp.Subquery = dmlt.expandViews(p.Subquery, views)
+		changed = cp.Subquery != p.Subquery
+	}
+
+	conds, condsChanged := expandEach(p.Conditions, func(c projection.Condition) projection.Condition {
+		return dmlt.expandWhere(c.(*statement.WhereStatement), views)
+	})
+	args, argsChanged := expandEach(p.Arguments, func(arg *projection.Projection) *projection.Projection {
+		return dmlt.expandProjection(arg, views)
+	})
+	if !changed && !condsChanged && !argsChanged {
+		return p
+	}
+	cp.Conditions, cp.Arguments = conds, args
+	return &cp
+}
+
+// expandProjections returns projections with expanded views, prs is returned if there are no views.
+func (dmlt *DML) expandProjections(prs *projection.Projections, views []string) *projection.Projections {
+	if prs == nil {
+		return nil
+	}
+
+	list, changed := expandEach(prs.Iterator(), func(p *projection.Projection) *projection.Projection {
+		return dmlt.expandProjection(p, views)
+	})
+	if !changed {
+		return prs
+	}
+
+	cp := projection.New()
+	for _, p := range list {
+		cp.Add(p)
+	}
+	return cp
+}
+
+// expandEach returns items expanded by fn. Items are copied only if
+// some item is changed, which is reported by changed.
+func expandEach[T comparable](items []T, fn func(T) T) (expanded []T, changed bool) {
+	expanded = items
+	for i, item := range items {
+		if e := fn(item); e != item {
+			if !changed {
+				expanded, changed = slices.Clone(items), true
+			}
+			expanded[i] = e
+		}
+	}
+	return expanded, changed
+}
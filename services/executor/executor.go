@@ -7,17 +7,28 @@ import (
 	"go-dbms/services/executor/ddl"
 	"go-dbms/services/executor/dml"
 	"go-dbms/services/executor/parent"
+	eshow "go-dbms/services/executor/show"
+	"go-dbms/services/executor/system"
 	"go-dbms/services/parser/query"
+	"go-dbms/services/parser/query/ddl/alter"
 	"go-dbms/services/parser/query/ddl/create"
+	"go-dbms/services/parser/query/ddl/drop"
+	"go-dbms/services/parser/query/ddl/rename"
+	"go-dbms/services/parser/query/ddl/truncate"
 	pdml "go-dbms/services/parser/query/dml"
 	"go-dbms/services/parser/query/dml/projection"
+	"go-dbms/services/parser/query/show"
 	"go-dbms/util/stream"
+
+	"github.com/pkg/errors"
 )
 
 type ExecutorService struct {
-	es  *parent.ExecutorService
-	dml *dml.DML
-	ddl *ddl.DDL
+	es   *parent.ExecutorService
+	dml  *dml.DML
+	ddl  *ddl.DDL
+	show *eshow.Show
+	sys  *system.System
 }
 
 func New(dataPath string) (*ExecutorService, error) {
@@ -26,27 +37,71 @@ func New(dataPath string) (*ExecutorService, error) {
 		return nil, err
 	}
 
+	sys := system.New(es)
+	if err := es.AddDatabase(sys.Database()); err != nil {
+		return nil, errors.Wrap(err, "failed to add system database")
+	}
+
 	return &ExecutorService{
-		es:  es,
-		dml: dml.New(es),
-		ddl: ddl.New(es),
+		es:   es,
+		dml:  dml.New(es),
+		ddl:  ddl.New(es),
+		show: eshow.New(es),
+		sys:  sys,
 	}, nil
 }
 
+// SetConnections sets function, which returns connections
+// of server shown by system.connections.
+func (es *ExecutorService) SetConnections(fn func() []system.Connection) {
+	es.sys.Connections = fn
+}
+
 func (es *ExecutorService) Exec(q query.Querier) (
 	stream.ReaderContinue[types.DataRow],
 	*projection.Projections,
 	error,
 ) {
+	q, err := es.dml.ExpandViews(q)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "validation error")
+	}
+
 	switch q.GetType() {
-		case query.CREATE:  return es.ddl.Create(q.(create.Creater), es)
-		case query.DELETE:  return es.dml.Delete(q.(*pdml.QueryDelete), es)
-		case query.INSERT:  return es.dml.Insert(q.(*pdml.QueryInsert), es)
-		case query.SELECT:  return es.dml.Select(q.(*pdml.QuerySelect), es)
-		case query.UPDATE:  return es.dml.Update(q.(*pdml.QueryUpdate), es)
-		case query.PREPARE: return es.dml.Prepare(q.(*pdml.QueryPrepare), es)
-		default:            panic(fmt.Errorf("invalid query type: '%s'", q.GetType()))
+		case query.CREATE:   return es.ddl.Create(q.(create.Creater), es)
+		case query.ALTER:    return es.ddl.Alter(q.(*alter.QueryAlterTable), es)
+		case query.DROP:     return es.ddl.Drop(q.(drop.Dropper), es)
+		case query.TRUNCATE: return es.ddl.Truncate(q.(*truncate.QueryTruncateTable), es)
+		case query.RENAME:   return es.ddl.Rename(q.(*rename.QueryRenameTable), es)
+		case query.DELETE:   return es.dml.Delete(q.(*pdml.QueryDelete), es)
+		case query.INSERT:   return es.dml.Insert(q.(*pdml.QueryInsert), es)
+		case query.SELECT:   return es.dml.Select(q.(*pdml.QuerySelect), es)
+		case query.UPDATE:   return es.dml.Update(q.(*pdml.QueryUpdate), es)
+		case query.PREPARE:  return es.dml.Prepare(q.(*pdml.QueryPrepare), es)
+		case query.COMPOUND: return es.dml.Compound(q.(*pdml.QueryCompound), es)
+		case query.EXPLAIN:  return es.dml.Explain(q.(*pdml.QueryExplain), es)
+		case query.SHOW:     return es.show.Show(q.(show.Shower))
+		case query.USE:      return es.use(q.(*query.QueryUse))
+		default:             panic(fmt.Errorf("invalid query type: '%s'", q.GetType()))
+	}
+}
+
+// Validate checks select query without executing it, views it references are expanded.
+func (es *ExecutorService) Validate(q query.Querier) error {
+	return es.dml.Validate(q)
+}
+
+// use checks that database exists, connection switches
+// to database after query succeeds.
+func (es *ExecutorService) use(q *query.QueryUse) (
+	stream.ReaderContinue[types.DataRow],
+	*projection.Projections,
+	error,
+) {
+	if _, ok := es.es.DB(q.DB); !ok {
+		return nil, nil, fmt.Errorf("database not found: '%s'", q.DB)
 	}
+	return nil, nil, nil
 }
 
 func (es *ExecutorService) Close() {
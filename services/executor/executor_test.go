@@ -0,0 +1,435 @@
+package executor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"go-dbms/pkg/table"
+	"go-dbms/pkg/types"
+	"go-dbms/services/parser"
+	"go-dbms/services/parser/query"
+
+	"github.com/stretchr/testify/require"
+)
+
+type session struct {
+	t  *testing.T
+	es *ExecutorService
+	ps query.Parser
+}
+
+// newSession opens executor in temporary directory and uses new database d.
+func newSession(t *testing.T) *session {
+	es, err := New(t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(es.Close)
+
+	s := &session{t: t, es: es, ps: parser.New()}
+	s.exec("CREATE DATABASE d")
+	s.exec("USE d")
+	return s
+}
+
+// query executes sql and returns result rows, values are formatted
+// in order of projections, NULL is formatted as "NULL".
+func (s *session) query(sql string) ([][]string, error) {
+	q, err := s.ps.Parse([]byte(sql))
+	if err != nil {
+		return nil, err
+	}
+	return s.run(q)
+}
+
+// run executes parsed query and returns result rows formatted as by query.
+func (s *session) run(q query.Querier) ([][]string, error) {
+	r, pr, err := s.es.Exec(q)
+	if err != nil {
+		return nil, err
+	} else if qu, ok := q.(*query.QueryUse); ok {
+		s.ps = s.ps.Use(qu.DB)
+	}
+
+	res := [][]string{}
+	if r == nil {
+		return res, nil
+	}
+
+	for row, ok := r.Pop(); ok; row, ok = r.Pop() {
+		r.Continue(true)
+		rec := make([]string, 0, len(pr.Iterator()))
+		for _, p := range pr.Iterator() {
+			if v := row[p.Alias]; v == nil {
+				rec = append(rec, "NULL")
+			} else {
+				rec = append(rec, fmt.Sprint(v.Value()))
+			}
+		}
+		res = append(res, rec)
+	}
+	return res, nil
+}
+
+func (s *session) exec(sql string) [][]string {
+	res, err := s.query(sql)
+	require.NoError(s.t, err, sql)
+	return res
+}
+
+// amounts creates table t with rows (1, 1), (2, 2), (3, 3), (4, 3), (5, 5).
+func (s *session) amounts() {
+	s.exec("CREATE TABLE t (id UInt32, amount Int32) ENGINE = InnoDB PRIMARY KEY (id) pk")
+	s.exec("INSERT INTO t (id, amount) VALUES (1, 1), (2, 2), (3, 3), (4, 3), (5, 5)")
+}
+
+func TestGroupByExpression(t *testing.T) {
+	s := newSession(t)
+	s.amounts()
+
+	require.ElementsMatch(t, [][]string{{"0", "1"}, {"1", "4"}},
+		s.exec("SELECT amount % 2 AS m, COUNT(id) AS c FROM t GROUP BY amount % 2"))
+	require.ElementsMatch(t, [][]string{{"1"}, {"1"}, {"2"}, {"1"}},
+		s.exec("SELECT COUNT(id) AS c FROM t GROUP BY amount"))
+	require.Equal(t, [][]string{{"1"}, {"2"}, {"3"}, {"5"}},
+		s.exec("SELECT amount FROM t GROUP BY amount ORDER BY amount"))
+
+	_, err := s.query("SELECT COUNT(id) AS c FROM t GROUP BY COUNT(id)")
+	require.Error(t, err)
+}
+
+func TestConditionalExpressions(t *testing.T) {
+	s := newSession(t)
+	s.exec("CREATE TABLE t (id UInt32, amount Int32, status VARCHAR(8)) ENGINE = InnoDB PRIMARY KEY (id) pk")
+	s.exec(`INSERT INTO t (id, amount, status) VALUES (1, 1, "ok"), (2, 2, "bad"), (3, 3, "ok")`)
+
+	require.Equal(t, [][]string{
+		{"1", "small", "1", "20"},
+		{"2", "big", "NULL", "10"},
+		{"3", "big", "1", "20"},
+	}, s.exec(`SELECT id, CASE WHEN amount > 1 THEN "big" ELSE "small" END AS s,
+		CASE status WHEN "ok" THEN 1 END AS k, IF(amount = 2, 10, 20) AS f FROM t`))
+
+	require.Equal(t, [][]string{
+		{"1", "1", "2", "1", "2.5"},
+		{"2", "NULL", "2", "2", "2.5"},
+		{"3", "3", "3", "2", "3"},
+	}, s.exec(`SELECT COALESCE(NULL, amount) AS c, NULLIF(amount, 2) AS n,
+		GREATEST(amount, 2) AS g, LEAST(amount, 2) AS l, GREATEST(amount, 2.5) AS gf FROM t`))
+
+	require.Equal(t, [][]string{{"2"}}, s.exec(`SELECT SUM(CASE WHEN status = "ok" THEN 1 ELSE 0 END) AS n FROM t`))
+}
+
+func TestExplain(t *testing.T) {
+	s := newSession(t)
+	s.amounts()
+
+	sql := "SELECT amount, COUNT(id) AS c FROM t USE_INDEX pk WHERE_INDEX (id >= 2) WHERE amount < 5 GROUP BY amount HAVING COUNT(id) > 1"
+	require.Equal(t, [][]string{
+		{"1", "NULL", "SELECT", "amount, COUNT(id)"},
+		{"2", "1", "GROUP", "by amount, having COUNT(id) > 1"},
+		{"3", "2", "FILTER", "amount < 5"},
+		{"4", "3", "INDEX RANGE SCAN", "table t using index pk, start id >= 2"},
+	}, s.exec("EXPLAIN "+sql))
+
+	// rows returned by each operator, time is reported but varies
+	analyzed := s.exec("EXPLAIN ANALYZE " + sql)
+	rows := []string{}
+	for _, rec := range analyzed {
+		require.Len(t, rec, 6)
+		require.NotEqual(t, "NULL", rec[5])
+		rows = append(rows, rec[4])
+	}
+	require.Equal(t, []string{"1", "1", "3", "4"}, rows)
+
+	require.Equal(t, [][]string{
+		{"1", "NULL", "UPDATE", "table t, set amount = 10"},
+		{"2", "1", "FULL SCAN", "table t, filter amount = 3"},
+	}, s.exec("EXPLAIN UPDATE t SET amount = 10 WHERE amount = 3"))
+	require.Equal(t, [][]string{{"3"}, {"3"}}, s.exec("SELECT amount FROM t WHERE amount = 3"))
+
+	// EXPLAIN ANALYZE executes query
+	analyzed = s.exec("EXPLAIN ANALYZE DELETE FROM t WHERE amount > 2")
+	require.Equal(t, "3", analyzed[0][4])
+	require.Equal(t, [][]string{{"1"}, {"2"}}, s.exec("SELECT id FROM t"))
+
+	s.exec("CREATE TABLE mt (k UInt32, v Int32) ENGINE = MergeTree PRIMARY KEY (k) pk")
+	s.exec("INSERT INTO mt (k, v) VALUES (1, 1)")
+	s.exec("INSERT INTO mt (k, v) VALUES (2, 1)")
+	require.Equal(t, [][]string{
+		{"1", "NULL", "SELECT", "k"},
+		{"2", "1", "INDEX SCAN", "table mt using index pk, parts 3"},
+	}, s.exec("EXPLAIN SELECT k FROM mt"))
+}
+
+// scan starts query and reads its first row, so table scan is in progress
+// till rest of rows are read by returned function.
+func (s *session) scan(sql string) (rest func() int) {
+	q, err := s.ps.Parse([]byte(sql))
+	require.NoError(s.t, err)
+	r, _, err := s.es.Exec(q)
+	require.NoError(s.t, err)
+
+	_, ok := r.Pop()
+	require.True(s.t, ok)
+	return func() int {
+		n := 1
+		for r.Continue(true); ; r.Continue(true) {
+			if _, ok := r.Pop(); !ok {
+				return n
+			}
+			n++
+		}
+	}
+}
+
+func TestDropWhileScanning(t *testing.T) {
+	s := newSession(t)
+	s.amounts()
+	tbl := s.es.es.Table("d", "t")
+
+	for _, sql := range []string{"TRUNCATE TABLE t", "DROP TABLE t"} {
+		rest := s.scan("SELECT id FROM t")
+		done := make(chan error, 1)
+		go func() {
+			_, err := s.query(sql)
+			done <- err
+		}()
+
+		select {
+			case err := <-done:
+				t.Fatalf("%s didn't wait for scan: %v", sql, err)
+			case <-time.After(100 * time.Millisecond):
+		}
+		require.Equal(t, 5, rest(), sql)
+		require.NoError(t, <-done, sql)
+
+		if sql == "TRUNCATE TABLE t" {
+			require.Empty(t, s.exec("SELECT id FROM t"))
+			s.exec("INSERT INTO t (id, amount) VALUES (1, 1), (2, 2), (3, 3), (4, 3), (5, 5)")
+		}
+	}
+
+	_, err := s.query("SELECT id FROM t")
+	require.Error(t, err)
+
+	// table was dropped, operations on it fail
+	_, err = tbl.FullScanByIndex("pk", false)
+	require.ErrorIs(t, err, table.ErrClosed)
+	_, err = tbl.ScanByIndex("pk", nil, nil)
+	require.ErrorIs(t, err, table.ErrClosed)
+	_, err = tbl.Delete(nil)
+	require.ErrorIs(t, err, table.ErrClosed)
+	_, eg := tbl.Update(nil, func(row types.DataRow) (types.DataRow, error) { return row, nil })
+	require.ErrorIs(t, eg.Wait(), table.ErrClosed)
+}
+
+func TestAlterDefaultsAndCasts(t *testing.T) {
+	s := newSession(t)
+	s.exec("CREATE TABLE t (id UInt32, name Nullable(VARCHAR(16))) ENGINE = InnoDB PRIMARY KEY (id) pk")
+	s.exec(`INSERT INTO t (id, name) VALUES (1, "a"), (2, "12")`)
+
+	// rows keep default of version, at which column was added
+	s.exec("ALTER TABLE t ADD COLUMN flag Int32 DEFAULT 7")
+	s.exec("ALTER TABLE t MODIFY COLUMN flag Int32 DEFAULT 9")
+	s.exec("INSERT INTO t (id, name) VALUES (3, NULL)")
+	require.Equal(t, [][]string{{"1", "7"}, {"2", "7"}, {"3", "9"}}, s.exec("SELECT id, flag FROM t"))
+
+	_, err := s.query("ALTER TABLE t MODIFY COLUMN name Nullable(Int32)")
+	require.ErrorContains(t, err, "value 'a' of column 'name' can't be casted to new type")
+	_, err = s.query("ALTER TABLE t MODIFY COLUMN name VARCHAR(16)")
+	require.ErrorContains(t, err, "column 'name' has NULL values")
+	require.Equal(t, [][]string{{"1", "a"}, {"2", "12"}, {"3", "NULL"}}, s.exec("SELECT id, name FROM t"))
+
+	s.exec(`UPDATE t SET name = "3" WHERE id = 1`)
+	s.exec("ALTER TABLE t MODIFY COLUMN name Nullable(Int32)")
+	require.Equal(t, [][]string{{"1", "3"}, {"2", "12"}, {"3", "NULL"}}, s.exec("SELECT id, name FROM t"))
+}
+
+func TestRenameTable(t *testing.T) {
+	s := newSession(t)
+	s.amounts()
+
+	// table isn't found by old name while it's closed for rename
+	rest := s.scan("SELECT id FROM t")
+	done := make(chan error, 1)
+	go func() {
+		_, err := s.query("RENAME TABLE t TO u")
+		done <- err
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+	_, err := s.query("SELECT id FROM t")
+	require.ErrorContains(t, err, "not found")
+	require.Equal(t, 5, rest())
+	require.NoError(t, <-done)
+	require.Len(t, s.exec("SELECT id FROM u"), 5)
+
+	s.exec("CREATE TABLE v (id UInt32) ENGINE = InnoDB PRIMARY KEY (id) pk")
+	_, err = s.query("RENAME TABLE u TO v")
+	require.ErrorContains(t, err, "already exists")
+
+	// table is opened back by old name, if its directory can't be moved
+	require.NoError(t, os.WriteFile(filepath.Join(s.es.es.DatabasePath("d"), "w"), nil, 0644))
+	_, err = s.query("RENAME TABLE u TO w")
+	require.ErrorContains(t, err, "failed to move table directory")
+	require.Len(t, s.exec("SELECT id FROM u"), 5)
+}
+
+func TestInsertConflicts(t *testing.T) {
+	s := newSession(t)
+	s.amounts()
+
+	// primary keys of inserted rows are returned with count of skipped rows
+	require.Equal(t, [][]string{{"6", "NULL"}, {"NULL", "2"}},
+		s.exec("INSERT IGNORE INTO t (id, amount) VALUES (1, 10), (6, 6), (2, 20)"))
+	require.Equal(t, [][]string{{"7", "NULL"}, {"NULL", "0"}},
+		s.exec("INSERT IGNORE INTO t (id, amount) VALUES (7, 7) RETURNING id"))
+	require.Equal(t, [][]string{{"NULL", "1"}}, s.exec("INSERT IGNORE INTO t (id, amount) VALUES (1, 10)"))
+
+	require.Equal(t, [][]string{{"1", "10"}, {"8", "8"}},
+		s.exec("REPLACE INTO t (id, amount) VALUES (1, 10), (8, 8) RETURNING id, amount"))
+
+	require.Equal(t, [][]string{{"2", "3"}, {"9", "9"}},
+		s.exec("INSERT INTO t (id, amount) VALUES (2, 100), (9, 9) ON DUPLICATE KEY UPDATE amount = amount + 1 RETURNING id, amount"))
+	s.exec("INSERT INTO t (id, amount) VALUES (2, 100) ON DUPLICATE KEY UPDATE amount = amount + 1")
+
+	require.Equal(t, [][]string{
+		{"1", "10"}, {"2", "4"}, {"3", "3"}, {"4", "3"}, {"5", "5"}, {"6", "6"}, {"7", "7"}, {"8", "8"}, {"9", "9"},
+	}, s.exec("SELECT id, amount FROM t"))
+}
+
+func TestConcurrentUpsert(t *testing.T) {
+	s := newSession(t)
+	s.amounts()
+
+	// every increment is applied, conflicting row isn't inserted twice,
+	// inserts run in parallel even on single cpu
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+	const workers, rows = 8, 100
+	sql := "INSERT INTO t (id, amount) VALUES (10, 1)" + strings.Repeat(", (10, 1)", rows-1) +
+		" ON DUPLICATE KEY UPDATE amount = amount + 1"
+	errs := make(chan error, workers)
+	for range workers {
+		q, err := s.ps.Parse([]byte(sql))
+		require.NoError(t, err)
+		go func() {
+			_, _, err := s.es.Exec(q)
+			errs <- err
+		}()
+	}
+	for range workers {
+		require.NoError(t, <-errs)
+	}
+	require.Equal(t, [][]string{{"10", fmt.Sprint(workers * rows)}}, s.exec("SELECT id, amount FROM t WHERE id >= 10"))
+}
+
+func TestReturning(t *testing.T) {
+	s := newSession(t)
+	s.amounts()
+
+	// expressions are evaluated on updated row, alias doesn't overwrite column
+	require.Equal(t, [][]string{{"20", "2"}},
+		s.exec("UPDATE t SET amount = amount * 2 WHERE id = 1 RETURNING amount * 10 AS amount, amount AS orig"))
+	require.Equal(t, [][]string{{"6", "7"}},
+		s.exec("INSERT INTO t (id, amount) VALUES (6, 6) RETURNING id AS amount, amount + 1 AS id"))
+	require.ElementsMatch(t, [][]string{{"3", "3"}, {"3", "4"}},
+		s.exec("DELETE FROM t WHERE amount = 3 RETURNING amount AS id, id AS amount"))
+	require.Equal(t, [][]string{{"5"}}, s.exec("DELETE FROM t WHERE id = 5"))
+
+	require.Equal(t, [][]string{{"1", "2"}, {"2", "2"}, {"6", "6"}}, s.exec("SELECT id, amount FROM t"))
+}
+
+func TestShowCreateTableRoundTrip(t *testing.T) {
+	s := newSession(t)
+
+	for _, sql := range []string{
+		"CREATE TABLE t (id UInt32 AUTO INCREMENT, `order` VARCHAR(16) DEFAULT \"x\", amount Nullable(Float64), n Int32 DEFAULT 3) ENGINE = InnoDB PRIMARY KEY (id) pk, INDEX (`order`, n) kn UNIQUE, INDEX (amount) a",
+		"CREATE TABLE t (k UInt32, v Int64) ENGINE = MergeTree PRIMARY KEY (k) pk",
+		"CREATE TABLE t (k UInt32, s AggregateFunction(SUM, Float64), m AggregateFunction(MAX, Int32)) ENGINE = AggregatingMergeTree PRIMARY KEY (k) pk",
+	} {
+		s.exec(sql)
+
+		// table created by shown query is shown by the same query
+		created := s.exec("SHOW CREATE TABLE t")[0][1]
+		s.exec(strings.Replace(created, "CREATE TABLE t ", "CREATE TABLE u ", 1))
+		require.Equal(t, created, strings.Replace(s.exec("SHOW CREATE TABLE u")[0][1], "CREATE TABLE u ", "CREATE TABLE t ", 1), sql)
+		require.Equal(t, s.exec("DESCRIBE t"), s.exec("DESCRIBE u"), sql)
+		require.Equal(t, s.exec("SHOW INDEXES FROM t"), s.exec("SHOW INDEXES FROM u"), sql)
+
+		s.exec("DROP TABLE t")
+		s.exec("DROP TABLE u")
+	}
+}
+
+func TestSystemTables(t *testing.T) {
+	s := newSession(t)
+	s.amounts()
+	s.exec("CREATE TABLE mt (k UInt32, v Nullable(Int32) DEFAULT 1) ENGINE = MergeTree PRIMARY KEY (k) pk, INDEX (v) vi")
+	s.exec("INSERT INTO mt (k) VALUES (1), (2)")
+	s.exec("INSERT INTO mt (k) VALUES (3)")
+
+	require.Equal(t, [][]string{{"mt", "MergeTree", "3"}, {"t", "InnoDB", "5"}},
+		s.exec(`SELECT name, engine, total_rows FROM system.tables WHERE database = "d"`))
+	require.Equal(t, [][]string{{"NULL", "NULL"}},
+		s.exec(`SELECT total_rows, total_bytes FROM system.tables WHERE name = "tables"`))
+	require.Equal(t, [][]string{{"k", "1", "UInt32", "0", "NULL"}, {"v", "2", "Int32", "1", "1"}},
+		s.exec(`SELECT name, position, type, nullable, default FROM system.columns WHERE table_name = "mt"`))
+	require.Equal(t, [][]string{{"pk", "k", "1", "0"}, {"vi", "v", "0", "0"}},
+		s.exec(`SELECT name, columns, is_primary, uniq FROM system.indexes WHERE table_name = "mt"`))
+	require.ElementsMatch(t, [][]string{{"1", "0"}, {"0", "2"}, {"0", "1"}},
+		s.exec(`SELECT master, total_rows FROM system.parts WHERE table_name = "mt"`))
+	require.Empty(t, s.exec("SELECT id FROM system.connections"))
+
+	// system database is read-only
+	for _, sql := range []string{
+		`INSERT INTO system.tables (name) VALUES ("x")`,
+		`DELETE FROM system.tables WHERE name = "t"`,
+		`UPDATE system.tables SET name = "x"`,
+		"DROP TABLE system.tables",
+		"CREATE TABLE system.x (id UInt32) ENGINE = InnoDB PRIMARY KEY (id) pk",
+	} {
+		_, err := s.query(sql)
+		require.Error(t, err, sql)
+	}
+}
+
+func TestViews(t *testing.T) {
+	s := newSession(t)
+	s.amounts()
+	s.exec("CREATE VIEW big AS SELECT id, amount FROM t WHERE amount > 2")
+	s.exec("CREATE VIEW bigger AS SELECT id FROM big WHERE amount > 3")
+
+	require.Equal(t, [][]string{{"3"}, {"4"}, {"5"}}, s.exec("SELECT id FROM big"))
+	require.Equal(t, [][]string{{"5"}}, s.exec("SELECT id FROM bigger"))
+	require.Equal(t, [][]string{{"4", "3"}, {"5", "5"}}, s.exec("SELECT id, amount FROM t WHERE id IN (SELECT id FROM big) AND id > 3"))
+
+	// prepared query is expanded on each execution, view isn't cached in it
+	p, err := s.ps.Prepare([]byte("SELECT id FROM big WHERE id > ?"))
+	require.NoError(t, err)
+	exec := func(id int) ([][]string, error) {
+		require.NoError(t, p.Bind([]types.DataType{types.Type(types.Meta(types.TYPE_INTEGER, true, 4, false)).Set(int32(id))}))
+		return s.run(p.Querier)
+	}
+	rows, err := exec(3)
+	require.NoError(t, err)
+	require.Equal(t, [][]string{{"4"}, {"5"}}, rows)
+
+	s.exec("DROP VIEW bigger")
+	s.exec("DROP VIEW big")
+	_, err = exec(3)
+	require.Error(t, err)
+
+	s.exec("CREATE VIEW big AS SELECT id, amount FROM t WHERE amount > 4")
+	rows, err = exec(0)
+	require.NoError(t, err)
+	require.Equal(t, [][]string{{"5"}}, rows)
+
+	// view is validated on each execution, so dropped column is reported
+	s.exec("ALTER TABLE t DROP COLUMN amount")
+	_, err = exec(0)
+	require.ErrorContains(t, err, "invalid view: 'big'")
+}
@@ -0,0 +1,311 @@
+package parent
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+
+	"go-dbms/pkg/engine/aggregatingmergetree"
+	"go-dbms/pkg/engine/mergetree"
+	"go-dbms/pkg/table"
+	"go-dbms/util/helpers"
+
+	"github.com/pkg/errors"
+)
+
+var ErrDatabaseDropped = errors.New("database is dropped")
+
+// errNotTable is error of directory, which has no valid metadata of table
+var errNotTable = errors.New("not a table")
+
+// viewsFileName is name of file of database directory, which keeps its views.
+const viewsFileName = "views.json"
+
+// View is query stored in catalog of database, it's parsed
+// and expanded each time view is referenced.
+type View struct {
+	Query    string `json:"query"`
+	Database string `json:"database"` // database of unqualified table names of query
+}
+
+type tableMetaEngine struct {
+	Engine table.Engine `json:"engine"`
+}
+
+// Database holds tables stored in its directory. Map of tables is replaced
+// instead of being modified, so queries can read it without locking.
+type Database struct {
+	Name     string
+	path     string
+	tablesMu *sync.Mutex
+	dropped  bool
+	tables   atomic.Pointer[map[string]table.ITable]
+	Views    map[string]*View
+
+	// read-only database has no directory, its tables and schema can't be changed
+	ReadOnly bool
+}
+
+func newDatabase(name, path string) *Database {
+	db := &Database{
+		Name:     name,
+		path:     path,
+		tablesMu: &sync.Mutex{},
+		Views:    map[string]*View{},
+	}
+	db.setTables(map[string]table.ITable{})
+	return db
+}
+
+// NewReadOnlyDatabase returns database of given tables, which
+// aren't stored on disk, e.g. tables of system database.
+func NewReadOnlyDatabase(name string, tables map[string]table.ITable) *Database {
+	db := &Database{
+		Name:     name,
+		tablesMu: &sync.Mutex{},
+		Views:    map[string]*View{},
+		ReadOnly: true,
+	}
+	db.setTables(tables)
+	return db
+}
+
+func openDatabase(name, path string) (*Database, error) {
+	dirEntries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read tables directory")
+	}
+
+	db := newDatabase(name, path)
+	tables := map[string]table.ITable{}
+	for _, de := range dirEntries {
+		if !de.IsDir() {
+			continue
+		}
+
+		tableName := de.Name()
+		t, err := openTable(db.TablePath(tableName))
+		if errors.Is(err, errNotTable) {
+			fmt.Printf("[error] => %v\n", err)
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to open table: '%s'", tableName)
+		}
+		tables[tableName] = t
+	}
+	db.setTables(tables)
+
+	if db.Views, err = readViews(db.viewsPath()); err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+// readViews reads catalog of views, database without views has no file.
+func readViews(path string) (map[string]*View, error) {
+	views := map[string]*View{}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return views, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "failed to read views")
+	}
+	return views, errors.Wrap(json.Unmarshal(data, &views), "failed to decode views")
+}
+
+// openTable opens table stored in dataPath by engine of its metadata.
+func openTable(dataPath string) (t table.ITable, err error) {
+	metaFilePath := filepath.Join(dataPath, table.MetadataFileName)
+
+	mf, err := os.Open(metaFilePath)
+	if err != nil {
+		return nil, errors.Wrap(errNotTable, err.Error())
+	}
+
+	engineMeta := &tableMetaEngine{}
+	err = json.NewDecoder(mf).Decode(engineMeta)
+	mf.Close()
+	if err != nil {
+		return nil, errors.Wrap(errNotTable, err.Error())
+	}
+
+	opts := &table.Options{
+		Engine:       engineMeta.Engine,
+		DataPath:     dataPath,
+		MetaFilePath: metaFilePath,
+	}
+
+	switch engineMeta.Engine {
+		case table.InnoDB:               return table.Open(opts)
+		case table.MergeTree:            return mergetree.Open(opts)
+		case table.AggregatingMergeTree: return aggregatingmergetree.Open(&aggregatingmergetree.Options{
+			Options: opts,
+		})
+		default: panic(ErrInvalidEngine)
+	}
+}
+
+func (db *Database) Close() {
+	for _, t := range db.Tables() {
+		t.Close()
+	}
+}
+
+// Writable returns error if database is read-only.
+func (db *Database) Writable() error {
+	if db.ReadOnly {
+		return fmt.Errorf("database is read-only: '%s'", db.Name)
+	}
+	return nil
+}
+
+// AddTable adds table to database, fails if database is dropped meanwhile.
+func (db *Database) AddTable(name string, t table.ITable) error {
+	db.tablesMu.Lock()
+	defer db.tablesMu.Unlock()
+
+	if db.dropped {
+		return ErrDatabaseDropped
+	}
+
+	db.setTables(withTable(db.Tables(), name, t))
+	return nil
+}
+
+// AddView adds view to catalog of database and writes catalog to disk.
+func (db *Database) AddView(name string, v *View) error {
+	db.tablesMu.Lock()
+	defer db.tablesMu.Unlock()
+
+	if db.dropped {
+		return ErrDatabaseDropped
+	}
+
+	views := maps.Clone(db.Views)
+	views[name] = v
+	if err := db.writeViews(views); err != nil {
+		return err
+	}
+	db.Views = views
+	return nil
+}
+
+// RemoveView removes view from catalog of database and writes catalog to disk.
+func (db *Database) RemoveView(name string) error {
+	db.tablesMu.Lock()
+	defer db.tablesMu.Unlock()
+
+	if _, ok := db.Views[name]; !ok || db.dropped {
+		return nil
+	}
+
+	views := maps.Clone(db.Views)
+	delete(views, name)
+	if err := db.writeViews(views); err != nil {
+		return err
+	}
+	db.Views = views
+	return nil
+}
+
+func (db *Database) writeViews(views map[string]*View) error {
+	err := os.WriteFile(db.viewsPath(), helpers.MarshalJSON(views), 0644)
+	return errors.Wrap(err, "failed to write views")
+}
+
+func (db *Database) viewsPath() string {
+	return filepath.Join(db.path, viewsFileName)
+}
+
+// RemoveTable removes table from map of tables and returns it.
+func (db *Database) RemoveTable(name string) (table.ITable, bool) {
+	db.tablesMu.Lock()
+	defer db.tablesMu.Unlock()
+
+	t, ok := db.Tables()[name]
+	if ok {
+		tables := maps.Clone(db.Tables())
+		delete(tables, name)
+		db.setTables(tables)
+	}
+	return t, ok
+}
+
+// Drop drops all tables of database and removes its directory.
+// Database must be removed from executor before.
+func (db *Database) Drop() error {
+	db.tablesMu.Lock()
+	db.dropped = true
+	tables := db.Tables()
+	db.setTables(map[string]table.ITable{})
+	db.tablesMu.Unlock()
+
+	for _, t := range tables {
+		t.Drop()
+	}
+	return errors.Wrap(os.RemoveAll(db.path), "failed to remove database directory")
+}
+
+// RenameTable removes table from tables of database, so queries don't find it
+// closed by old name, moves its directory and opens it again by new name.
+// Table is opened back by old name, if move fails, and error is returned.
+func (db *Database) RenameTable(name, newName string) error {
+	db.tablesMu.Lock()
+	defer db.tablesMu.Unlock()
+
+	if db.dropped {
+		return ErrDatabaseDropped
+	}
+
+	t, ok := db.Tables()[name]
+	if !ok {
+		return fmt.Errorf("table not found: '%s'", name)
+	} else if _, ok := db.Tables()[newName]; ok {
+		return fmt.Errorf("table already exists: '%s'", newName)
+	}
+
+	tables := maps.Clone(db.Tables())
+	delete(tables, name)
+	db.setTables(tables)
+
+	t.Close()
+	if err := os.Rename(db.TablePath(name), db.TablePath(newName)); err != nil {
+		if t, openErr := openTable(db.TablePath(name)); openErr == nil {
+			db.setTables(withTable(db.Tables(), name, t))
+		}
+		return errors.Wrap(err, "failed to move table directory")
+	}
+
+	t, err := openTable(db.TablePath(newName))
+	if err != nil {
+		return errors.Wrapf(err, "failed to open table: '%s'", newName)
+	}
+
+	db.setTables(withTable(db.Tables(), newName, t))
+	return nil
+}
+
+// Tables returns tables of database, returned map must not be modified.
+func (db *Database) Tables() map[string]table.ITable {
+	return *db.tables.Load()
+}
+
+func (db *Database) setTables(tables map[string]table.ITable) {
+	db.tables.Store(&tables)
+}
+
+// withTable returns copy of tables with t added.
+func withTable(tables map[string]table.ITable, name string, t table.ITable) map[string]table.ITable {
+	tables = maps.Clone(tables)
+	tables[name] = t
+	return tables
+}
+
+func (db *Database) TablePath(tableName string) string {
+	return filepath.Join(db.path, tableName)
+}
@@ -1,13 +1,13 @@
 package parent
 
 import (
-	"encoding/json"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"sync"
 	"time"
 
-	"go-dbms/pkg/engine/aggregatingmergetree"
 	"go-dbms/pkg/engine/mergetree"
 	"go-dbms/pkg/table"
 	"go-dbms/pkg/types"
@@ -19,30 +19,42 @@ import (
 	"github.com/pkg/errors"
 )
 
-var ErrInvalidEngine = errors.New("invalid engine")
+// DefaultDatabase is database of queries with unqualified table
+// names, when connection hasn't selected another database.
+const DefaultDatabase = "default"
 
-type tableMetaEngine struct {
-	Engine table.Engine `json:"engine"`
-}
+var ErrInvalidEngine = errors.New("invalid engine")
 
+// ExecutorService holds databases, each database is directory of tables
+// in data directory. Map of databases is replaced instead of being modified,
+// so queries can read it without locking.
 type ExecutorService struct {
-	dataPath string
-	Tables   map[string]table.ITable
+	dataPath  string
+	dbMu      *sync.Mutex
+	Databases map[string]*Database
 }
 
 type Executor interface {
 	Exec(q query.Querier) (stream.ReaderContinue[types.DataRow], *projection.Projections, error)
+
+	// Validate checks query without executing it, e.g. query of view.
+	Validate(q query.Querier) error
 }
 
 func New(dataPath string) (*ExecutorService, error) {
+	if err := migrateTables(dataPath); err != nil {
+		return nil, err
+	}
+
 	dirEntries, err := os.ReadDir(dataPath)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to read tables directory")
+		return nil, errors.Wrap(err, "failed to read databases directory")
 	}
 
 	es := &ExecutorService{
-		dataPath: dataPath,
-		Tables:   make(map[string]table.ITable, len(dirEntries)),
+		dataPath:  dataPath,
+		dbMu:      &sync.Mutex{},
+		Databases: make(map[string]*Database, len(dirEntries)),
 	}
 
 	for _, de := range dirEntries {
@@ -50,62 +62,167 @@ func New(dataPath string) (*ExecutorService, error) {
 			continue
 		}
 
-		tableName := de.Name()
-		dataPath := es.TablePath(tableName)
-		metaFilePath := filepath.Join(dataPath, table.MetadataFileName)
-
-		mf, err := os.Open(metaFilePath)
+		name := de.Name()
+		es.Databases[name], err = openDatabase(name, es.DatabasePath(name))
 		if err != nil {
-			fmt.Printf("[error] => %v\n", err)
-			continue
+			return nil, errors.Wrapf(err, "failed to open database: '%s'", name)
 		}
+	}
 
-		engineMeta := &tableMetaEngine{}
-		err = json.NewDecoder(mf).Decode(engineMeta)
-		if err != nil {
-			fmt.Printf("[error] => %v\n", err)
+	if _, ok := es.Databases[DefaultDatabase]; !ok {
+		if err := es.CreateDatabase(DefaultDatabase); err != nil {
+			return nil, err
+		}
+	}
+
+	es.StartMerger()
+	return es, nil
+}
+
+// migrateTables moves tables stored right in data directory, as they were
+// before databases were added, to directory of default database.
+func migrateTables(dataPath string) error {
+	dirEntries, err := os.ReadDir(dataPath)
+	if err != nil {
+		return errors.Wrap(err, "failed to read databases directory")
+	}
+
+	for _, de := range dirEntries {
+		if !de.IsDir() {
 			continue
 		}
 
-		opts := &table.Options{
-			Engine:       engineMeta.Engine,
-			DataPath:     dataPath,
-			MetaFilePath: metaFilePath,
+		oldPath := filepath.Join(dataPath, de.Name())
+		if _, err := os.Stat(filepath.Join(oldPath, table.MetadataFileName)); err != nil {
+			continue
 		}
 
-		switch engineMeta.Engine {
-			case table.InnoDB:               es.Tables[tableName], err = table.Open(opts)
-			case table.MergeTree:            es.Tables[tableName], err = mergetree.Open(opts)
-			case table.AggregatingMergeTree: es.Tables[tableName], err = aggregatingmergetree.Open(&aggregatingmergetree.Options{
-				Options: opts,
-			})
-			default: panic(ErrInvalidEngine)
+		dbPath := filepath.Join(dataPath, DefaultDatabase)
+		if err := os.MkdirAll(dbPath, 0755); err != nil {
+			return errors.Wrap(err, "failed to create default database")
 		}
-		if err != nil {
-			return nil, errors.Wrapf(err, "failed to open table: '%s'", tableName)
+		if err := os.Rename(oldPath, filepath.Join(dbPath, de.Name())); err != nil {
+			return errors.Wrapf(err, "failed to move table '%s' to default database", de.Name())
 		}
+		fmt.Printf("table '%s' moved to database '%s'\n", de.Name(), DefaultDatabase)
 	}
-
-	es.StartMerger()
-	return es, nil
+	return nil
 }
 
 func (es *ExecutorService) StartMerger() {
 	timer.SetInterval(time.Minute, func() {
-		for _, t := range es.Tables {
-			if t, ok := t.(mergetree.IMergeTree); ok {
-				t.Merge()
+		for _, db := range es.Databases {
+			for _, t := range db.Tables() {
+				if t, ok := t.(mergetree.IMergeTree); ok {
+					t.Merge()
+				}
 			}
 		}
 	})
 }
 
 func (es *ExecutorService) Close() {
-	for _, table := range es.Tables {
-		table.Close()
+	for _, db := range es.Databases {
+		db.Close()
+	}
+}
+
+// DB returns database by name, empty name is default database.
+func (es *ExecutorService) DB(name string) (*Database, bool) {
+	if name == "" {
+		name = DefaultDatabase
+	}
+	db, ok := es.Databases[name]
+	return db, ok
+}
+
+// Table returns table of database, nil if database or table doesn't exist.
+func (es *ExecutorService) Table(dbName, name string) table.ITable {
+	if db, ok := es.DB(dbName); ok {
+		return db.Tables()[name]
+	}
+	return nil
+}
+
+// LookupTable is Table, which returns error for
+// not existing database or table.
+func (es *ExecutorService) LookupTable(dbName, name string) (table.ITable, error) {
+	db, ok := es.DB(dbName)
+	if !ok {
+		return nil, fmt.Errorf("database not found: '%s'", dbName)
+	}
+
+	t, ok := db.Tables()[name]
+	if !ok {
+		return nil, fmt.Errorf("table not found: '%s'", name)
+	}
+	return t, nil
+}
+
+// LookupWritableTable is LookupTable for queries, which
+// change rows or schema of table, it fails for read-only database.
+func (es *ExecutorService) LookupWritableTable(dbName, name string) (table.ITable, error) {
+	t, err := es.LookupTable(dbName, name)
+	if err != nil {
+		return nil, err
+	}
+
+	db, _ := es.DB(dbName)
+	if err := db.Writable(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// AddDatabase adds database, which isn't stored in data directory.
+func (es *ExecutorService) AddDatabase(db *Database) error {
+	es.dbMu.Lock()
+	defer es.dbMu.Unlock()
+
+	if _, ok := es.Databases[db.Name]; ok {
+		return fmt.Errorf("database already exists: '%s'", db.Name)
+	}
+
+	dbs := maps.Clone(es.Databases)
+	dbs[db.Name] = db
+	es.Databases = dbs
+	return nil
+}
+
+// CreateDatabase creates directory of database and adds empty database.
+func (es *ExecutorService) CreateDatabase(name string) error {
+	es.dbMu.Lock()
+	defer es.dbMu.Unlock()
+
+	if _, ok := es.Databases[name]; ok {
+		return fmt.Errorf("database already exists: '%s'", name)
+	}
+
+	dbPath := es.DatabasePath(name)
+	if err := os.MkdirAll(dbPath, 0755); err != nil {
+		return errors.Wrapf(err, "failed to create database: '%s'", name)
+	}
+
+	dbs := maps.Clone(es.Databases)
+	dbs[name] = newDatabase(name, dbPath)
+	es.Databases = dbs
+	return nil
+}
+
+// RemoveDatabase removes database from map of databases and returns it.
+func (es *ExecutorService) RemoveDatabase(name string) (*Database, bool) {
+	es.dbMu.Lock()
+	defer es.dbMu.Unlock()
+
+	db, ok := es.Databases[name]
+	if ok {
+		dbs := maps.Clone(es.Databases)
+		delete(dbs, name)
+		es.Databases = dbs
 	}
+	return db, ok
 }
 
-func (es *ExecutorService) TablePath(tableName string) string {
-	return filepath.Join(es.dataPath, tableName)
+func (es *ExecutorService) DatabasePath(name string) string {
+	return filepath.Join(es.dataPath, name)
 }
@@ -0,0 +1,93 @@
+package show
+
+import (
+	"fmt"
+	"strings"
+
+	"go-dbms/pkg/index"
+	"go-dbms/pkg/table"
+	"go-dbms/pkg/types"
+	"go-dbms/services/parser/kwords"
+	"go-dbms/services/parser/query/dml/projection"
+	"go-dbms/services/parser/query/show"
+	"go-dbms/util/stream"
+
+	"github.com/pkg/errors"
+)
+
+// createTable returns query, which creates table with the same columns and indexes.
+func (sh *Show) createTable(q *show.QueryShowCreateTable) (
+	stream.ReaderContinue[types.DataRow],
+	*projection.Projections,
+	error,
+) {
+	t, err := sh.LookupTable(q.DB, q.Table)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "validation error")
+	}
+
+	return result([]types.DataRow{{
+		"table":        str(q.Table),
+		"create_table": str(createTableQuery(q.Table, t)),
+	}}, "table", "create_table")
+}
+
+// createTableQuery rebuilds CREATE TABLE query of table from its metadata.
+func createTableQuery(name string, t table.ITable) string {
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "CREATE TABLE %s (\n", ident(name))
+
+	aggrs := aggregations(t)
+	for i, col := range t.Columns() {
+		typ := types.Name(col.Meta)
+		if autoIncrement(col.Meta) {
+			typ += " AUTO INCREMENT"
+		}
+		if col.Nullable {
+			typ = "Nullable(" + typ + ")"
+		}
+		if aggr, ok := aggrs[col.Name]; ok {
+			typ = fmt.Sprintf("AggregateFunction(%s, %s)", aggr, typ)
+		}
+
+		fmt.Fprintf(b, "\t%s %s", ident(col.Name), typ)
+		if col.Default != nil {
+			fmt.Fprintf(b, " DEFAULT %s", literal(col.Default))
+		}
+		if i < len(t.Columns())-1 {
+			b.WriteString(",")
+		}
+		b.WriteString("\n")
+	}
+	fmt.Fprintf(b, ") ENGINE = %s\n", t.Engine())
+
+	pk := t.IndexMeta(t.PrimaryKey())
+	fmt.Fprintf(b, "PRIMARY KEY(%s) %s", indexColumns(pk), ident(pk.Name))
+	for _, meta := range t.IndexesMeta() {
+		if meta.Name == pk.Name {
+			continue
+		}
+
+		fmt.Fprintf(b, ",\nINDEX(%s) %s", indexColumns(meta), ident(meta.Name))
+		if meta.Uniq {
+			b.WriteString(" UNIQUE")
+		}
+	}
+	return b.String()
+}
+
+func indexColumns(meta *index.Meta) string {
+	columns := make([]string, len(meta.Columns))
+	for i, col := range meta.Columns {
+		columns[i] = ident(col)
+	}
+	return strings.Join(columns, ", ")
+}
+
+// ident returns name, which is quoted if it's reserved word.
+func ident(name string) string {
+	if kwords.IsReserved(strings.ToUpper(name)) {
+		return "`" + name + "`"
+	}
+	return name
+}
@@ -0,0 +1,55 @@
+package show
+
+import (
+	"fmt"
+	"slices"
+
+	"go-dbms/pkg/types"
+	"go-dbms/services/parser/query/dml/projection"
+	"go-dbms/services/parser/query/show"
+	"go-dbms/util/stream"
+)
+
+// databases returns names of databases in alphabetical order.
+func (sh *Show) databases() (
+	stream.ReaderContinue[types.DataRow],
+	*projection.Projections,
+	error,
+) {
+	rows := []types.DataRow{}
+	for _, name := range sortedKeys(sh.Databases) {
+		rows = append(rows, types.DataRow{"name": str(name)})
+	}
+	return result(rows, "name")
+}
+
+// tables returns names and engines of tables of database in alphabetical order.
+func (sh *Show) tables(q *show.QueryShowTables) (
+	stream.ReaderContinue[types.DataRow],
+	*projection.Projections,
+	error,
+) {
+	db, ok := sh.DB(q.DB)
+	if !ok {
+		return nil, nil, fmt.Errorf("database not found: '%s'", q.DB)
+	}
+
+	tables := db.Tables()
+	rows := []types.DataRow{}
+	for _, name := range sortedKeys(tables) {
+		rows = append(rows, types.DataRow{
+			"name":   str(name),
+			"engine": str(string(tables[name].Engine())),
+		})
+	}
+	return result(rows, "name", "engine")
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
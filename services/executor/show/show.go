@@ -0,0 +1,86 @@
+package show
+
+import (
+	"fmt"
+
+	"go-dbms/pkg/engine/aggregatingmergetree"
+	"go-dbms/pkg/table"
+	"go-dbms/pkg/types"
+	"go-dbms/services/executor/parent"
+	"go-dbms/services/parser/query/dml/aggregator"
+	"go-dbms/services/parser/query/dml/projection"
+	"go-dbms/services/parser/query/show"
+	"go-dbms/util/stream"
+)
+
+type Show struct {
+	*parent.ExecutorService
+}
+
+func New(es *parent.ExecutorService) *Show {
+	return &Show{ExecutorService: es}
+}
+
+func (sh *Show) Show(q show.Shower) (
+	stream.ReaderContinue[types.DataRow],
+	*projection.Projections,
+	error,
+) {
+	switch q.GetTarget() {
+		case show.DATABASES:    return sh.databases()
+		case show.TABLES:       return sh.tables(q.(*show.QueryShowTables))
+		case show.COLUMNS:      return sh.columns(q.(*show.QueryShowColumns))
+		case show.INDEXES:      return sh.indexes(q.(*show.QueryShowIndexes))
+		case show.CREATE_TABLE: return sh.createTable(q.(*show.QueryShowCreateTable))
+		default:                panic(fmt.Errorf("invalid show target: '%s'", q.GetTarget()))
+	}
+}
+
+// result returns rows with columns of given names in the same order.
+func result(rows []types.DataRow, columns ...string) (
+	stream.ReaderContinue[types.DataRow],
+	*projection.Projections,
+	error,
+) {
+	prs := projection.New()
+	for _, col := range columns {
+		prs.Add(&projection.Projection{Alias: col, Name: col, Type: projection.IDENTIFIER})
+	}
+
+	out := stream.New[types.DataRow](1)
+	go func() {
+		defer out.Close()
+		for _, row := range rows {
+			out.Push(row)
+			if !out.ShouldContinue() {
+				return
+			}
+		}
+	}()
+	return out, prs, nil
+}
+
+func str(v string) types.DataType {
+	return types.Type(types.Meta(types.TYPE_STRING)).Set(v)
+}
+
+func num(v int) types.DataType {
+	return types.Type(types.Meta(types.TYPE_INTEGER, false, 8, false)).Set(v)
+}
+
+// flag is boolean value as UInt8 0 or 1.
+func flag(v bool) types.DataType {
+	if v {
+		return types.Type(types.Meta(types.TYPE_INTEGER, false, 1, false)).Set(1)
+	}
+	return types.Type(types.Meta(types.TYPE_INTEGER, false, 1, false)).Set(0)
+}
+
+// aggregations returns aggregate functions of columns, which
+// only AggregatingMergeTree tables have.
+func aggregations(t table.ITable) map[string]aggregator.AggregatorType {
+	if at, ok := t.(*aggregatingmergetree.AggregatingMergeTree); ok {
+		return at.Meta.GetAggregations()
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package show
+
+import (
+	"encoding/json"
+	"strings"
+
+	"go-dbms/pkg/types"
+	"go-dbms/services/parser/query/dml/projection"
+	"go-dbms/services/parser/query/show"
+	"go-dbms/util/helpers"
+	"go-dbms/util/stream"
+
+	"github.com/pkg/errors"
+)
+
+// columns returns definitions of columns of table in their order.
+func (sh *Show) columns(q *show.QueryShowColumns) (
+	stream.ReaderContinue[types.DataRow],
+	*projection.Projections,
+	error,
+) {
+	t, err := sh.LookupTable(q.DB, q.Table)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "validation error")
+	}
+
+	aggrs := aggregations(t)
+	rows := []types.DataRow{}
+	for _, col := range t.Columns() {
+		row := types.DataRow{
+			"name":           str(col.Name),
+			"type":           str(types.Name(col.Meta)),
+			"nullable":       flag(col.Nullable),
+			"auto_increment": flag(autoIncrement(col.Meta)),
+			"default":        nil,
+			"aggregation":    nil,
+		}
+		if col.Default != nil {
+			row["default"] = str(literal(col.Default))
+		}
+		if aggr, ok := aggrs[col.Name]; ok {
+			row["aggregation"] = str(string(aggr))
+		}
+		rows = append(rows, row)
+	}
+	return result(rows, "name", "type", "nullable", "auto_increment", "default", "aggregation")
+}
+
+// indexes returns indexes of table with options of their B+ trees.
+func (sh *Show) indexes(q *show.QueryShowIndexes) (
+	stream.ReaderContinue[types.DataRow],
+	*projection.Projections,
+	error,
+) {
+	t, err := sh.LookupTable(q.DB, q.Table)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "validation error")
+	}
+
+	rows := []types.DataRow{}
+	for _, meta := range t.IndexesMeta() {
+		rows = append(rows, types.DataRow{
+			"name":      str(meta.Name),
+			"columns":   str(strings.Join(meta.Columns, ", ")),
+			"primary":   flag(meta.Name == t.PrimaryKey()),
+			"uniq":      flag(meta.Uniq),
+			"degree":    num(meta.Options.Degree),
+			"page_size": num(meta.Options.PageSize),
+		})
+	}
+	return result(rows, "name", "columns", "primary", "uniq", "degree", "page_size")
+}
+
+func autoIncrement(meta types.DataTypeMeta) bool {
+	m, ok := meta.(*types.DataTypeINTEGERMeta)
+	return ok && m.AI.Enabled
+}
+
+// literal returns value as it's written in query.
+func literal(v types.DataType) string {
+	return string(helpers.MustVal(json.Marshal(v)))
+}
@@ -0,0 +1,105 @@
+package system
+
+import (
+	"encoding/json"
+	"slices"
+	"time"
+
+	"go-dbms/pkg/column"
+	"go-dbms/pkg/table"
+	"go-dbms/pkg/types"
+	"go-dbms/services/executor/parent"
+	"go-dbms/util/helpers"
+)
+
+// Database is name of read-only database of system tables.
+const Database = "system"
+
+// Connection is state of client connection shown by system.connections.
+type Connection struct {
+	ID        uint64
+	Addr      string
+	Database  string
+	Connected time.Time
+	Queries   uint64
+}
+
+// System builds rows of system tables from state of executor and server.
+type System struct {
+	*parent.ExecutorService
+
+	// Connections returns connections of server, nil till server is started
+	Connections func() []Connection
+}
+
+func New(es *parent.ExecutorService) *System {
+	return &System{ExecutorService: es}
+}
+
+// Database returns system database with its tables.
+func (sys *System) Database() *parent.Database {
+	return parent.NewReadOnlyDatabase(Database, map[string]table.ITable{
+		"tables":      sys.tablesTable(),
+		"columns":     sys.columnsTable(),
+		"indexes":     sys.indexesTable(),
+		"parts":       sys.partsTable(),
+		"merges":      sys.mergesTable(),
+		"connections": sys.connectionsTable(),
+	})
+}
+
+// eachTable calls fn for tables of all databases ordered by names.
+func (sys *System) eachTable(fn func(db string, name string, t table.ITable)) {
+	dbs := sys.Databases
+	for _, dbName := range sortedKeys(dbs) {
+		tables := dbs[dbName].Tables()
+		for _, name := range sortedKeys(tables) {
+			fn(dbName, name, tables[name])
+		}
+	}
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+func col(name string, meta types.DataTypeMeta, nullable bool) *column.Column {
+	c := column.New(name, meta)
+	c.Nullable = nullable
+	return c
+}
+
+func strMeta() types.DataTypeMeta      { return types.Meta(types.TYPE_STRING) }
+func numMeta() types.DataTypeMeta      { return types.Meta(types.TYPE_INTEGER, false, 8, false) }
+func flagMeta() types.DataTypeMeta     { return types.Meta(types.TYPE_INTEGER, false, 1, false) }
+func datetimeMeta() types.DataTypeMeta { return types.Meta(types.TYPE_DATETIME) }
+
+func str(v string) types.DataType {
+	return types.Type(strMeta()).Set(v)
+}
+
+func num(v uint64) types.DataType {
+	return types.Type(numMeta()).Set(v)
+}
+
+// flag is boolean value as UInt8 0 or 1.
+func flag(v bool) types.DataType {
+	if v {
+		return types.Type(flagMeta()).Set(1)
+	}
+	return types.Type(flagMeta()).Set(0)
+}
+
+func datetime(v time.Time) types.DataType {
+	return types.Type(datetimeMeta()).Set(v.Unix())
+}
+
+// literal returns value as it's written in query.
+func literal(v types.DataType) string {
+	return string(helpers.MustVal(json.Marshal(v)))
+}
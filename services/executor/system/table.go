@@ -0,0 +1,149 @@
+package system
+
+import (
+	"fmt"
+
+	"go-dbms/pkg/column"
+	"go-dbms/pkg/index"
+	"go-dbms/pkg/statement"
+	"go-dbms/pkg/table"
+	"go-dbms/pkg/types"
+	"go-dbms/util/stream"
+
+	"github.com/pkg/errors"
+	"golang.org/x/sync/errgroup"
+)
+
+// Engine of tables of system database.
+const Engine table.Engine = "System"
+
+var ErrReadOnly = errors.New("system table is read-only")
+
+// Table is read-only table without indexes, its rows
+// are built from in-memory state on each scan.
+type Table struct {
+	columns []*column.Column
+	rows    func() []types.DataRow
+}
+
+func newTable(rows func() []types.DataRow, columns ...*column.Column) *Table {
+	return &Table{columns: columns, rows: rows}
+}
+
+func (t *Table) Insert(in stream.Reader[types.DataRow]) (stream.Reader[types.DataRow], *errgroup.Group) {
+	return readOnly()
+}
+
+func (t *Table) Upsert(in stream.Reader[types.DataRow], oc *table.OnConflict) (stream.Reader[types.DataRow], *errgroup.Group) {
+	return readOnly()
+}
+
+func (t *Table) Find(filter *statement.WhereStatement) stream.Reader[index.Entry] {
+	s := stream.New[index.Entry](1)
+	go func() {
+		defer s.Close()
+		for _, row := range t.rows() {
+			if filter == nil || filter.Compare(row) {
+				s.Push(index.Entry{Row: row})
+			}
+		}
+	}()
+	return s
+}
+
+func (t *Table) ScanByIndex(name string, start, end *index.Filter) (stream.ReaderContinue[types.DataRow], error) {
+	return nil, fmt.Errorf("index not found => %v", name)
+}
+
+func (t *Table) FullScan() stream.ReaderContinue[types.DataRow] {
+	s := stream.New[types.DataRow](1)
+	go func() {
+		defer s.Close()
+		for _, row := range t.rows() {
+			s.Push(row)
+			if !s.ShouldContinue() {
+				return
+			}
+		}
+	}()
+	return s
+}
+
+// FullScanByIndex scans rows in order they are built, table has
+// no indexes, so only its empty primary key is accepted.
+func (t *Table) FullScanByIndex(indexName string, reverse bool) (stream.ReaderContinue[types.DataRow], error) {
+	if indexName != t.PrimaryKey() || reverse {
+		return nil, fmt.Errorf("index not found => %v", indexName)
+	}
+	return t.FullScan(), nil
+}
+
+func (t *Table) Update(filter *statement.WhereStatement, set table.Assignment) (stream.Reader[types.DataRow], *errgroup.Group) {
+	return readOnly()
+}
+
+func (t *Table) UpdateByIndex(
+	name string,
+	start, end *index.Filter,
+	filter *statement.WhereStatement,
+	set table.Assignment,
+) (stream.Reader[types.DataRow], *errgroup.Group) {
+	return readOnly()
+}
+
+func (t *Table) Delete(filter *statement.WhereStatement) (stream.Reader[types.DataRow], error) {
+	return nil, ErrReadOnly
+}
+
+func (t *Table) DeleteByIndex(name string, start, end *index.Filter, filter *statement.WhereStatement) (stream.Reader[types.DataRow], error) {
+	return nil, ErrReadOnly
+}
+
+func (t *Table) PrepareSpace(rows int) {}
+
+func (t *Table) Column(name string) *column.Column {
+	for _, col := range t.columns {
+		if col.Name == name {
+			return col
+		}
+	}
+	return nil
+}
+
+func (t *Table) Columns() []*column.Column {
+	return t.columns
+}
+
+func (t *Table) ColumnsMap() map[string]*column.Column {
+	columns := make(map[string]*column.Column, len(t.columns))
+	for _, col := range t.columns {
+		columns[col.Name] = col
+	}
+	return columns
+}
+
+func (t *Table) CreateIndex(name *string, opts *index.IndexOptions) error { return ErrReadOnly }
+func (t *Table) DropIndex(name string) error                             { return ErrReadOnly }
+func (t *Table) HasIndex(name string) bool                               { return false }
+func (t *Table) IndexMeta(name string) *index.Meta                       { return nil }
+func (t *Table) IndexesMeta() []*index.Meta                              { return nil }
+
+func (t *Table) PrimaryColumns() []*column.Column { return nil }
+func (t *Table) PrimaryKey() string               { return "" }
+
+func (t *Table) Alter(a *table.Alteration) error { return ErrReadOnly }
+func (t *Table) Truncate() error                 { return ErrReadOnly }
+
+func (t *Table) Engine() table.Engine { return Engine }
+func (t *Table) Drop()                {}
+func (t *Table) Close()               {}
+
+// readOnly is result of modification of system table, which fails.
+func readOnly() (stream.Reader[types.DataRow], *errgroup.Group) {
+	s := stream.New[types.DataRow](0)
+	s.Close()
+
+	eg := &errgroup.Group{}
+	eg.Go(func() error { return ErrReadOnly })
+	return s, eg
+}
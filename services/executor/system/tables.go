@@ -0,0 +1,217 @@
+package system
+
+import (
+	"strings"
+
+	"go-dbms/pkg/engine/mergetree"
+	"go-dbms/pkg/table"
+	"go-dbms/pkg/types"
+)
+
+// parted is table stored in parts, e.g. MergeTree.
+type parted interface {
+	PartsIterator(yield func(name string, part *table.Table) bool)
+}
+
+// eachPart calls fn for parts of table, table is single part unless it's stored in parts.
+// Tables of system database have no parts.
+func eachPart(t table.ITable, fn func(name string, part *table.Table)) {
+	switch t := t.(type) {
+		case parted:
+			t.PartsIterator(func(name string, part *table.Table) bool {
+				fn(name, part)
+				return true
+			})
+		case *table.Table:
+			fn("", t)
+	}
+}
+
+// tablesTable is system.tables, which lists tables of all databases with
+// their rows and size of data files, they are NULL for system tables.
+func (sys *System) tablesTable() *Table {
+	return newTable(func() []types.DataRow {
+		rows := []types.DataRow{}
+		sys.eachTable(func(db, name string, t table.ITable) {
+			row := types.DataRow{
+				"database":    str(db),
+				"name":        str(name),
+				"engine":      str(string(t.Engine())),
+				"total_rows":  nil,
+				"total_bytes": nil,
+			}
+
+			var count, size uint64
+			found := false
+			eachPart(t, func(_ string, part *table.Table) {
+				partRows, partSize := part.Stats()
+				count, size, found = count+partRows, size+partSize, true
+			})
+			if found {
+				row["total_rows"], row["total_bytes"] = num(count), num(size)
+			}
+			rows = append(rows, row)
+		})
+		return rows
+	},
+		col("database", strMeta(), false),
+		col("name", strMeta(), false),
+		col("engine", strMeta(), false),
+		col("total_rows", numMeta(), true),
+		col("total_bytes", numMeta(), true),
+	)
+}
+
+// columnsTable is system.columns, which lists columns of tables in their order.
+func (sys *System) columnsTable() *Table {
+	return newTable(func() []types.DataRow {
+		rows := []types.DataRow{}
+		sys.eachTable(func(db, name string, t table.ITable) {
+			for i, c := range t.Columns() {
+				row := types.DataRow{
+					"database":   str(db),
+					"table_name": str(name),
+					"name":       str(c.Name),
+					"position":   num(uint64(i + 1)),
+					"type":       str(types.Name(c.Meta)),
+					"nullable":   flag(c.Nullable),
+					"default":    nil,
+				}
+				if c.Default != nil {
+					row["default"] = str(literal(c.Default))
+				}
+				rows = append(rows, row)
+			}
+		})
+		return rows
+	},
+		col("database", strMeta(), false),
+		col("table_name", strMeta(), false),
+		col("name", strMeta(), false),
+		col("position", numMeta(), false),
+		col("type", strMeta(), false),
+		col("nullable", flagMeta(), false),
+		col("default", strMeta(), true),
+	)
+}
+
+// indexesTable is system.indexes, which lists indexes of tables.
+func (sys *System) indexesTable() *Table {
+	return newTable(func() []types.DataRow {
+		rows := []types.DataRow{}
+		sys.eachTable(func(db, name string, t table.ITable) {
+			for _, meta := range t.IndexesMeta() {
+				rows = append(rows, types.DataRow{
+					"database":   str(db),
+					"table_name": str(name),
+					"name":       str(meta.Name),
+					"columns":    str(strings.Join(meta.Columns, ", ")),
+					"is_primary": flag(meta.Name == t.PrimaryKey()),
+					"uniq":       flag(meta.Uniq),
+				})
+			}
+		})
+		return rows
+	},
+		col("database", strMeta(), false),
+		col("table_name", strMeta(), false),
+		col("name", strMeta(), false),
+		col("columns", strMeta(), false),
+		col("is_primary", flagMeta(), false),
+		col("uniq", flagMeta(), false),
+	)
+}
+
+// partsTable is system.parts, which lists parts of tables stored in parts,
+// master table of MergeTree is part without name.
+func (sys *System) partsTable() *Table {
+	return newTable(func() []types.DataRow {
+		rows := []types.DataRow{}
+		sys.eachTable(func(db, name string, t table.ITable) {
+			if _, ok := t.(mergetree.IMergeTree); !ok {
+				return
+			}
+
+			eachPart(t, func(partName string, part *table.Table) {
+				count, size := part.Stats()
+				rows = append(rows, types.DataRow{
+					"database":    str(db),
+					"table_name":  str(name),
+					"part":        str(partName),
+					"master":      flag(partName == ""),
+					"total_rows":  num(count),
+					"total_bytes": num(size),
+				})
+			})
+		})
+		return rows
+	},
+		col("database", strMeta(), false),
+		col("table_name", strMeta(), false),
+		col("part", strMeta(), false),
+		col("master", flagMeta(), false),
+		col("total_rows", numMeta(), false),
+		col("total_bytes", numMeta(), false),
+	)
+}
+
+// mergesTable is system.merges, which lists merges in progress.
+func (sys *System) mergesTable() *Table {
+	return newTable(func() []types.DataRow {
+		rows := []types.DataRow{}
+		sys.eachTable(func(db, name string, t table.ITable) {
+			mt, ok := t.(mergetree.IMergeTree)
+			if !ok {
+				return
+			}
+
+			status, ok := mt.Merging()
+			if !ok {
+				return
+			}
+			rows = append(rows, types.DataRow{
+				"database":   str(db),
+				"table_name": str(name),
+				"started":    datetime(status.Started),
+				"parts":      num(uint64(status.Parts)),
+				"merged":     num(uint64(status.Merged)),
+				"part":       str(status.Part),
+			})
+		})
+		return rows
+	},
+		col("database", strMeta(), false),
+		col("table_name", strMeta(), false),
+		col("started", datetimeMeta(), false),
+		col("parts", numMeta(), false),
+		col("merged", numMeta(), false),
+		col("part", strMeta(), false),
+	)
+}
+
+// connectionsTable is system.connections, which lists connected clients.
+func (sys *System) connectionsTable() *Table {
+	return newTable(func() []types.DataRow {
+		rows := []types.DataRow{}
+		if sys.Connections == nil {
+			return rows
+		}
+
+		for _, c := range sys.Connections() {
+			rows = append(rows, types.DataRow{
+				"id":        num(c.ID),
+				"address":   str(c.Addr),
+				"database":  str(c.Database),
+				"connected": datetime(c.Connected),
+				"queries":   num(c.Queries),
+			})
+		}
+		return rows
+	},
+		col("id", numMeta(), false),
+		col("address", strMeta(), false),
+		col("database", strMeta(), false),
+		col("connected", datetimeMeta(), false),
+		col("queries", numMeta(), false),
+	)
+}
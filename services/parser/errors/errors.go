@@ -1,6 +1,9 @@
 package errors
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
 	ErrSyntax        = errors.New("syntax error")
@@ -8,4 +11,19 @@ var (
 	ErrNoFrom        = errors.New("empty 'FROM' clause")
 	ErrNoWhereIndex  = errors.New("empty 'WHERE_INDEX' clause")
 	ErrInvalidEngine = errors.New("invalid engine")
+	ErrUnboundParams = errors.New("query has parameters, it must be prepared")
 )
+
+// SyntaxError is syntax error with position of token it occurred at.
+type SyntaxError struct {
+	Line, Col int
+	Msg       string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("%v at line %d col %d: %s", ErrSyntax, e.Line, e.Col, e.Msg)
+}
+
+func (e *SyntaxError) Unwrap() error {
+	return ErrSyntax
+}
@@ -4,13 +4,41 @@ import "go-dbms/pkg/types"
 
 var KeyWords = map[string]struct{}{
 	"SELECT":      {},
+	"DISTINCT":    {},
 	"FROM":        {},
+	"USE_INDEX":   {},
 	"WHERE_INDEX": {},
+	"JOIN":        {},
+	"INNER":       {},
+	"LEFT":        {},
+	"OUTER":       {},
+	"ON":          {},
 	"WHERE":       {},
 	"GROUP_BY":    {},
+	"GROUP":       {},
 	"HAVING":      {},
 	"ORDER_BY":    {},
+	"ORDER":       {},
+	"ASC":         {},
+	"DESC":        {},
 	"LIMIT":       {},
+	"OFFSET":      {},
+	"UNION":       {},
+	"ALL":         {},
+	"INTERSECT":   {},
+	"EXCEPT":      {},
+	"IN":          {},
+	"NOT":         {},
+	"BETWEEN":     {},
+	"LIKE":        {},
+	"ILIKE":       {},
+	"IS":          {},
+	"NULL":        {},
+	"CASE":        {},
+	"WHEN":        {},
+	"THEN":        {},
+	"ELSE":        {},
+	"END":         {},
 
 	"INSERT": {},
 	"VALUES": {},
@@ -23,6 +51,11 @@ var KeyWords = map[string]struct{}{
 	"PREPARE": {},
 	"TABLE":   {},
 	"ROWS":    {},
+
+	"EXPLAIN": {},
+	"ANALYZE": {},
+
+	"USE": {},
 }
 
 var IndexOperators = map[types.Operator]struct{}{
@@ -37,3 +70,51 @@ var LogicalOperators = map[string]struct{}{
 	"AND": {},
 	"OR":  {},
 }
+
+// Words are words which are recognized by parser case-insensitively,
+// besides key words and logical operators. Unlike them words aren't
+// reserved, so they can be used as identifiers, e.g. database 'default'.
+var Words = map[string]struct{}{
+	"AS":        {},
+	"BY":        {},
+	"INTO":      {},
+	"IF":        {},
+	"CREATE":    {},
+	"DROP":      {},
+	"DATABASE":  {},
+	"EXISTS":    {},
+	"ENGINE":    {},
+	"PRIMARY":   {},
+	"KEY":       {},
+	"INDEX":     {},
+	"UNIQUE":    {},
+	"AUTO":      {},
+	"INCREMENT": {},
+	"ALTER":     {},
+	"COLUMN":    {},
+	"RENAME":    {},
+	"TO":        {},
+	"MODIFY":    {},
+	"DEFAULT":   {},
+	"TRUNCATE":  {},
+	"REPLACE":   {},
+	"IGNORE":    {},
+	"DUPLICATE": {},
+	"RETURNING": {},
+	"SHOW":      {},
+	"DESCRIBE":  {},
+	"VIEW":      {},
+}
+
+// IsReserved checks if word is key word or logical operator,
+// which can't be used as identifier unless it's quoted.
+func IsReserved(word string) bool {
+	_, isKW := KeyWords[word]
+	_, isLogical := LogicalOperators[word]
+	return isKW || isLogical
+}
+
+func IsWord(word string) bool {
+	_, isWord := Words[word]
+	return isWord
+}
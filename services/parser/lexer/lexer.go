@@ -0,0 +1,230 @@
+package lexer
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"go-dbms/pkg/types"
+	"go-dbms/services/parser/errors"
+	"go-dbms/services/parser/kwords"
+)
+
+// maxParams limits number of numbered parameter
+const maxParams = 1 << 16
+
+// Param is placeholder of query parameter, value is bound to it before execution.
+type Param interface {
+	Bind(val types.DataType)
+}
+
+// Lexer is cursor over query tokens. Parsers report syntax errors
+// by panicking with *errors.SyntaxError pointing to current token.
+type Lexer struct {
+	tokens []Token
+	pos    int
+
+	// params[i] are placeholders of parameter i+1, both positional and
+	// numbered placeholders can't be used in the same query
+	params     [][]Param
+	positional int
+	numbered   bool
+
+	// database of unqualified table names
+	database string
+}
+
+func New(src []byte) (*Lexer, error) {
+	tokens, err := Tokenize(src)
+	if err != nil {
+		return nil, err
+	}
+	return &Lexer{tokens: tokens}, nil
+}
+
+// Token returns current token.
+func (l *Lexer) Token() Token {
+	return l.tokens[l.pos]
+}
+
+// TokenText returns text of current token, empty for end of query.
+func (l *Lexer) TokenText() string {
+	return l.tokens[l.pos].Text
+}
+
+// Scan moves to the next token and returns it.
+func (l *Lexer) Scan() Token {
+	if l.pos < len(l.tokens)-1 {
+		l.pos++
+	}
+	return l.tokens[l.pos]
+}
+
+// Peek returns token following the current one.
+func (l *Lexer) Peek() Token {
+	return l.tokens[min(l.pos+1, len(l.tokens)-1)]
+}
+
+// Pos returns position of cursor, which can be restored by Reset.
+func (l *Lexer) Pos() int {
+	return l.pos
+}
+
+func (l *Lexer) Reset(pos int) {
+	l.pos = pos
+}
+
+// Text returns text of tokens from position start till current token.
+// Text is tokenized to the same tokens, though spaces and comments of
+// source aren't kept.
+func (l *Lexer) Text(start int) string {
+	texts := make([]string, 0, l.pos-start)
+	for _, tok := range l.tokens[start:l.pos] {
+		if tok.Quoted {
+			texts = append(texts, "`"+tok.Text+"`")
+		} else {
+			texts = append(texts, tok.Text)
+		}
+	}
+	return strings.Join(texts, " ")
+}
+
+// Is checks if current token is one of keywords or operators.
+func (l *Lexer) Is(words ...string) bool {
+	_, ok := l.match(words)
+	return ok
+}
+
+// match returns one of words, which current token is. Words which aren't
+// reserved keep their case in tokens, so they are compared case-insensitively.
+func (l *Lexer) match(words []string) (string, bool) {
+	tok := l.Token()
+	if tok.Quoted || tok.Kind != Ident && tok.Kind != Op {
+		return "", false
+	}
+
+	text := tok.Text
+	if upper := strings.ToUpper(text); tok.Kind == Ident && kwords.IsWord(upper) {
+		text = upper
+	}
+	return text, slices.Contains(words, text)
+}
+
+// IsKeyword checks if current token is reserved word, which can't be identifier.
+func (l *Lexer) IsKeyword() bool {
+	tok := l.Token()
+	return tok.Kind == Ident && !tok.Quoted && kwords.IsReserved(tok.Text)
+}
+
+// Expect checks that current token is one of words and moves to the next one.
+// Returns matched word as it's written in words.
+func (l *Lexer) Expect(words ...string) string {
+	word, ok := l.match(words)
+	if !ok {
+		names := make([]string, len(words))
+		for i, w := range words {
+			if unicode.IsLetter(rune(w[0])) {
+				names[i] = w
+			} else {
+				names[i] = fmt.Sprintf("%q", w)
+			}
+		}
+		l.Unexpected(strings.Join(names, " or "))
+	}
+
+	l.Scan()
+	return word
+}
+
+// Ident checks that current token is identifier and moves to the next one.
+func (l *Lexer) Ident() string {
+	if l.Token().Kind != Ident || l.IsKeyword() {
+		l.Unexpected("identifier")
+	}
+
+	name := l.TokenText()
+	l.Scan()
+	return name
+}
+
+// TableName reads table name, which can be qualified by database name
+// as 'db.table'. Database of unqualified name is the one set by UseDatabase.
+func (l *Lexer) TableName() (db, table string) {
+	table = l.Ident()
+	if !l.Is(".") {
+		return l.database, table
+	}
+
+	l.Scan()
+	return table, l.Ident()
+}
+
+// UseDatabase sets database of unqualified table names.
+func (l *Lexer) UseDatabase(db string) {
+	l.database = db
+}
+
+// Database returns database of unqualified table names.
+func (l *Lexer) Database() string {
+	return l.database
+}
+
+// Param checks that current token is placeholder, registers p as placeholder
+// of its parameter and moves to the next token.
+func (l *Lexer) Param(p Param) {
+	tok := l.Token()
+	if tok.Kind != Placeholder {
+		l.Unexpected("parameter")
+	}
+
+	var n int
+	if tok.Text == "?" {
+		if l.numbered {
+			l.Errorf("positional and numbered parameters can't be mixed")
+		}
+		l.positional++
+		n = l.positional
+	} else {
+		if l.positional != 0 {
+			l.Errorf("positional and numbered parameters can't be mixed")
+		}
+		l.numbered = true
+
+		var err error
+		if n, err = strconv.Atoi(tok.Text[1:]); err != nil || n < 1 || n > maxParams {
+			l.Errorf("invalid parameter number %v", tok)
+		}
+	}
+
+	for len(l.params) < n {
+		l.params = append(l.params, nil)
+	}
+	l.params[n-1] = append(l.params[n-1], p)
+	l.Scan()
+}
+
+// Params returns placeholders registered by Param grouped by parameter number.
+func (l *Lexer) Params() [][]Param {
+	return l.params
+}
+
+// Unexpected reports that current token isn't the expected one.
+func (l *Lexer) Unexpected(expected string) {
+	l.Errorf("expected %s, got %v", expected, l.Token())
+}
+
+// Errorf reports syntax error at position of current token.
+func (l *Lexer) Errorf(format string, args ...interface{}) {
+	l.ErrorAt(l.Token(), format, args...)
+}
+
+// ErrorAt reports syntax error at position of token tok.
+func (l *Lexer) ErrorAt(tok Token, format string, args ...interface{}) {
+	panic(&errors.SyntaxError{
+		Line: tok.Line,
+		Col:  tok.Col,
+		Msg:  fmt.Sprintf(format, args...),
+	})
+}
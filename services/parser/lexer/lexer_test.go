@@ -0,0 +1,42 @@
+package lexer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTableName(t *testing.T) {
+	s, err := New([]byte("a.t1 t2"))
+	require.NoError(t, err)
+	s.UseDatabase("b")
+
+	db, table := s.TableName()
+	require.Equal(t, "a", db)
+	require.Equal(t, "t1", table)
+
+	db, table = s.TableName()
+	require.Equal(t, "b", db)
+	require.Equal(t, "t2", table)
+	require.Equal(t, EOF, s.Token().Kind)
+}
+
+func TestText(t *testing.T) {
+	s, err := New([]byte("SELECT `from`, 'it''s' /* comment */ FROM t WHERE a>=1.5 ;"))
+	require.NoError(t, err)
+
+	for !s.Is(";") {
+		s.Scan()
+	}
+	text := s.Text(0)
+	require.Equal(t, "SELECT `from` , \"it's\" FROM t WHERE a >= 1.5", text)
+
+	tokens, err := Tokenize([]byte(text))
+	require.NoError(t, err)
+	require.Len(t, tokens, s.Pos()+1)
+	for i, tok := range tokens[:s.Pos()] {
+		require.Equal(t, s.tokens[i].Kind, tok.Kind)
+		require.Equal(t, s.tokens[i].Text, tok.Text)
+		require.Equal(t, s.tokens[i].Quoted, tok.Quoted)
+	}
+}
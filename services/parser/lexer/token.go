@@ -0,0 +1,31 @@
+package lexer
+
+import "fmt"
+
+type Kind uint8
+
+const (
+	EOF         Kind = iota
+	Ident            // identifier or keyword, keywords are uppercased
+	Int              // integer number
+	Float            // floating point number
+	String           // string literal in JSON format
+	Op               // operator or punctuation
+	Placeholder      // parameter placeholder, '?' or '$<number>'
+)
+
+type Token struct {
+	Kind   Kind
+	Text   string
+	Quoted bool // identifier in backticks, never treated as keyword
+	Line   int
+	Col    int
+}
+
+// String returns token as it's shown in error messages.
+func (t Token) String() string {
+	if t.Kind == EOF {
+		return "end of query"
+	}
+	return fmt.Sprintf("%q", t.Text)
+}
@@ -0,0 +1,244 @@
+package lexer
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"go-dbms/services/parser/errors"
+	"go-dbms/services/parser/kwords"
+)
+
+// operators of two characters, the rest operators are single characters
+var operators = map[string]struct{}{
+	">=": {},
+	"<=": {},
+	"!=": {},
+	"<>": {},
+}
+
+const punctuation = "(),;.=<>+-*/%!"
+
+type tokenizer struct {
+	src       []rune
+	pos       int
+	line, col int
+	tokens    []Token
+}
+
+// Tokenize splits query text into tokens. Comments ('--', '//' and '/* */') are skipped,
+// reserved words are uppercased, single quoted strings are converted to JSON format.
+func Tokenize(src []byte) (tokens []Token, err error) {
+	t := &tokenizer{src: []rune(string(src)), line: 1, col: 1}
+	for {
+		if err := t.skipSpace(); err != nil {
+			return nil, err
+		}
+
+		tok := Token{Line: t.line, Col: t.col}
+		if t.pos >= len(t.src) {
+			tok.Kind = EOF
+			return append(t.tokens, tok), nil
+		}
+
+		r := t.src[t.pos]
+		switch {
+			case r == '_' || unicode.IsLetter(r): t.word(&tok)
+			case unicode.IsDigit(r):             t.number(&tok)
+			case r == '`':                       err = t.quotedIdent(&tok)
+			case r == '"':                       err = t.doubleQuoted(&tok)
+			case r == '\'':                      err = t.singleQuoted(&tok)
+			case r == '?' || r == '$':           err = t.param(&tok)
+			case strings.ContainsRune(punctuation, r):
+				tok.Kind = Op
+				tok.Text = string(r)
+				if t.pos+1 < len(t.src) {
+					if _, ok := operators[string(t.src[t.pos:t.pos+2])]; ok {
+						tok.Text = string(t.src[t.pos : t.pos+2])
+						t.advance()
+					}
+				}
+				t.advance()
+			default:
+				err = t.errorf(tok, "unexpected character %q", r)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+		t.tokens = append(t.tokens, tok)
+	}
+}
+
+func (t *tokenizer) advance() {
+	if t.src[t.pos] == '\n' {
+		t.line++
+		t.col = 1
+	} else {
+		t.col++
+	}
+	t.pos++
+}
+
+func (t *tokenizer) peek(offset int) rune {
+	if t.pos+offset >= len(t.src) {
+		return 0
+	}
+	return t.src[t.pos+offset]
+}
+
+func (t *tokenizer) skipSpace() error {
+	for t.pos < len(t.src) {
+		switch r := t.src[t.pos]; {
+			case unicode.IsSpace(r):
+				t.advance()
+			case r == '-' && t.peek(1) == '-', r == '/' && t.peek(1) == '/':
+				for t.pos < len(t.src) && t.src[t.pos] != '\n' {
+					t.advance()
+				}
+			case r == '/' && t.peek(1) == '*':
+				tok := Token{Line: t.line, Col: t.col}
+				t.advance()
+				t.advance()
+				for t.pos < len(t.src) && !(t.src[t.pos] == '*' && t.peek(1) == '/') {
+					t.advance()
+				}
+				if t.pos >= len(t.src) {
+					return t.errorf(tok, "unterminated comment")
+				}
+				t.advance()
+				t.advance()
+			default:
+				return nil
+		}
+	}
+	return nil
+}
+
+func (t *tokenizer) word(tok *Token) {
+	start := t.pos
+	for t.pos < len(t.src) && (t.src[t.pos] == '_' || unicode.IsLetter(t.src[t.pos]) || unicode.IsDigit(t.src[t.pos])) {
+		t.advance()
+	}
+
+	tok.Kind = Ident
+	tok.Text = string(t.src[start:t.pos])
+	if upper := strings.ToUpper(tok.Text); kwords.IsReserved(upper) {
+		tok.Text = upper
+	}
+}
+
+func (t *tokenizer) number(tok *Token) {
+	start := t.pos
+	tok.Kind = Int
+	for unicode.IsDigit(t.peek(0)) {
+		t.advance()
+	}
+	if t.peek(0) == '.' && unicode.IsDigit(t.peek(1)) {
+		tok.Kind = Float
+		t.advance()
+		for unicode.IsDigit(t.peek(0)) {
+			t.advance()
+		}
+	}
+	if e := t.peek(0); (e == 'e' || e == 'E') && (unicode.IsDigit(t.peek(1)) ||
+		(t.peek(1) == '-' || t.peek(1) == '+') && unicode.IsDigit(t.peek(2))) {
+		tok.Kind = Float
+		t.advance()
+		t.advance()
+		for unicode.IsDigit(t.peek(0)) {
+			t.advance()
+		}
+	}
+	tok.Text = string(t.src[start:t.pos])
+}
+
+func (t *tokenizer) quotedIdent(tok *Token) error {
+	t.advance()
+	start := t.pos
+	for t.pos < len(t.src) && t.src[t.pos] != '`' {
+		t.advance()
+	}
+	if t.pos >= len(t.src) {
+		return t.errorf(*tok, "unterminated quoted identifier")
+	}
+
+	tok.Kind = Ident
+	tok.Quoted = true
+	tok.Text = string(t.src[start:t.pos])
+	t.advance()
+	return nil
+}
+
+func (t *tokenizer) doubleQuoted(tok *Token) error {
+	start := t.pos
+	for t.advance(); t.pos < len(t.src) && t.src[t.pos] != '"'; t.advance() {
+		if t.src[t.pos] == '\\' && t.pos+1 < len(t.src) {
+			t.advance()
+		}
+	}
+	if t.pos >= len(t.src) {
+		return t.errorf(*tok, "unterminated string")
+	}
+	t.advance()
+
+	tok.Kind = String
+	tok.Text = string(t.src[start:t.pos])
+	if !json.Valid([]byte(tok.Text)) {
+		return t.errorf(*tok, "invalid string %s", tok.Text)
+	}
+	return nil
+}
+
+// singleQuoted reads SQL string, where quote is escaped by doubling it.
+func (t *tokenizer) singleQuoted(tok *Token) error {
+	val := []rune{}
+	for t.advance(); ; t.advance() {
+		if t.pos >= len(t.src) {
+			return t.errorf(*tok, "unterminated string")
+		} else if t.src[t.pos] == '\'' {
+			if t.peek(1) != '\'' {
+				break
+			}
+			t.advance()
+		}
+		val = append(val, t.src[t.pos])
+	}
+	t.advance()
+
+	text, err := json.Marshal(string(val))
+	if err != nil {
+		return err
+	}
+
+	tok.Kind = String
+	tok.Text = string(text)
+	return nil
+}
+
+// param reads positional '?' or numbered '$<number>' placeholder.
+func (t *tokenizer) param(tok *Token) error {
+	start := t.pos
+	t.advance()
+	if t.src[start] == '$' {
+		if !unicode.IsDigit(t.peek(0)) {
+			return t.errorf(*tok, "expected number of parameter after '$'")
+		}
+		for unicode.IsDigit(t.peek(0)) {
+			t.advance()
+		}
+	}
+
+	tok.Kind = Placeholder
+	tok.Text = string(t.src[start:t.pos])
+	return nil
+}
+
+func (t *tokenizer) errorf(tok Token, format string, args ...interface{}) error {
+	return &errors.SyntaxError{
+		Line: tok.Line,
+		Col:  tok.Col,
+		Msg:  fmt.Sprintf(format, args...),
+	}
+}
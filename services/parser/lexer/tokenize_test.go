@@ -0,0 +1,76 @@
+package lexer
+
+import (
+	"testing"
+
+	"go-dbms/pkg/types"
+	"go-dbms/services/parser/errors"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenize(t *testing.T) {
+	tokens, err := Tokenize([]byte("select `from`, 'it''s' -- comment\n// /* multi\n/* multi\nline */ FROM t where a>=1.5e3 AND b <> \"x\";"))
+	require.NoError(t, err)
+
+	expected := []Token{
+		{Kind: Ident, Text: "SELECT", Line: 1, Col: 1},
+		{Kind: Ident, Text: "from", Quoted: true, Line: 1, Col: 8},
+		{Kind: Op, Text: ",", Line: 1, Col: 14},
+		{Kind: String, Text: `"it's"`, Line: 1, Col: 16},
+		{Kind: Ident, Text: "FROM", Line: 4, Col: 9},
+		{Kind: Ident, Text: "t", Line: 4, Col: 14},
+		{Kind: Ident, Text: "WHERE", Line: 4, Col: 16},
+		{Kind: Ident, Text: "a", Line: 4, Col: 22},
+		{Kind: Op, Text: ">=", Line: 4, Col: 23},
+		{Kind: Float, Text: "1.5e3", Line: 4, Col: 25},
+		{Kind: Ident, Text: "AND", Line: 4, Col: 31},
+		{Kind: Ident, Text: "b", Line: 4, Col: 35},
+		{Kind: Op, Text: "<>", Line: 4, Col: 37},
+		{Kind: String, Text: `"x"`, Line: 4, Col: 40},
+		{Kind: Op, Text: ";", Line: 4, Col: 43},
+		{Kind: EOF, Line: 4, Col: 44},
+	}
+	require.Equal(t, expected, tokens)
+}
+
+func TestTokenizeError(t *testing.T) {
+	_, err := Tokenize([]byte("SELECT a\nFROM t WHERE b = 'x"))
+	require.ErrorIs(t, err, errors.ErrSyntax)
+	require.EqualError(t, err, "syntax error at line 2 col 18: unterminated string")
+
+	_, err = Tokenize([]byte("SELECT id FROM t /* oops;"))
+	require.ErrorIs(t, err, errors.ErrSyntax)
+	require.EqualError(t, err, "syntax error at line 1 col 18: unterminated comment")
+
+	s, err := New([]byte("SELECT a\n  WHERE"))
+	require.NoError(t, err)
+	s.Scan()
+	s.Scan()
+	require.PanicsWithError(t, `syntax error at line 2 col 3: expected FROM, got "WHERE"`, func() {
+		s.Expect("FROM")
+	})
+}
+
+type param struct{ val types.DataType }
+
+func (p *param) Bind(val types.DataType) { p.val = val }
+
+func TestParams(t *testing.T) {
+	s, err := New([]byte("$2 $1 $2"))
+	require.NoError(t, err)
+	require.Equal(t, Token{Kind: Placeholder, Text: "$2", Line: 1, Col: 1}, s.Token())
+
+	a, b, c := &param{}, &param{}, &param{}
+	s.Param(a)
+	s.Param(b)
+	s.Param(c)
+	require.Equal(t, [][]Param{{b}, {a, c}}, s.Params())
+
+	s, err = New([]byte("? $1"))
+	require.NoError(t, err)
+	s.Param(&param{})
+	require.PanicsWithError(t, "syntax error at line 1 col 3: positional and numbered parameters can't be mixed", func() {
+		s.Param(&param{})
+	})
+}
@@ -1,28 +1,94 @@
 package parser
 
 import (
-	"errors"
 	"fmt"
-	"text/scanner"
+	"strings"
 
+	"go-dbms/services/parser/errors"
+	"go-dbms/services/parser/lexer"
 	"go-dbms/services/parser/query"
 	"go-dbms/services/parser/query/ddl"
 	"go-dbms/services/parser/query/dml"
+	"go-dbms/services/parser/query/show"
+	"go-dbms/util/helpers"
 )
 
-type ParserServiceT struct {  }
+type ParserServiceT struct {
+	// database of unqualified table names
+	database string
+}
 
 func New() *ParserServiceT {
 	return &ParserServiceT{}
 }
 
-func (ps *ParserServiceT) ParseQuery(s *scanner.Scanner) (query.Querier, error) {
-	qt := query.QueryType(s.TokenText())
+// Use returns parser, which qualifies table names without
+// database by db. Empty db leaves them unqualified.
+func (ps *ParserServiceT) Use(db string) query.Parser {
+	return &ParserServiceT{database: db}
+}
+
+// Parse parses text of single query, which can be terminated by ';'.
+// Query can't have parameters, use Prepare for parameterized queries.
+func (ps *ParserServiceT) Parse(src []byte) (query.Querier, error) {
+	p, err := ps.Prepare(src)
+	if err != nil {
+		return nil, err
+	} else if p.NumParams() != 0 {
+		return nil, errors.ErrUnboundParams
+	}
+	return p.Querier, nil
+}
+
+// Prepare parses text of single query with parameter placeholders.
+// Placeholders are either positional '?' or numbered '$<number>'.
+func (ps *ParserServiceT) Prepare(src []byte) (p *query.Prepared, err error) {
+	s, err := lexer.New(src)
+	if err != nil {
+		return nil, err
+	}
+	s.UseDatabase(ps.database)
+
+	q, err := ps.ParseQuery(s)
+	if err != nil {
+		return nil, err
+	}
+
+	defer helpers.RecoverOnError(&err)()
+	if s.Is(";") {
+		s.Scan()
+	}
+	if s.Token().Kind != lexer.EOF {
+		s.Unexpected("end of query")
+	}
+
+	params := s.Params()
+	for i, param := range params {
+		if param == nil {
+			return nil, fmt.Errorf("%w: parameter $%d isn't used", errors.ErrSyntax, i+1)
+		}
+	}
+	return query.NewPrepared(q, params), nil
+}
+
+// ParseQuery parses query starting at current token, cursor is left
+// at the first token after query.
+func (ps *ParserServiceT) ParseQuery(s *lexer.Lexer) (q query.Querier, err error) {
+	defer helpers.RecoverOnError(&err)()
+
+	qt := query.QueryType(strings.ToUpper(s.TokenText()))
 	switch qt {
-		case query.CREATE, query.DROP:
-			return ddl.Parse(s, qt)
-		case query.DELETE, query.INSERT, query.SELECT, query.UPDATE, query.PREPARE:
+		case query.CREATE, query.ALTER, query.DROP, query.TRUNCATE, query.RENAME:
+			return ddl.Parse(s, qt, ps)
+		case query.DELETE, query.INSERT, query.REPLACE, query.SELECT, query.UPDATE, query.PREPARE, query.EXPLAIN:
 			return dml.Parse(s, qt, ps)
+		case query.SHOW, query.DESCRIBE:
+			return show.Parse(s)
+		case query.USE:
+			qu := &query.QueryUse{}
+			return qu, qu.Parse(s, ps)
 	}
-	return nil, errors.New(fmt.Sprintf("unsupported query type: '%s'", qt))
+
+	s.Unexpected("query")
+	return nil, nil
 }
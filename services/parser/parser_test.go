@@ -0,0 +1,36 @@
+package parser
+
+import (
+	"testing"
+
+	"go-dbms/services/parser/query"
+	"go-dbms/services/parser/query/ddl/create"
+	"go-dbms/services/parser/query/dml"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWordsAsIdentifiers(t *testing.T) {
+	ps := New()
+
+	q, err := ps.Parse([]byte("USE default;"))
+	require.NoError(t, err)
+	require.Equal(t, "default", q.(*query.QueryUse).DB)
+
+	q, err = ps.Parse([]byte("SELECT id FROM default.t"))
+	require.NoError(t, err)
+	from := q.(*dml.QuerySelect).From
+	require.Equal(t, "default", from.DB)
+	require.Equal(t, "t", from.Table)
+
+	q, err = ps.Parse([]byte("create table Engine (database VARCHAR(8) default 'x', key UInt32) engine = InnoDB primary key(key) pk"))
+	require.NoError(t, err)
+	qct := q.(*create.QueryCreateTable)
+	require.Equal(t, "Engine", qct.Name)
+	require.Equal(t, "database", qct.Columns[0].Name)
+	require.Contains(t, qct.Defaults, "database")
+	require.Equal(t, "key", qct.Columns[1].Name)
+
+	_, err = ps.Parse([]byte("SELECT id FROM select.t"))
+	require.EqualError(t, err, `syntax error at line 1 col 16: expected identifier, got "SELECT"`)
+}
@@ -0,0 +1,84 @@
+package alter
+
+import (
+	"strings"
+
+	"go-dbms/pkg/column"
+	"go-dbms/pkg/table"
+	"go-dbms/services/parser/lexer"
+	"go-dbms/services/parser/query"
+	"go-dbms/services/parser/query/ddl/create"
+	"go-dbms/services/parser/query/dml"
+	"go-dbms/services/parser/query/dml/aggregator"
+	"go-dbms/services/parser/query/dml/projection"
+	"go-dbms/util/helpers"
+)
+
+/*
+ALTER TABLE [<dbName>.]<tableName>
+
+	ADD COLUMN <columnName> <type> [DEFAULT <expression>]
+	| DROP COLUMN <columnName>
+	| RENAME COLUMN <columnName> TO <newColumnName>
+	| MODIFY COLUMN <columnName> <type> [DEFAULT <expression>];
+*/
+type QueryAlterTable struct {
+	*query.Query
+	DB          string
+	Table       string
+	Action      table.AlterAction
+	Name        string
+	NewName     string
+	Column      *column.Column
+	Aggregation aggregator.AggregatorType
+	Default     *projection.Projection // constant expression, evaluated on execution
+}
+
+func Parse(s *lexer.Lexer, ps query.Parser) (q *QueryAlterTable, err error) {
+	defer helpers.RecoverOnError(&err)()
+
+	q = &QueryAlterTable{Query: &query.Query{Type: query.ALTER}}
+	return q, q.Parse(s, ps)
+}
+
+func (qa *QueryAlterTable) Parse(s *lexer.Lexer, ps query.Parser) (err error) {
+	defer helpers.RecoverOnError(&err)()
+
+	s.Expect("ALTER")
+	s.Expect("TABLE")
+	qa.DB, qa.Table = s.TableName()
+
+	// ADD isn't a word, because it's name of function too
+	switch strings.ToUpper(s.TokenText()) {
+		case "ADD":
+			qa.Action = table.ADD_COLUMN
+		case "DROP":
+			qa.Action = table.DROP_COLUMN
+		case "RENAME":
+			qa.Action = table.RENAME_COLUMN
+		case "MODIFY":
+			qa.Action = table.MODIFY_COLUMN
+		default:
+			s.Unexpected("ADD, DROP, RENAME or MODIFY")
+	}
+	s.Scan()
+	s.Expect("COLUMN")
+
+	switch qa.Action {
+		case table.ADD_COLUMN, table.MODIFY_COLUMN:
+			qa.Column, qa.Aggregation = create.ParseColumn(s)
+			qa.Name = qa.Column.Name
+			if s.Is("DEFAULT") {
+				s.Scan()
+				qa.Default = dml.ParseConstant(s, ps)
+			}
+		case table.DROP_COLUMN:
+			qa.Name = s.Ident()
+		case table.RENAME_COLUMN:
+			qa.Name = s.Ident()
+			s.Expect("TO")
+			qa.NewName = s.Ident()
+	}
+
+	return nil
+}
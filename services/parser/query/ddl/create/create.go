@@ -1,17 +1,20 @@
 package create
 
 import (
-	"errors"
-	"fmt"
+	"strings"
+
+	"go-dbms/services/parser/lexer"
 	"go-dbms/services/parser/query"
-	"text/scanner"
+	"go-dbms/util/helpers"
 )
 
 type QueryCreateTarget string
 
 const (
+	DATABASE QueryCreateTarget = "DATABASE"
 	TABLE    QueryCreateTarget = "TABLE"
 	INDEX    QueryCreateTarget = "INDEX"
+	VIEW     QueryCreateTarget = "VIEW"
 )
 
 type Creater interface {
@@ -28,18 +31,17 @@ func (qc *QueryCreate) GetTarget() QueryCreateTarget {
 	return qc.Target
 }
 
-func Parse(s *scanner.Scanner) (Creater, error) {
-	var q Creater
-	
-	s.Scan()
-	target := QueryCreateTarget(s.TokenText())
+func Parse(s *lexer.Lexer, ps query.Parser) (q Creater, err error) {
+	defer helpers.RecoverOnError(&err)()
 
-	switch target {
-		// case DATABASE: q = &QueryCreateDatabase{QueryCreate: &QueryCreate{Query: &query.Query{Type: query.CREATE}}}
-		case TABLE: q = &QueryCreateTable{QueryCreate: &QueryCreate{Query: &query.Query{Type: query.CREATE}}}
+	s.Expect("CREATE")
+	switch QueryCreateTarget(strings.ToUpper(s.TokenText())) {
+		case DATABASE: q = &QueryCreateDatabase{QueryCreate: &QueryCreate{Query: &query.Query{Type: query.CREATE}}}
+		case TABLE:    q = &QueryCreateTable{QueryCreate: &QueryCreate{Query: &query.Query{Type: query.CREATE}}}
+		case VIEW:     q = &QueryCreateView{QueryCreate: &QueryCreate{Query: &query.Query{Type: query.CREATE}}}
 		// case INDEX:    q = &QueryCreateIndex{QueryCreate: &QueryCreate{Query: &query.Query{Type: query.CREATE}}}
-		default:       return nil, errors.New(fmt.Sprintf("unsupported create target: '%s'", target))
+		default:       s.Unexpected("DATABASE, TABLE or VIEW")
 	}
 
-	return q, q.Parse(s, nil)
+	return q, q.Parse(s, ps)
 }
@@ -1,12 +1,32 @@
 package create
 
-import "text/scanner"
+import (
+	"go-dbms/services/parser/lexer"
+	"go-dbms/services/parser/query"
+	"go-dbms/util/helpers"
+)
 
+/*
+CREATE DATABASE [IF NOT EXISTS] <databaseName>;
+*/
 type QueryCreateDatabase struct {
 	*QueryCreate
-	Name string `json:"name"`
+	Name        string `json:"name"`
+	IfNotExists bool   `json:"if_not_exists"`
 }
 
-func (qs *QueryCreateDatabase) Parse(s *scanner.Scanner) (err error) {
+func (qs *QueryCreateDatabase) Parse(s *lexer.Lexer, ps query.Parser) (err error) {
+	defer helpers.RecoverOnError(&err)()
+
+	qs.Target = DATABASE
+
+	s.Expect("DATABASE")
+	if s.Is("IF") {
+		s.Scan()
+		s.Expect("NOT")
+		s.Expect("EXISTS")
+		qs.IfNotExists = true
+	}
+	qs.Name = s.Ident()
 	return nil
 }
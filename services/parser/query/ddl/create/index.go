@@ -2,8 +2,8 @@ package create
 
 import (
 	"go-dbms/pkg/index"
+	"go-dbms/services/parser/lexer"
 	"go-dbms/services/parser/query"
-	"text/scanner"
 )
 
 type QueryCreateTableIndex struct {
@@ -14,9 +14,10 @@ type QueryCreateTableIndex struct {
 type QueryCreateIndex struct {
 	*QueryCreate
 	QueryCreateTableIndex
+	DB    string `json:"db"`
 	Table string `json:"table"`
 }
 
-func (qs *QueryCreateIndex) Parse(s *scanner.Scanner, ps query.Parser) (err error) {
+func (qs *QueryCreateIndex) Parse(s *lexer.Lexer, ps query.Parser) (err error) {
 	return nil
 }
@@ -1,21 +1,24 @@
 package create
 
 import (
-	"text/scanner"
+	"strings"
 
 	"go-dbms/pkg/column"
 	"go-dbms/pkg/index"
 	"go-dbms/pkg/table"
 	"go-dbms/pkg/types"
 	"go-dbms/services/parser/errors"
-	"go-dbms/services/parser/kwords"
+	"go-dbms/services/parser/lexer"
 	"go-dbms/services/parser/query"
+	"go-dbms/services/parser/query/dml"
 	"go-dbms/services/parser/query/dml/aggregator"
+	"go-dbms/services/parser/query/dml/projection"
+	"go-dbms/util/helpers"
 )
 
 /*
-CREATE TABLE <tableName> (
-	<columnName> <type> [AUTO INCREMENT],
+CREATE TABLE [<dbName>.]<tableName> (
+	<columnName> <type | Nullable(<type>)> [AUTO INCREMENT] [DEFAULT <expression>],
 	...
 ) ENGINE = (InnoDB | MergeTree | AggregatingMergeTree | ...)
 PRIMARY KEY (<...columns>) <primaryKeyName>
@@ -30,23 +33,16 @@ type QueryCreateTable struct {
 	Indexes  []*QueryCreateTableIndex
 	Engine   table.Engine
 	AggrFunc map[string]aggregator.AggregatorType
+	Defaults map[string]*projection.Projection // constant expressions, evaluated on execution
 }
 
-func (qct *QueryCreateTable) Parse(s *scanner.Scanner, ps query.Parser) (err error) {
-	defer func ()  {
-		if r := recover(); r != nil {
-			var ok bool
-			err, ok = r.(error)
-			if !ok {
-				panic(r)
-			}
-		}
-	}()
+func (qct *QueryCreateTable) Parse(s *lexer.Lexer, ps query.Parser) (err error) {
+	defer helpers.RecoverOnError(&err)()
 
 	qct.Target = TABLE
 
 	qct.parseName(s)
-	qct.parseColumns(s)
+	qct.parseColumns(s, ps)
 	
 	qct.Indexes = []*QueryCreateTableIndex{}
 
@@ -57,224 +53,159 @@ func (qct *QueryCreateTable) Parse(s *scanner.Scanner, ps query.Parser) (err err
 	return nil
 }
 
-func (qct *QueryCreateTable) parseName(s *scanner.Scanner) {
-	s.Scan()
-	qct.Name = s.TokenText()
+func (qct *QueryCreateTable) parseName(s *lexer.Lexer) {
+	s.Expect("TABLE")
+	qct.Database, qct.Name = s.TableName()
 }
 
-func (qct *QueryCreateTable) parseColumns(s *scanner.Scanner) {
-	s.Scan()
-	if s.TokenText() != "(" {
-		panic(errors.ErrSyntax)
-	}
+func (qct *QueryCreateTable) parseColumns(s *lexer.Lexer, ps query.Parser) {
+	s.Expect("(")
 
 	qct.Columns = []*column.Column{}
-	for tok := s.Scan(); tok != scanner.EOF; tok = s.Scan() {
-		word := s.TokenText()
-		if word == ")" {
+	for !s.Is(")") {
+		qct.parseColumn(s, ps)
+		if !s.Is(",") {
 			break
 		}
-		qct.parseColumn(s)
+		s.Scan()
 	}
 
-	s.Scan()
+	s.Expect(")")
 }
 
-func (qct *QueryCreateTable) parseColumn(s *scanner.Scanner) {
-	scope := 0
-	col := &column.Column{}
-	colName := s.TokenText()
-
-	if _, isKW := kwords.KeyWords[colName]; isKW {
-		panic(errors.ErrSyntax)
+func (qct *QueryCreateTable) parseColumn(s *lexer.Lexer, ps query.Parser) {
+	col, aggr := ParseColumn(s)
+	if aggr != "" {
+		if qct.AggrFunc == nil {
+			qct.AggrFunc = map[string]aggregator.AggregatorType{}
+		}
+		qct.AggrFunc[col.Name] = aggr
+	}
+	if s.Is("DEFAULT") {
+		s.Scan()
+		if qct.Defaults == nil {
+			qct.Defaults = map[string]*projection.Projection{}
+		}
+		qct.Defaults[col.Name] = dml.ParseConstant(s, ps)
 	}
-	col.Name = colName
 
-	tokens := []string{}
-	for tok := s.Scan(); tok != scanner.EOF; tok = s.Scan() {
-		word := s.TokenText()
-		if word == "AggregateFunction" {
-			if qct.AggrFunc == nil {
-				qct.AggrFunc = map[string]aggregator.AggregatorType{}
-			}
+	qct.Columns = append(qct.Columns, col)
+}
 
-			s.Scan()
-			if s.TokenText() != "(" {
-				panic(errors.ErrSyntax)
-			}
-			scope++
+// ParseColumn parses column definition, aggr is set
+// if column type is AggregateFunction(<aggregator>, <type>).
+func ParseColumn(s *lexer.Lexer) (col *column.Column, aggr aggregator.AggregatorType) {
+	col = &column.Column{Name: s.Ident()}
 
-			s.Scan()
-			aggr := s.TokenText()
-			if !aggregator.IsAggregator(aggr) {
-				panic(errors.ErrSyntax)
-			}
+	if s.Is("AggregateFunction") {
+		s.Scan()
+		s.Expect("(")
 
-			qct.AggrFunc[col.Name] = aggregator.AggregatorType(aggr)
-			s.Scan()
-			if s.TokenText() != "," {
-				panic(errors.ErrSyntax)
-			}
-			continue
+		aggr = aggregator.AggregatorType(strings.ToUpper(s.TokenText()))
+		if !aggregator.IsAggregator(string(aggr)) {
+			s.Unexpected("aggregator")
 		}
+		s.Scan()
+		s.Expect(",")
+	}
 
-		if word == "(" {
-			scope++
-		}
-		if scope == 0 && (word == "," || word == ")") {
-			break
-		}
-		if word == ")" {
-			scope--
-		}
+	tok := s.Token()
+	tokens := parseType(s)
+	if aggr != "" {
+		s.Expect(")")
+	}
 
-		tokens = append(tokens, word)
+	// Nullable(<type>) wraps type allowing NULL values in column
+	if len(tokens) > 3 && tokens[0] == "Nullable" && tokens[1] == "(" && tokens[len(tokens)-1] == ")" {
+		col.Nullable = true
+		tokens = tokens[2:len(tokens)-1]
 	}
 
+	if len(tokens) == 0 {
+		s.ErrorAt(tok, "expected type, got %v", tok)
+	}
 	col.Meta = types.Parse(tokens)
 	col.Typ = col.Meta.GetCode()
 
-	qct.Columns = append(qct.Columns, col)
+	return col, aggr
 }
 
-func (qct *QueryCreateTable) parseEngine(s *scanner.Scanner) {
-	if s.TokenText() != "ENGINE" {
-		panic(errors.ErrSyntax)
-	}
+// parseType returns tokens of column type, which ends with ',', ')',
+// DEFAULT or end of query outside of parentheses.
+func parseType(s *lexer.Lexer) []string {
+	tokens := []string{}
+	for scope := 0; s.Token().Kind != lexer.EOF; s.Scan() {
+		if scope == 0 && s.Is(",", ")", "DEFAULT", ";") {
+			break
+		} else if s.Is("(") {
+			scope++
+		} else if s.Is(")") {
+			scope--
+		}
 
-	s.Scan()
-	if s.TokenText() != "=" {
-		panic(errors.ErrSyntax)
+		tokens = append(tokens, s.TokenText())
 	}
+	return tokens
+}
+
+func (qct *QueryCreateTable) parseEngine(s *lexer.Lexer) {
+	s.Expect("ENGINE")
+	s.Expect("=")
 
-	s.Scan()
 	eng := table.Engine(s.TokenText())
 	switch eng {
 		case table.InnoDB, table.MergeTree, table.SummingMergeTree, table.AggregatingMergeTree:
-		default: panic(errors.ErrInvalidEngine)
+		default: s.Errorf("%v: %v", errors.ErrInvalidEngine, s.Token())
 	}
 	qct.Engine = eng
 
 	s.Scan()
 }
 
-func (qct *QueryCreateTable) parsePrimaryKey(s *scanner.Scanner) {
-	if s.TokenText() != "PRIMARY" {
-		panic(errors.ErrSyntax)
-	}
-
-	s.Scan()
-	if s.TokenText() != "KEY" {
-		panic(errors.ErrSyntax)
-	}
-
-	s.Scan()
-	if s.TokenText() != "(" {
-		panic(errors.ErrSyntax)
-	}
-
-	isUniq := false
-	if qct.Engine == table.InnoDB {
-		isUniq = true
-	}
+func (qct *QueryCreateTable) parsePrimaryKey(s *lexer.Lexer) {
+	s.Expect("PRIMARY")
+	s.Expect("KEY")
 
 	pk := &QueryCreateTableIndex{
 		IndexOptions: &index.IndexOptions{
-			Columns: []string{},
+			Columns: parseIndexColumns(s),
 			Primary: true,
-			Uniq:    isUniq,
+			Uniq:    qct.Engine == table.InnoDB,
 		},
 	}
+	pk.Name = s.Ident()
 	qct.Indexes = append(qct.Indexes, pk)
-
-	for tok := s.Scan(); tok != scanner.EOF; tok = s.Scan() {
-		word := s.TokenText()
-		if _, isKW := kwords.KeyWords[word]; isKW {
-			panic(errors.ErrSyntax)
-		}
-
-		pk.Columns = append(pk.Columns, word)
-
-		s.Scan()
-		word = s.TokenText()
-		if word == ")" {
-			break
-		} else if word != "," {
-			panic(errors.ErrSyntax)
-		}
-	}
-
-	s.Scan()
-	pk.Name = s.TokenText()
-	if _, isKW := kwords.KeyWords[pk.Name]; isKW {
-		panic(errors.ErrSyntax)
-	}
-
-	s.Scan()
-	word := s.TokenText()
-	if word != "," && word != ";" {
-		panic(errors.ErrSyntax)
-	}
 }
 
-func (qct *QueryCreateTable) parseIndexes(s *scanner.Scanner) {
-	word := s.TokenText()
-	if word == ";" {
-		return
-	} else if word != "," {
-		panic(errors.ErrSyntax)
-	}
-
-	for tok := s.Scan(); tok != scanner.EOF; tok = s.Scan() {
-		if s.TokenText() != "INDEX" {
-			panic(errors.ErrSyntax)
-		}
-
+func (qct *QueryCreateTable) parseIndexes(s *lexer.Lexer) {
+	for s.Is(",") {
 		s.Scan()
-		if s.TokenText() != "(" {
-			panic(errors.ErrSyntax)
-		}
+		s.Expect("INDEX")
 
 		idx := &QueryCreateTableIndex{
 			IndexOptions: &index.IndexOptions{
-				Columns: []string{},
+				Columns: parseIndexColumns(s),
 			},
 		}
+		idx.Name = s.Ident()
 		qct.Indexes = append(qct.Indexes, idx)
-	
-		for tok := s.Scan(); tok != scanner.EOF; tok = s.Scan() {
-			word := s.TokenText()
-			if _, isKW := kwords.KeyWords[word]; isKW {
-				panic(errors.ErrSyntax)
-			}
-	
-			idx.Columns = append(idx.Columns, word)
-	
-			s.Scan()
-			word = s.TokenText()
-			if word == ")" {
-				break
-			} else if word != "," {
-				panic(errors.ErrSyntax)
-			}
-		}
-	
-		s.Scan()
-		idx.Name = s.TokenText()
-		if _, isKW := kwords.KeyWords[idx.Name]; isKW {
-			panic(errors.ErrSyntax)
-		}
-	
-		s.Scan()
-		word := s.TokenText()
-		if word == "UNIQUE" {
+
+		if s.Is("UNIQUE") {
 			idx.Uniq = true
 			s.Scan()
-			word = s.TokenText()
 		}
+	}
+}
 
-		if word != "," && word != ";" {
-			panic(errors.ErrSyntax)
-		}
+func parseIndexColumns(s *lexer.Lexer) []string {
+	s.Expect("(")
+
+	columns := []string{s.Ident()}
+	for s.Is(",") {
+		s.Scan()
+		columns = append(columns, s.Ident())
 	}
+
+	s.Expect(")")
+	return columns
 }
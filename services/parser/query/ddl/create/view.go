@@ -0,0 +1,57 @@
+package create
+
+import (
+	"go-dbms/services/parser/lexer"
+	"go-dbms/services/parser/query"
+	"go-dbms/services/parser/query/dml"
+	"go-dbms/util/helpers"
+)
+
+/*
+CREATE VIEW [IF NOT EXISTS] [<dbName>.]<viewName> AS <selectQuery>;
+*/
+type QueryCreateView struct {
+	*QueryCreate
+	DB          string        `json:"db"`
+	Name        string        `json:"name"`
+	IfNotExists bool          `json:"if_not_exists"`
+	Query       query.Querier `json:"query"`    // parsed query, which is validated on creation
+	Text        string        `json:"text"`     // text of query, which is stored in catalog
+	Database    string        `json:"database"` // database of unqualified table names of query
+}
+
+func (qv *QueryCreateView) Parse(s *lexer.Lexer, ps query.Parser) (err error) {
+	defer helpers.RecoverOnError(&err)()
+
+	qv.Target = VIEW
+
+	s.Expect("VIEW")
+	if s.Is("IF") {
+		s.Scan()
+		s.Expect("NOT")
+		s.Expect("EXISTS")
+		qv.IfNotExists = true
+	}
+	qv.DB, qv.Name = s.TableName()
+	s.Expect("AS")
+
+	start, tok, params := s.Pos(), s.Token(), len(s.Params())
+	if !s.Is("SELECT") {
+		s.Unexpected("SELECT")
+	}
+	if qv.Query, err = ps.ParseQuery(s); err != nil {
+		panic(err)
+	}
+
+	switch qv.Query.(type) {
+		case *dml.QuerySelect, *dml.QueryCompound:
+		default: s.ErrorAt(tok, "view must be SELECT query")
+	}
+	if len(s.Params()) != params {
+		s.ErrorAt(tok, "view can't have parameters")
+	}
+
+	qv.Text = s.Text(start)
+	qv.Database = s.Database()
+	return nil
+}
@@ -3,15 +3,22 @@ package ddl
 import (
 	"errors"
 	"fmt"
+	"go-dbms/services/parser/lexer"
 	"go-dbms/services/parser/query"
+	"go-dbms/services/parser/query/ddl/alter"
 	"go-dbms/services/parser/query/ddl/create"
-	"text/scanner"
+	"go-dbms/services/parser/query/ddl/drop"
+	"go-dbms/services/parser/query/ddl/rename"
+	"go-dbms/services/parser/query/ddl/truncate"
 )
 
-func Parse(s *scanner.Scanner, queryType query.QueryType) (query.Querier, error) {
+func Parse(s *lexer.Lexer, queryType query.QueryType, ps query.Parser) (query.Querier, error) {
 	switch queryType {
-		case query.CREATE: return create.Parse(s)
-		// case query.DROP:   return drop.Parse(data)
-		default:           return nil, errors.New(fmt.Sprintf("unsupported query type: '%s'", queryType))
+		case query.CREATE:   return create.Parse(s, ps)
+		case query.ALTER:    return alter.Parse(s, ps)
+		case query.DROP:     return drop.Parse(s)
+		case query.TRUNCATE: return truncate.Parse(s)
+		case query.RENAME:   return rename.Parse(s)
+		default:             return nil, errors.New(fmt.Sprintf("unsupported query type: '%s'", queryType))
 	}
 }
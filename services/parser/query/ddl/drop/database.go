@@ -1,12 +1,25 @@
 package drop
 
-import "text/scanner"
+import (
+	"go-dbms/services/parser/lexer"
+	"go-dbms/services/parser/query"
+	"go-dbms/util/helpers"
+)
 
+/*
+DROP DATABASE [IF EXISTS] <databaseName>;
+*/
 type QueryDropDatabase struct {
 	*QueryDrop
-	DB string `json:"db"`
+	DB       string `json:"db"`
+	IfExists bool   `json:"if_exists"`
 }
 
-func (qs *QueryDropDatabase) Parse(s *scanner.Scanner) (err error) {
+func (qd *QueryDropDatabase) Parse(s *lexer.Lexer, ps query.Parser) (err error) {
+	defer helpers.RecoverOnError(&err)()
+
+	s.Expect("DATABASE")
+	qd.IfExists = parseIfExists(s)
+	qd.DB = s.Ident()
 	return nil
 }
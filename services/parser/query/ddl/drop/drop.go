@@ -1,10 +1,11 @@
 package drop
 
 import (
-	"encoding/json"
-	"errors"
-	"fmt"
+	"strings"
+
+	"go-dbms/services/parser/lexer"
 	"go-dbms/services/parser/query"
+	"go-dbms/util/helpers"
 )
 
 type QueryDropTarget string
@@ -13,15 +14,16 @@ const (
 	DATABASE QueryDropTarget = "DATABASE"
 	TABLE    QueryDropTarget = "TABLE"
 	INDEX    QueryDropTarget = "INDEX"
+	VIEW     QueryDropTarget = "VIEW"
 )
 
 type Dropper interface {
-	query.Querier
+	query.QueryParser
 	GetTarget() QueryDropTarget
 }
 
 type QueryDrop struct {
-	query.Query
+	*query.Query
 	Target QueryDropTarget `json:"target"`
 }
 
@@ -29,19 +31,28 @@ func (qd *QueryDrop) GetTarget() QueryDropTarget {
 	return qd.Target
 }
 
-func Parse(data []byte) (Dropper, error) {
-	var q Dropper
-	dropQuery := &QueryDrop{}
-	if err := json.Unmarshal(data, dropQuery); err != nil {
-		return nil, err
+func Parse(s *lexer.Lexer) (q Dropper, err error) {
+	defer helpers.RecoverOnError(&err)()
+
+	s.Expect("DROP")
+	qd := &QueryDrop{Query: &query.Query{Type: query.DROP}, Target: QueryDropTarget(strings.ToUpper(s.TokenText()))}
+	switch qd.Target {
+		case DATABASE: q = &QueryDropDatabase{QueryDrop: qd}
+		case TABLE:    q = &QueryDropTable{QueryDrop: qd}
+		case INDEX:    q = &QueryDropIndex{QueryDrop: qd}
+		case VIEW:     q = &QueryDropView{QueryDrop: qd}
+		default:       s.Unexpected("DATABASE, TABLE, INDEX or VIEW")
 	}
 
-	switch dropQuery.Target {
-		case DATABASE: q = &QueryDropDatabase{}
-		case TABLE:    q = &QueryDropTable{}
-		case INDEX:    q = &QueryDropIndex{}
-		default:       return nil, errors.New(fmt.Sprintf("unsupported drop target: '%s'", dropQuery.Target))
-	}
+	return q, q.Parse(s, nil)
+}
 
-	return q, json.Unmarshal(data, &q)
+// parseIfExists parses optional IF EXISTS clause.
+func parseIfExists(s *lexer.Lexer) bool {
+	if !s.Is("IF") {
+		return false
+	}
+	s.Scan()
+	s.Expect("EXISTS")
+	return true
 }
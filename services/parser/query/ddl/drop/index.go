@@ -1,14 +1,27 @@
 package drop
 
-import "text/scanner"
+import (
+	"go-dbms/services/parser/lexer"
+	"go-dbms/services/parser/query"
+	"go-dbms/util/helpers"
+)
 
+/*
+DROP INDEX <indexName> ON [<dbName>.]<tableName>;
+*/
 type QueryDropIndex struct {
 	*QueryDrop
 	DB    string `json:"db"`
 	Table string `json:"table"`
-	Index string `josn:"index"`
+	Index string `json:"index"`
 }
 
-func (qs *QueryDropIndex) Parse(s *scanner.Scanner) (err error) {
+func (qd *QueryDropIndex) Parse(s *lexer.Lexer, ps query.Parser) (err error) {
+	defer helpers.RecoverOnError(&err)()
+
+	s.Expect("INDEX")
+	qd.Index = s.Ident()
+	s.Expect("ON")
+	qd.DB, qd.Table = s.TableName()
 	return nil
 }
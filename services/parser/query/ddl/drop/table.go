@@ -1,13 +1,26 @@
 package drop
 
-import "text/scanner"
+import (
+	"go-dbms/services/parser/lexer"
+	"go-dbms/services/parser/query"
+	"go-dbms/util/helpers"
+)
 
+/*
+DROP TABLE [IF EXISTS] [<dbName>.]<tableName>;
+*/
 type QueryDropTable struct {
 	*QueryDrop
-	DB    string `json:"db"`
-	Table string `json:"table"`
+	DB       string `json:"db"`
+	Table    string `json:"table"`
+	IfExists bool   `json:"if_exists"`
 }
 
-func (qs *QueryDropTable) Parse(s *scanner.Scanner) (err error) {
+func (qd *QueryDropTable) Parse(s *lexer.Lexer, ps query.Parser) (err error) {
+	defer helpers.RecoverOnError(&err)()
+
+	s.Expect("TABLE")
+	qd.IfExists = parseIfExists(s)
+	qd.DB, qd.Table = s.TableName()
 	return nil
 }
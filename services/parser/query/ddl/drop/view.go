@@ -0,0 +1,26 @@
+package drop
+
+import (
+	"go-dbms/services/parser/lexer"
+	"go-dbms/services/parser/query"
+	"go-dbms/util/helpers"
+)
+
+/*
+DROP VIEW [IF EXISTS] [<dbName>.]<viewName>;
+*/
+type QueryDropView struct {
+	*QueryDrop
+	DB       string `json:"db"`
+	Name     string `json:"name"`
+	IfExists bool   `json:"if_exists"`
+}
+
+func (qd *QueryDropView) Parse(s *lexer.Lexer, ps query.Parser) (err error) {
+	defer helpers.RecoverOnError(&err)()
+
+	s.Expect("VIEW")
+	qd.IfExists = parseIfExists(s)
+	qd.DB, qd.Name = s.TableName()
+	return nil
+}
@@ -0,0 +1,36 @@
+package rename
+
+import (
+	"go-dbms/services/parser/lexer"
+	"go-dbms/services/parser/query"
+	"go-dbms/util/helpers"
+)
+
+/*
+RENAME TABLE [<dbName>.]<tableName> TO [<dbName>.]<newTableName>;
+*/
+type QueryRenameTable struct {
+	*query.Query
+	DB       string `json:"db"`
+	Table    string `json:"table"`
+	NewDB    string `json:"new_db"`
+	NewTable string `json:"new_table"`
+}
+
+func Parse(s *lexer.Lexer) (q *QueryRenameTable, err error) {
+	defer helpers.RecoverOnError(&err)()
+
+	q = &QueryRenameTable{Query: &query.Query{Type: query.RENAME}}
+	return q, q.Parse(s, nil)
+}
+
+func (qr *QueryRenameTable) Parse(s *lexer.Lexer, ps query.Parser) (err error) {
+	defer helpers.RecoverOnError(&err)()
+
+	s.Expect("RENAME")
+	s.Expect("TABLE")
+	qr.DB, qr.Table = s.TableName()
+	s.Expect("TO")
+	qr.NewDB, qr.NewTable = s.TableName()
+	return nil
+}
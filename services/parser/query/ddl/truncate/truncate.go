@@ -0,0 +1,32 @@
+package truncate
+
+import (
+	"go-dbms/services/parser/lexer"
+	"go-dbms/services/parser/query"
+	"go-dbms/util/helpers"
+)
+
+/*
+TRUNCATE TABLE [<dbName>.]<tableName>;
+*/
+type QueryTruncateTable struct {
+	*query.Query
+	DB    string `json:"db"`
+	Table string `json:"table"`
+}
+
+func Parse(s *lexer.Lexer) (q *QueryTruncateTable, err error) {
+	defer helpers.RecoverOnError(&err)()
+
+	q = &QueryTruncateTable{Query: &query.Query{Type: query.TRUNCATE}}
+	return q, q.Parse(s, nil)
+}
+
+func (qt *QueryTruncateTable) Parse(s *lexer.Lexer, ps query.Parser) (err error) {
+	defer helpers.RecoverOnError(&err)()
+
+	s.Expect("TRUNCATE")
+	s.Expect("TABLE")
+	qt.DB, qt.Table = s.TableName()
+	return nil
+}
@@ -11,7 +11,9 @@ type AggregationANYLAST struct {
 }
 
 func (as *AggregationANYLAST) Apply(row types.DataRow) {
-	as.Val = eval.Eval(row, as.Arguments[0])
+	if val := eval.Eval(row, as.Arguments[0]); val != nil {
+		as.Val = val
+	}
 }
 
 func (as *AggregationANYLAST) Value() types.DataType {
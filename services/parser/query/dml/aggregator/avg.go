@@ -14,7 +14,12 @@ type AggregationAVG struct {
 }
 
 func (as *AggregationAVG) Apply(row types.DataRow) {
-	val, err := eval.Eval(row, as.Arguments[0]).Cast(float64Meta)
+	val := eval.Eval(row, as.Arguments[0])
+	if val == nil {
+		return
+	}
+
+	val, err := val.Cast(float64Meta)
 	if err != nil {
 		panic(err)
 	}
@@ -23,9 +28,8 @@ func (as *AggregationAVG) Apply(row types.DataRow) {
 }
 
 func (as *AggregationAVG) Value() types.DataType {
-	var val float64
-	if as.Count != 0 {
-		val = float64(as.Sum) / float64(as.Count)
+	if as.Count == 0 {
+		return nil
 	}
-	return types.Type(float64Meta).Set(val)
+	return types.Type(float64Meta).Set(float64(as.Sum) / float64(as.Count))
 }
@@ -1,6 +1,9 @@
 package aggregator
 
-import "go-dbms/pkg/types"
+import (
+	"go-dbms/pkg/types"
+	"go-dbms/services/parser/query/dml/eval"
+)
 
 type AggregationCOUNT struct {
 	*AggregatorBase
@@ -8,6 +11,9 @@ type AggregationCOUNT struct {
 }
 
 func (as *AggregationCOUNT) Apply(row types.DataRow) {
+	if len(as.Arguments) != 0 && eval.Eval(row, as.Arguments[0]) == nil {
+		return
+	}
 	as.Val++
 }
 
@@ -0,0 +1,75 @@
+package aggregator
+
+import (
+	"fmt"
+
+	"go-dbms/pkg/types"
+	"go-dbms/pkg/types/hashed"
+	"go-dbms/services/parser/query/dml/eval"
+	"go-dbms/services/parser/query/dml/projection"
+)
+
+// AggregationDISTINCT applies wrapped aggregator only on
+// distinct combinations of argument values.
+type AggregationDISTINCT struct {
+	*AggregatorBase
+	aggr Aggregator
+	set  *hashed.Set
+}
+
+func NewDistinct(name AggregatorType, args []*projection.Projection) Aggregator {
+	argsCp := make([]*projection.Projection, 0, len(args))
+	for i := range args {
+		alias := fmt.Sprintf("#arg%d", i)
+		argsCp = append(argsCp, &projection.Projection{
+			Alias: alias,
+			Name:  alias,
+			Type:  projection.IDENTIFIER,
+		})
+	}
+
+	return &AggregationDISTINCT{
+		AggregatorBase: &AggregatorBase{args},
+		aggr:           New(name, argsCp),
+		set:            hashed.NewSet(hashed.DefaultMemLimit),
+	}
+}
+
+func (ad *AggregationDISTINCT) Apply(row types.DataRow) {
+	vals := make([]types.DataType, 0, len(ad.Arguments))
+	argRow := make(types.DataRow, len(ad.Arguments))
+	for i, arg := range ad.Arguments {
+		val := eval.Eval(row, arg)
+		vals = append(vals, val)
+		argRow[fmt.Sprintf("#arg%d", i)] = val
+	}
+
+	r, err := ad.set.Add(hashed.Key(vals...), argRow)
+	if err != nil {
+		panic(err)
+	} else if r != nil {
+		ad.aggr.Apply(r)
+	}
+}
+
+func (ad *AggregationDISTINCT) Value() types.DataType {
+	if ad.set != nil {
+		err := ad.set.Flush(func(row types.DataRow) bool {
+			ad.aggr.Apply(row)
+			return false
+		})
+		if err != nil {
+			panic(err)
+		}
+		ad.Close()
+	}
+	return ad.aggr.Value()
+}
+
+// Close removes temporary files of not flushed aggregation.
+func (ad *AggregationDISTINCT) Close() {
+	if ad.set != nil {
+		ad.set.Close()
+		ad.set = nil
+	}
+}
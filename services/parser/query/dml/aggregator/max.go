@@ -12,7 +12,7 @@ type AggregationMAX struct {
 
 func (as *AggregationMAX) Apply(row types.DataRow) {
 	val := eval.Eval(row, as.Arguments[0])
-	if as.Val == nil || val.CompareOp(types.Greater, as.Val) {
+	if val != nil && (as.Val == nil || val.CompareOp(types.Greater, as.Val)) {
 		as.Val = val
 	}
 }
@@ -13,7 +13,9 @@ type AggregationSUM struct {
 
 func (as *AggregationSUM) Apply(row types.DataRow) {
 	val := eval.Eval(row, as.Arguments[0])
-	if as.Sum == nil {
+	if val == nil {
+		return
+	} else if as.Sum == nil {
 		as.Sum = val
 	} else {
 		as.Sum = function.Eval(function.ADD, row, []types.DataType{as.Sum, val})
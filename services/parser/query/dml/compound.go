@@ -0,0 +1,102 @@
+package dml
+
+import (
+	"go-dbms/services/parser/lexer"
+	"go-dbms/services/parser/query"
+	"go-dbms/services/parser/query/dml/order"
+	"go-dbms/services/parser/query/dml/projection"
+	"go-dbms/util/helpers"
+)
+
+type SetOperation string
+
+const (
+	UNION     SetOperation = "UNION"
+	INTERSECT SetOperation = "INTERSECT"
+	EXCEPT    SetOperation = "EXCEPT"
+)
+
+/*
+<select> {UNION [ALL] | INTERSECT | EXCEPT} <select> ...
+[ORDER BY <projection> [ASC | DESC], ...]
+[LIMIT <count> [OFFSET <offset>]];
+
+INTERSECT has higher precedence than UNION and EXCEPT. ORDER BY and LIMIT
+of the last select are applied to the whole result.
+*/
+type QueryCompound struct {
+	query.Query
+	Left, Right query.Querier
+	Op          SetOperation
+	All         bool
+	OrderBy     []*order.Item
+	Limit       *Limit
+}
+
+// Projections returns projections of query result. For compound
+// queries these are projections of the leftmost select.
+func Projections(q query.Querier) *projection.Projections {
+	switch q := q.(type) {
+		case *QuerySelect:   return q.Projections
+		case *QueryCompound: return Projections(q.Left)
+	}
+	return nil
+}
+
+func parseCompound(s *lexer.Lexer, first *QuerySelect, ps query.Parser) (q query.Querier, err error) {
+	defer helpers.RecoverOnError(&err)()
+
+	operands := []query.Querier{first}
+	ops := []*QueryCompound{}
+	last := first
+
+	for {
+		if !s.Is(string(UNION), string(INTERSECT), string(EXCEPT)) {
+			break
+		}
+
+		qc := &QueryCompound{Op: SetOperation(s.TokenText())}
+		qc.Type = query.COMPOUND
+		if s.Scan(); qc.Op == UNION && s.Is("ALL") {
+			qc.All = true
+			s.Scan()
+		}
+
+		last = &QuerySelect{}
+		if err := last.Parse(s, ps); err != nil {
+			panic(err)
+		}
+
+		operands = append(operands, last)
+		ops = append(ops, qc)
+	}
+
+	if len(ops) == 0 {
+		return first, nil
+	}
+
+	// INTERSECT is evaluated first
+	for i := 0; i < len(ops); {
+		if ops[i].Op != INTERSECT {
+			i++
+			continue
+		}
+
+		ops[i].Left, ops[i].Right = operands[i], operands[i+1]
+		operands[i] = ops[i]
+		operands = append(operands[:i+1], operands[i+2:]...)
+		ops = append(ops[:i], ops[i+1:]...)
+	}
+
+	q = operands[0]
+	for i, qc := range ops {
+		qc.Left, qc.Right = q, operands[i+1]
+		q = qc
+	}
+
+	if qc, ok := q.(*QueryCompound); ok {
+		qc.OrderBy, qc.Limit = last.OrderBy, last.Limit
+		last.OrderBy, last.Limit = nil, nil
+	}
+	return q, nil
+}
@@ -1,19 +1,19 @@
 package dml
 
 import (
-	"text/scanner"
-
 	"go-dbms/pkg/statement"
-	"go-dbms/services/parser/errors"
-	"go-dbms/services/parser/kwords"
+	"go-dbms/services/parser/lexer"
 	"go-dbms/services/parser/query"
+	"go-dbms/services/parser/query/dml/projection"
 	"go-dbms/util/helpers"
 )
 
 /*
-DELETE FROM <tableName>
-[WHERE_INDEX <indexName> <condition> [AND <condition>]]
-[WHERE <...condition>];
+DELETE FROM [<dbName>.]<tableName>
+[USE_INDEX <indexName>]
+[WHERE_INDEX (<condition> [AND <condition>]) [AND (<condition> [AND <condition>])]]
+[WHERE <...condition>]
+[RETURNING <...projection>];
 */
 type QueryDelete struct {
 	query.Query
@@ -22,9 +22,10 @@ type QueryDelete struct {
 	UseIndex   string
 	Where      *statement.WhereStatement
 	WhereIndex *WhereIndex
+	Returning  *projection.Projections // projections of deleted rows, primary key if nil
 }
 
-func (qd *QueryDelete) Parse(s *scanner.Scanner, ps query.Parser) (err error) {
+func (qd *QueryDelete) Parse(s *lexer.Lexer, ps query.Parser) (err error) {
 	defer helpers.RecoverOnError(&err)()
 
 	qd.Type = query.DELETE
@@ -33,59 +34,29 @@ func (qd *QueryDelete) Parse(s *scanner.Scanner, ps query.Parser) (err error) {
 	qd.parseUseIndex(s)
 	qd.parseWhereIndex(s, ps)
 	qd.parseWhere(s, ps)
+	qd.parseReturning(s, ps)
 
 	return nil
 }
 
-func (qd *QueryDelete) parseFrom(s *scanner.Scanner) {
-	s.Scan()
-	if s.TokenText() != "FROM" {
-		panic(errors.ErrSyntax)
-	}
-
-	tok := s.Scan()
-	word := s.TokenText()
-	_, isKW := kwords.KeyWords[word]
-	if tok == scanner.EOF {
-		panic(errors.ErrSyntax)
-	} else if isKW {
-		panic(errors.ErrNoFrom)
-	}
-
-	qd.Table = word
-
-	tok = s.Scan()
-	word = s.TokenText()
-	_, idKW := kwords.KeyWords[word]
-	if tok != scanner.EOF && !idKW {
-		panic(errors.ErrSyntax)
-	}
+func (qd *QueryDelete) parseFrom(s *lexer.Lexer) {
+	s.Expect("DELETE")
+	s.Expect("FROM")
+	qd.DB, qd.Table = s.TableName()
 }
 
-func (qs *QueryDelete) parseUseIndex(s *scanner.Scanner) {
-	word := s.TokenText()
-	if word != "USE_INDEX" {
-		return
-	}
-
-	tok := s.Scan()
-	if tok == scanner.EOF {
-		panic(errors.ErrSyntax)
-	}
-
-	qs.UseIndex = s.TokenText()
-	s.Scan()
+func (qd *QueryDelete) parseUseIndex(s *lexer.Lexer) {
+	qd.UseIndex = parseUseIndex(s)
 }
 
-func (qs *QueryDelete) parseWhereIndex(s *scanner.Scanner, ps query.Parser) {
-	qs.WhereIndex = parseWhereIndex(s, ps)
+func (qd *QueryDelete) parseWhereIndex(s *lexer.Lexer, ps query.Parser) {
+	qd.WhereIndex = parseWhereIndex(s, ps)
 }
 
-func (qd *QueryDelete) parseWhere(s *scanner.Scanner, ps query.Parser) {
-	word := s.TokenText()
-	if word != "WHERE" {
-		return
-	}
-
+func (qd *QueryDelete) parseWhere(s *lexer.Lexer, ps query.Parser) {
 	qd.Where = parseWhere(s, ps)
 }
+
+func (qd *QueryDelete) parseReturning(s *lexer.Lexer, ps query.Parser) {
+	qd.Returning = parseReturning(s, ps)
+}
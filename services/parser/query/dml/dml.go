@@ -3,28 +3,59 @@ package dml
 import (
 	"errors"
 	"fmt"
-	"text/scanner"
 
 	"go-dbms/pkg/index"
+	"go-dbms/services/parser/lexer"
 	"go-dbms/services/parser/query"
+	"go-dbms/services/parser/query/dml/projection"
 )
 
-func Parse(s *scanner.Scanner, queryType query.QueryType, ps query.Parser) (query.Querier, error) {
+func Parse(s *lexer.Lexer, queryType query.QueryType, ps query.Parser) (query.Querier, error) {
 	var q query.QueryParser
 
 	switch queryType {
 		case query.DELETE:  q = &QueryDelete{}
 		case query.INSERT:  q = &QueryInsert{}
+		case query.REPLACE: q = &QueryInsert{}
 		case query.SELECT:  q = &QuerySelect{}
 		case query.UPDATE:  q = &QueryUpdate{}
 		case query.PREPARE: q = &QueryPrepare{}
+		case query.EXPLAIN: q = &QueryExplain{}
 		default:            return nil, errors.New(fmt.Sprintf("unsupported query type: '%s'", queryType))
 	}
 
-	return q, q.Parse(s, ps)
+	if err := q.Parse(s, ps); err != nil {
+		return nil, err
+	} else if qs, ok := q.(*QuerySelect); ok {
+		return parseCompound(s, qs, ps)
+	}
+	return q, nil
 }
 
 type WhereIndex struct {
 	FilterStart *index.Filter
 	FilterEnd   *index.Filter
 }
+
+// parseReturning parses projections of rows returned by INSERT, UPDATE and DELETE.
+func parseReturning(s *lexer.Lexer, ps query.Parser) *projection.Projections {
+	if !s.Is("RETURNING") {
+		return nil
+	}
+	s.Scan()
+
+	prs := projection.New()
+	for {
+		tok := s.Token()
+		p := parseProjection(s, ps)
+		if !isRowExpr(p) {
+			s.ErrorAt(tok, "returned expression can't contain aggregators or subqueries")
+		}
+		prs.Add(p)
+
+		if !s.Is(",") {
+			return prs
+		}
+		s.Scan()
+	}
+}
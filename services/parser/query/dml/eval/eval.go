@@ -9,16 +9,35 @@ import (
 
 func Eval(row types.DataRow, p *projection.Projection) types.DataType {
 	switch p.Type {
-		case projection.LITERAL:
+		case projection.LITERAL, projection.SUBQUERY: // value of subquery is set before evaluation
 			return p.Literal
 		case projection.IDENTIFIER:
 			return row[p.Name]
+		case projection.AGGREGATOR:
+			if val, ok := row[p.Alias]; ok { // already aggregated value
+				return val
+			}
 		case projection.FUNCTION:
+			nullSafe := function.IsNullSafe(p.Name)
 			argVals := make([]types.DataType, 0, len(p.Arguments))
 			for _, arg := range p.Arguments {
-				argVals = append(argVals, Eval(row, arg))
+				val := Eval(row, arg)
+				if val == nil && !nullSafe {
+					return nil // function of NULL is NULL
+				}
+				argVals = append(argVals, val)
 			}
 			return function.Eval(function.FunctionType(p.Name), row, argVals)
+		case projection.CASE:
+			for i, cond := range p.Conditions {
+				if cond.Compare(row) {
+					return Eval(row, p.Arguments[i])
+				}
+			}
+			if len(p.Arguments) > len(p.Conditions) {
+				return Eval(row, p.Arguments[len(p.Conditions)])
+			}
+			return nil
 	}
 
 	panic(fmt.Errorf("invalid projection:'%v'", p.Type))
@@ -0,0 +1,40 @@
+package dml
+
+import (
+	"go-dbms/services/parser/lexer"
+	"go-dbms/services/parser/query"
+	"go-dbms/util/helpers"
+)
+
+/*
+EXPLAIN [ANALYZE] <select | update | delete>;
+
+EXPLAIN returns plan of query without executing it, EXPLAIN ANALYZE
+executes query and adds count of rows and elapsed time of every operator.
+*/
+type QueryExplain struct {
+	query.Query
+	Analyze bool
+	Target  query.Querier
+}
+
+func (qe *QueryExplain) Parse(s *lexer.Lexer, ps query.Parser) (err error) {
+	defer helpers.RecoverOnError(&err)()
+
+	qe.Type = query.EXPLAIN
+
+	s.Expect("EXPLAIN")
+	if s.Is("ANALYZE") {
+		qe.Analyze = true
+		s.Scan()
+	}
+
+	if !s.Is(string(query.SELECT), string(query.UPDATE), string(query.DELETE)) {
+		s.Unexpected("SELECT, UPDATE or DELETE")
+	}
+	if qe.Target, err = ps.ParseQuery(s); err != nil {
+		panic(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,19 @@
+package function
+
+import (
+	"go-dbms/pkg/types"
+)
+
+const COALESCE FunctionType = "COALESCE"
+
+func init() {
+	nullSafe[COALESCE] = struct{}{}
+	functions[COALESCE] = func(row types.DataRow, args []types.DataType) types.DataType {
+		for _, arg := range args {
+			if arg != nil {
+				return arg
+			}
+		}
+		return nil
+	}
+}
@@ -19,7 +19,7 @@ func init() {
 		for _, arg := range args {
 			switch arg.GetCode() {
 				case types.TYPE_STRING:  buf.WriteString(arg.Value().(string))
-				case types.TYPE_VARCHAR: buf.Write(arg.Value().([]byte))
+				case types.TYPE_VARCHAR: buf.Write(arg.(*types.DataTypeVARCHAR).Bytes())
 				default:                 buf.WriteString(helpers.MustVal(arg.Cast(types.Meta(types.TYPE_STRING))).Value().(string))
 			}
 		}
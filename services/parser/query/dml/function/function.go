@@ -20,11 +20,20 @@ type Function func(row types.DataRow, args []types.DataType) types.DataType
 
 var functions = map[FunctionType]Function{}
 
+// nullSafe functions handle NULL arguments by themselves,
+// other functions return NULL if any argument is NULL
+var nullSafe = map[FunctionType]struct{}{}
+
 func IsFunction(fn string) bool {
 	_, ok := functions[FunctionType(fn)]
 	return ok
 }
 
+func IsNullSafe(fn string) bool {
+	_, ok := nullSafe[FunctionType(fn)]
+	return ok
+}
+
 func Eval(name FunctionType, row types.DataRow, args []types.DataType) types.DataType {
 	return functions[name](row, args)
 }
@@ -0,0 +1,19 @@
+package function
+
+import (
+	"go-dbms/pkg/types"
+)
+
+const LEAST FunctionType = "LEAST"
+
+func init() {
+	functions[LEAST] = func(row types.DataRow, args []types.DataType) types.DataType {
+		res := args[0]
+		for _, arg := range args[1:] {
+			if compare(res, arg) > 0 {
+				res = arg
+			}
+		}
+		return res
+	}
+}
@@ -8,12 +8,12 @@ const MUL FunctionType = "MUL"
 
 func init() {
 	functions[MUL] = func(row types.DataRow, args []types.DataType) types.DataType {
-		var valInt intType
+		var valInt intType = 1
 		var valFloat floatType
 		isInt := true
 
 		for _, arg := range args {
-			if !isInt && arg.GetCode() == types.TYPE_FLOAT {
+			if isInt && arg.GetCode() == types.TYPE_FLOAT {
 				valFloat = float64(valInt)
 				isInt = false
 			}
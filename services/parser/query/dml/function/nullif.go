@@ -0,0 +1,20 @@
+package function
+
+import (
+	"go-dbms/pkg/types"
+	"go-dbms/util/helpers"
+)
+
+const NULLIF FunctionType = "NULLIF"
+
+func init() {
+	nullSafe[NULLIF] = struct{}{}
+	functions[NULLIF] = func(row types.DataRow, args []types.DataType) types.DataType {
+		if args[0] == nil || args[1] == nil {
+			return args[0]
+		} else if args[0].Compare(helpers.MustVal(args[1].Cast(args[0].MetaCopy()))) == 0 {
+			return nil
+		}
+		return args[0]
+	}
+}
@@ -1,6 +1,9 @@
 package function
 
-import "go-dbms/pkg/types"
+import (
+	"go-dbms/pkg/types"
+	"go-dbms/util/helpers"
+)
 
 type intType = int64
 var (
@@ -11,3 +14,15 @@ type floatType = float64
 var (
 	floatMeta = &types.DataTypeFLOATMeta{ByteSize: 8}
 )
+
+// compare compares values in common type, numbers of
+// different types are compared as widest of them.
+func compare(a, b types.DataType) int {
+	var meta types.DataTypeMeta
+	switch {
+		case a.GetCode() == types.TYPE_FLOAT || b.GetCode() == types.TYPE_FLOAT: meta = floatMeta
+		case a.GetCode() == types.TYPE_INTEGER && b.GetCode() == types.TYPE_INTEGER: meta = intMeta
+		default: meta = a.MetaCopy()
+	}
+	return helpers.MustVal(a.Cast(meta)).Compare(helpers.MustVal(b.Cast(meta)))
+}
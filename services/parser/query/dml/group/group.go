@@ -1,73 +1,73 @@
 package group
 
 import (
+	"go-dbms/pkg/statement"
 	"go-dbms/pkg/types"
+	"go-dbms/pkg/types/hashed"
 	"go-dbms/services/parser/query/dml/aggregator"
+	"go-dbms/services/parser/query/dml/eval"
 	"go-dbms/services/parser/query/dml/projection"
 	"go-dbms/util/stream"
 )
 
 type subGroup struct {
-	next       map[string]*subGroup
 	val        map[string]aggregator.Aggregator
 	groupItems types.DataRow
 }
 
 type Group struct {
 	projections *projection.Projections
-	groupList   map[string]struct{}
-	groups      *subGroup
-	dst         stream.Writer[types.DataRow]
+	keys        []*projection.Projection
+	having      *statement.WhereStatement
+	groups      map[string]*subGroup
+	dst         stream.WriterContinue[types.DataRow]
 }
 
-func New(projections *projection.Projections, groupList map[string]struct{}, dst stream.Writer[types.DataRow]) *Group {
+// New creates group, rows are grouped by values of keys expressions.
+func New(
+	projections *projection.Projections,
+	keys []*projection.Projection,
+	having *statement.WhereStatement,
+	dst stream.WriterContinue[types.DataRow],
+) *Group {
 	return &Group{
 		projections: projections,
-		groupList:   groupList,
-		groups:      &subGroup{},
+		keys:        keys,
+		having:      having,
+		groups:      map[string]*subGroup{},
 		dst:         dst,
 	}
 }
 
 func (g *Group) Add(row types.DataRow) {
-	gr := g.groups
-	groupItems := gr.groupItems
-	for gIdx := range g.projections.NonAggregators() {
-		gi := g.projections.GetByIndex(gIdx).Alias
-		if gr.next == nil {
-			gr.next = map[string]*subGroup{}
-		}
-
-		key := string(row[gi].Bytes())
-		if next, ok := gr.next[key]; !ok {
-			if groupItems == nil {
-				groupItems = types.DataRow{gi: row[gi]}
-			} else {
-				groupItems[gi] = row[gi]
-			}
-			sg := &subGroup{}
-			gr.next[key] = sg
-			gr = sg
+	vals := make([]types.DataType, len(g.keys))
+	for i, k := range g.keys {
+		vals[i] = eval.Eval(row, k)
+	}
 
-			cp := types.DataRow{}
-			for k, v := range groupItems {
-				cp[k] = v
-			}
-			sg.groupItems = cp
-		} else {
-			gr = next
-			groupItems = next.groupItems
+	key := hashed.Key(vals...) // NULL values are grouped together
+	gr, ok := g.groups[key]
+	if !ok {
+		gr = &subGroup{
+			val:        map[string]aggregator.Aggregator{},
+			groupItems: types.DataRow{},
 		}
-	}
-	if gr.val == nil {
-		gr.val = map[string]aggregator.Aggregator{}
+		for _, i := range g.projections.NonAggregators() {
+			alias := g.projections.GetByIndex(i).Alias
+			gr.groupItems[alias] = row[alias]
+		}
+		g.groups[key] = gr
 	}
 
 	for _, i := range g.projections.Aggregators() {
 		p := g.projections.GetByIndex(i)
 		var aggr aggregator.Aggregator
 		if ag, ok := gr.val[p.Alias]; !ok {
-			aggr = aggregator.New(aggregator.AggregatorType(p.Name), p.Arguments)
+			if p.Distinct {
+				aggr = aggregator.NewDistinct(aggregator.AggregatorType(p.Name), p.Arguments)
+			} else {
+				aggr = aggregator.New(aggregator.AggregatorType(p.Name), p.Arguments)
+			}
 			gr.val[p.Alias] = aggr
 		} else {
 			aggr = ag
@@ -77,39 +77,42 @@ func (g *Group) Add(row types.DataRow) {
 	}
 }
 
+// Flush pushes aggregated groups matching having condition to dst.
+// Stops if dst doesn't want to continue.
 func (g *Group) Flush() (n int, err error) {
-	return g.flush(g.groups)
-}
-
-func (g *Group) flush(gr *subGroup) (n int, err error) {
-	if gr.next != nil {
-		for _, sg := range gr.next {
-			sN, sErr := g.flush(sg)
-			n += sN
-			if sErr != nil {
-				return 0, sErr
+	prList := g.projections.Iterator()
+	for _, gr := range g.groups {
+		record := make(types.DataRow, len(prList))
+		for _, pr := range prList {
+			var val types.DataType
+			if pr.Type == projection.AGGREGATOR {
+				val = gr.val[pr.Alias].Value()
+			} else {
+				val = gr.groupItems[pr.Alias]
 			}
+			record[pr.Alias] = val
 		}
-		return n, err
-	}
 
-	if len(gr.val) == 0 {
-		return 0, nil
-	}
-
-	prList := g.projections.Iterator()
-	record := make(types.DataRow, len(prList))
+		if g.having != nil && !g.having.Compare(record) {
+			continue
+		}
 
-	for _, pr := range prList {
-		var val types.DataType
-		if pr.Type == projection.AGGREGATOR {
-			val = gr.val[pr.Alias].Value()
-		} else {
-			val = gr.groupItems[pr.Alias]
+		g.dst.Push(record)
+		n++
+		if !g.dst.ShouldContinue() {
+			break
 		}
-		record[pr.Alias] = val
 	}
+	return n, nil
+}
 
-	g.dst.Push(record)
-	return 0, nil
+// Close releases resources of aggregators, which were not flushed.
+func (g *Group) Close() {
+	for _, gr := range g.groups {
+		for _, aggr := range gr.val {
+			if c, ok := aggr.(interface{ Close() }); ok {
+				c.Close()
+			}
+		}
+	}
 }
@@ -0,0 +1,88 @@
+package group
+
+import (
+	"slices"
+	"testing"
+
+	"go-dbms/pkg/types"
+	"go-dbms/services/parser/query/dml/aggregator"
+	"go-dbms/services/parser/query/dml/function"
+	"go-dbms/services/parser/query/dml/projection"
+	"go-dbms/util/stream"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroupByNonFirstColumn(t *testing.T) {
+	// grouped column is not first projection, so its index differs from its position among non aggregators
+	prs := projection.New()
+	prs.Add(&projection.Projection{
+		Alias:     "cnt",
+		Name:      string(aggregator.COUNT),
+		Type:      projection.AGGREGATOR,
+		Arguments: []*projection.Projection{{Alias: "id", Name: "id", Type: projection.IDENTIFIER}},
+	})
+	prs.Add(&projection.Projection{Alias: "name", Name: "name", Type: projection.IDENTIFIER})
+
+	dst := stream.New[types.DataRow](2)
+	dst.AutoContinue(true)
+	g := New(prs, []*projection.Projection{{Alias: "name", Name: "name", Type: projection.IDENTIFIER}}, nil, dst)
+
+	for i, name := range []string{"a", "b", "a"} {
+		g.Add(types.DataRow{
+			"id":   types.Type(types.Meta(types.TYPE_INTEGER, true, 4, false)).Set(int32(i)),
+			"name": types.Type(types.Meta(types.TYPE_STRING)).Set(name),
+		})
+	}
+
+	n, err := g.Flush()
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+	dst.Close()
+
+	counts := map[string]uint64{}
+	for _, row := range dst.Slice() {
+		counts[row["name"].Value().(string)] = row["cnt"].Value().(uint64)
+	}
+	require.Equal(t, map[string]uint64{"a": 2, "b": 1}, counts)
+}
+
+func TestGroupByExpression(t *testing.T) {
+	// rows are grouped by expression, which is not selected
+	prs := projection.New()
+	prs.Add(&projection.Projection{
+		Alias:     "cnt",
+		Name:      string(aggregator.COUNT),
+		Type:      projection.AGGREGATOR,
+		Arguments: []*projection.Projection{{Alias: "id", Name: "id", Type: projection.IDENTIFIER}},
+	})
+
+	dst := stream.New[types.DataRow](4)
+	dst.AutoContinue(true)
+	key := &projection.Projection{
+		Alias: "id%2",
+		Name:  string(function.RES),
+		Type:  projection.FUNCTION,
+		Arguments: []*projection.Projection{
+			{Alias: "id", Name: "id", Type: projection.IDENTIFIER},
+			{Alias: "2", Type: projection.LITERAL, Literal: types.Type(types.Meta(types.TYPE_INTEGER, true, 4, false)).Set(int32(2))},
+		},
+	}
+	g := New(prs, []*projection.Projection{key}, nil, dst)
+
+	for i := range 5 {
+		g.Add(types.DataRow{"id": types.Type(types.Meta(types.TYPE_INTEGER, true, 4, false)).Set(int32(i))})
+	}
+
+	n, err := g.Flush()
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+	dst.Close()
+
+	counts := []uint64{}
+	for _, row := range dst.Slice() {
+		counts = append(counts, row["cnt"].Value().(uint64))
+	}
+	slices.Sort(counts)
+	require.Equal(t, []uint64{2, 3}, counts)
+}
@@ -1,131 +1,129 @@
 package dml
 
 import (
-	"encoding/json"
-	"text/scanner"
-
-	"go-dbms/pkg/types"
-	"go-dbms/services/parser/errors"
-	"go-dbms/services/parser/kwords"
+	"go-dbms/pkg/table"
+	"go-dbms/services/parser/lexer"
 	"go-dbms/services/parser/query"
+	"go-dbms/services/parser/query/dml/projection"
 	"go-dbms/util/helpers"
 )
 
 /*
-INSERT INTO <tableName> (...columns)
+{INSERT [IGNORE] | REPLACE} INTO [<dbName>.]<tableName> (...columns)
 VALUES
 	(...values)
 	...
-	(...values);
+	(...values)
+[ON DUPLICATE KEY UPDATE <columnName> = <expression>, ...]
+[RETURNING <...projection>];
+
+{INSERT [IGNORE] | REPLACE} INTO [<dbName>.]<tableName> (...columns) <select>
+[ON DUPLICATE KEY UPDATE <columnName> = <expression>, ...]
+[RETURNING <...projection>];
+
+ON DUPLICATE KEY UPDATE can't be used with IGNORE or REPLACE.
 */
 type QueryInsert struct {
 	query.Query
-	DB      string
-	Table   string
-	Columns []string
-	Values  [][]types.DataType
+	DB         string
+	Table      string
+	Columns    []string
+	Values     [][]*projection.Projection // constant expressions, evaluated on execution
+	Select     query.Querier              // query, which rows are inserted instead of values
+	OnConflict table.ConflictAction
+	Update     map[string]*projection.Projection // new values of conflicting row on CONFLICT_UPDATE, evaluated on it
+	Returning  *projection.Projections           // projections of inserted rows, primary key if nil
 }
 
-func (qi *QueryInsert) Parse(s *scanner.Scanner, ps query.Parser) (err error) {
+func (qi *QueryInsert) Parse(s *lexer.Lexer, ps query.Parser) (err error) {
 	defer helpers.RecoverOnError(&err)()
 
 	qi.Type = query.INSERT
 
 	qi.parseInto(s)
 	qi.parseColumns(s)
-	qi.parseValues(s)
+	if s.Is(string(query.SELECT)) {
+		qi.parseSelect(s, ps)
+	} else {
+		qi.parseValues(s, ps)
+	}
+	qi.parseOnDuplicate(s, ps)
+	qi.parseReturning(s, ps)
 
 	return nil
 }
 
-func (qi *QueryInsert) parseInto(s *scanner.Scanner) {
-	s.Scan()
-	if s.TokenText() != "INTO" {
-		panic(errors.ErrSyntax)
-	}
-
-	tok := s.Scan()
-	word := s.TokenText()
-	_, isKW := kwords.KeyWords[word]
-	if tok == scanner.EOF || isKW {
-		panic(errors.ErrSyntax)
-	}
-
-	qi.Table = word
-
-	tok = s.Scan()
-	word = s.TokenText()
-	if tok != scanner.EOF && word != "(" {
-		panic(errors.ErrSyntax)
+func (qi *QueryInsert) parseInto(s *lexer.Lexer) {
+	if s.Expect(string(query.INSERT), string(query.REPLACE)) == string(query.REPLACE) {
+		qi.OnConflict = table.CONFLICT_REPLACE
+	} else if s.Is("IGNORE") {
+		qi.OnConflict = table.CONFLICT_IGNORE
+		s.Scan()
 	}
+	s.Expect("INTO")
+	qi.DB, qi.Table = s.TableName()
 }
 
-func (qi *QueryInsert) parseColumns(s *scanner.Scanner) {
-	for tok := s.Scan(); tok != scanner.EOF; tok = s.Scan() {
-		word := s.TokenText()
-		_, isKW := kwords.KeyWords[word]
-		if tok == scanner.EOF || isKW {
-			panic(errors.ErrSyntax)
-		}
-
-		qi.Columns = append(qi.Columns, word)
-
-		tok = s.Scan()
-		word = s.TokenText()
-		_, isKW = kwords.KeyWords[word]
-		if tok == scanner.EOF || (word != "," && word != ")") || isKW {
-			panic(errors.ErrSyntax)
-		} else if word == ")" {
+func (qi *QueryInsert) parseColumns(s *lexer.Lexer) {
+	s.Expect("(")
+	for {
+		qi.Columns = append(qi.Columns, s.Ident())
+		if s.Expect(",", ")") == ")" {
 			return
 		}
 	}
 }
 
-func (qi *QueryInsert) parseValues(s *scanner.Scanner) {
-	qi.Values = [][]types.DataType{}
+func (qi *QueryInsert) parseOnDuplicate(s *lexer.Lexer, ps query.Parser) {
+	if qi.OnConflict != table.CONFLICT_FAIL || !s.Is("ON") {
+		return
+	}
 
 	s.Scan()
-	if s.TokenText() != "VALUES" {
-		panic(errors.ErrSyntax)
+	s.Expect("DUPLICATE")
+	s.Expect("KEY")
+	s.Expect("UPDATE")
+	qi.OnConflict = table.CONFLICT_UPDATE
+	qi.Update = parseSet(s, ps)
+}
+
+func (qi *QueryInsert) parseReturning(s *lexer.Lexer, ps query.Parser) {
+	qi.Returning = parseReturning(s, ps)
+}
+
+func (qi *QueryInsert) parseSelect(s *lexer.Lexer, ps query.Parser) {
+	sq, err := ps.ParseQuery(s)
+	if err != nil {
+		panic(err)
 	}
+	qi.Select = sq
+}
 
-	for tok := s.Scan(); tok != scanner.EOF; tok = s.Scan() {
-		word := s.TokenText()
-		row := []types.DataType{}
+func (qi *QueryInsert) parseValues(s *lexer.Lexer, ps query.Parser) {
+	qi.Values = [][]*projection.Projection{}
 
-		if word != "(" {
-			panic(errors.ErrSyntax)
-		}
+	s.Expect("VALUES")
+	for {
+		row := []*projection.Projection{}
 
-		for tok := s.Scan(); tok != scanner.EOF; tok = s.Scan() {
-			word := s.TokenText()
-			_, isKW := kwords.KeyWords[word]
-			if tok == scanner.EOF || isKW {
-				panic(errors.ErrSyntax)
+		s.Expect("(")
+		for {
+			tok := s.Token()
+			p, _ := parseExpr(s, ps, 0)
+			if !isConstant(p) {
+				s.ErrorAt(tok, "value must be constant expression")
 			}
+			row = append(row, p)
 
-			var val interface{}
-			if err := json.Unmarshal([]byte(word), &val); err != nil {
-				panic(err)
-			}
-			row = append(row, types.ParseJSONValue(val))
-
-			tok = s.Scan()
-			word = s.TokenText()
-			_, isKW = kwords.KeyWords[word]
-			if tok == scanner.EOF || (word != "," && word != ")") || isKW {
-				panic(errors.ErrSyntax)
-			} else if word == ")" {
+			if s.Expect(",", ")") == ")" {
 				break
 			}
 		}
 
 		qi.Values = append(qi.Values, row)
-
-		s.Scan()
-		word = s.TokenText()
-		if word != "," && word != ";" {
-			panic(errors.ErrSyntax)
+		if !s.Is(",") {
+			break
 		}
+		s.Scan()
 	}
 }
@@ -0,0 +1,28 @@
+package dml
+
+import (
+	"testing"
+
+	"go-dbms/pkg/table"
+	"go-dbms/services/parser/lexer"
+	"go-dbms/services/parser/query/dml/function"
+	"go-dbms/services/parser/query/dml/projection"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOnDuplicate(t *testing.T) {
+	s, err := lexer.New([]byte("INSERT INTO t (id, v) VALUES (1, 2) ON DUPLICATE KEY UPDATE v = v + 1"))
+	require.NoError(t, err)
+
+	qi := &QueryInsert{}
+	require.NoError(t, qi.Parse(s, nil))
+	require.Equal(t, table.CONFLICT_UPDATE, qi.OnConflict)
+	require.Len(t, qi.Update, 1)
+
+	v := qi.Update["v"]
+	require.Equal(t, string(function.ADD), v.Name)
+	require.Equal(t, projection.IDENTIFIER, v.Arguments[0].Type)
+	require.Equal(t, "v", v.Arguments[0].Name)
+	require.Equal(t, lexer.EOF, s.Token().Kind)
+}
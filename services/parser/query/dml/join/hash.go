@@ -0,0 +1,436 @@
+package join
+
+import (
+	"strconv"
+
+	"go-dbms/pkg/statement"
+	"go-dbms/pkg/types"
+	"go-dbms/pkg/types/hashed"
+	"go-dbms/pkg/types/spill"
+	"go-dbms/services/parser/query/dml/eval"
+	"go-dbms/services/parser/query/dml/projection"
+	"go-dbms/util/stream"
+)
+
+// DefaultMemLimit is the amount of memory in bytes hash table can use before
+// spilling rows to disk.
+const DefaultMemLimit = 32 << 20
+
+const partitions = 16
+
+// maxLevel is how many times partition can be split again,
+// if its right rows don't fit into memory.
+const maxLevel = 3
+
+// Hash implements hash join. Right rows are collected into hash table by
+// right keys and left rows are probed against it. If hash table exceeds
+// memory limit, both sides are partitioned by key hash to temporary files
+// and joined partition by partition. Partitions exceeding memory limit are
+// split again, or joined by nested loop if they can't be split, e.g. if
+// most of right rows have the same key.
+type Hash struct {
+	leftKeys, rightKeys []*projection.Projection
+	on                  *statement.WhereStatement
+	outer               bool
+	dst                 stream.WriterContinue[types.DataRow]
+
+	memLimit int
+	memSize  int
+	metas    []types.DataTypeMeta
+	table    map[string][]types.DataRow
+	build    []*spill.File
+	probe    []*spill.File
+}
+
+// NewHash creates hash join of rows by equality of leftKeys and rightKeys,
+// on is an additional condition checked against joined row. If outer is
+// true, left rows without match are pushed to dst as is.
+func NewHash(
+	leftKeys, rightKeys []*projection.Projection,
+	on *statement.WhereStatement,
+	outer bool,
+	memLimit int,
+	dst stream.WriterContinue[types.DataRow],
+) *Hash {
+	if memLimit <= 0 {
+		memLimit = DefaultMemLimit
+	}
+
+	return &Hash{
+		leftKeys:  leftKeys,
+		rightKeys: rightKeys,
+		on:        on,
+		outer:     outer,
+		dst:       dst,
+		memLimit:  memLimit,
+		table:     map[string][]types.DataRow{},
+	}
+}
+
+// Build adds right row to hash table.
+func (h *Hash) Build(row types.DataRow) error {
+	vals, ok := values(row, h.rightKeys)
+	if !ok {
+		return nil // can't match anything
+	}
+
+	if h.metas == nil {
+		h.metas = make([]types.DataTypeMeta, len(vals))
+		for i, v := range vals {
+			h.metas[i] = v.MetaCopy()
+		}
+	}
+
+	key, ok := h.key(vals)
+	if !ok {
+		return nil
+	} else if h.build != nil {
+		return h.build[partition(key, 0)].Write(row)
+	}
+
+	h.table[key] = append(h.table[key], row)
+	h.memSize += row.MemSize()
+	if h.memSize >= h.memLimit {
+		return h.spill()
+	}
+	return nil
+}
+
+// Probe joins left row with collected right rows. Returns true if
+// dst doesn't want to continue.
+func (h *Hash) Probe(row types.DataRow) (stop bool, err error) {
+	key, ok := h.leftKey(row)
+	if h.build != nil {
+		if !ok {
+			if h.outer {
+				return h.push(row), nil
+			}
+			return false, nil
+		}
+		return false, h.probe[partition(key, 0)].Write(row)
+	}
+
+	return h.match(row, key, ok), nil
+}
+
+// Flush joins spilled partitions, if any.
+func (h *Hash) Flush() error {
+	if h.build == nil {
+		return nil
+	}
+
+	for i := range h.build {
+		if stop, err := h.joinPartition(h.build[i], h.probe[i], 0); err != nil || stop {
+			return err
+		}
+	}
+	return nil
+}
+
+// joinPartition joins spilled partition of given level. If its right rows
+// don't fit into memory, partition is split by hash of next level, or
+// joined by nested loop if max level is reached.
+func (h *Hash) joinPartition(build, probe *spill.File, level int) (stop bool, err error) {
+	if err := build.Rewind(); err != nil {
+		return false, err
+	}
+
+	if full, err := h.load(build); err != nil {
+		return false, err
+	} else if !full {
+		return h.probeAll(probe)
+	} else if level < maxLevel {
+		return h.repartition(build, probe, level)
+	}
+	return h.nestedLoop(build, probe)
+}
+
+// load reads right rows from file into hash table till memory limit is reached.
+// Returns true if limit is reached, next call continues from the next row.
+func (h *Hash) load(build *spill.File) (full bool, err error) {
+	h.table = map[string][]types.DataRow{}
+	h.memSize = 0
+	for h.memSize < h.memLimit {
+		row, ok, err := build.Next()
+		if err != nil {
+			return false, err
+		} else if !ok {
+			return false, nil
+		}
+
+		key, _ := h.rightKey(row)
+		h.table[key] = append(h.table[key], row)
+		h.memSize += row.MemSize()
+	}
+	return true, nil
+}
+
+func (h *Hash) probeAll(probe *spill.File) (stop bool, err error) {
+	if err := probe.Rewind(); err != nil {
+		return false, err
+	}
+
+	for row, ok, err := probe.Next(); ok || err != nil; row, ok, err = probe.Next() {
+		if err != nil {
+			return false, err
+		}
+
+		key, _ := h.leftKey(row)
+		if h.match(row, key, true) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// repartition splits partition of given level into partitions
+// of next level and joins them one by one.
+func (h *Hash) repartition(build, probe *spill.File, level int) (stop bool, err error) {
+	h.table = map[string][]types.DataRow{}
+	h.memSize = 0
+
+	builds, err := newPartitions()
+	if err != nil {
+		return false, err
+	}
+	defer closePartitions(builds)
+
+	probes, err := newPartitions()
+	if err != nil {
+		return false, err
+	}
+	defer closePartitions(probes)
+
+	if err := split(build, builds, h.rightKey, level+1); err != nil {
+		return false, err
+	}
+	if err := split(probe, probes, h.leftKey, level+1); err != nil {
+		return false, err
+	}
+
+	for i := range builds {
+		if stop, err := h.joinPartition(builds[i], probes[i], level+1); err != nil || stop {
+			return stop, err
+		}
+	}
+	return false, nil
+}
+
+// nestedLoop joins partition by loading its right rows by chunks fitting into
+// memory and probing all left rows against each chunk. For outer join left
+// rows matched by none of chunks are pushed after the last chunk.
+func (h *Hash) nestedLoop(build, probe *spill.File) (stop bool, err error) {
+	if err := build.Rewind(); err != nil {
+		return false, err
+	}
+
+	var matched []bool // by position of left row in probe file
+	for full := true; full; {
+		if full, err = h.load(build); err != nil {
+			return false, err
+		} else if len(h.table) == 0 {
+			break
+		}
+
+		if err := probe.Rewind(); err != nil {
+			return false, err
+		}
+		for i := 0; ; i++ {
+			row, ok, err := probe.Next()
+			if err != nil {
+				return false, err
+			} else if !ok {
+				break
+			}
+
+			key, _ := h.leftKey(row)
+			m, stop := h.join(row, key)
+			if stop {
+				return true, nil
+			} else if h.outer {
+				if i == len(matched) {
+					matched = append(matched, false)
+				}
+				matched[i] = matched[i] || m
+			}
+		}
+	}
+
+	if !h.outer {
+		return false, nil
+	}
+
+	if err := probe.Rewind(); err != nil {
+		return false, err
+	}
+	for i := 0; ; i++ {
+		row, ok, err := probe.Next()
+		if err != nil {
+			return false, err
+		} else if !ok {
+			return false, nil
+		}
+
+		if (i >= len(matched) || !matched[i]) && h.push(row) {
+			return true, nil
+		}
+	}
+}
+
+// Close removes all temporary files.
+func (h *Hash) Close() {
+	for i := range h.build {
+		h.build[i].Close()
+		h.probe[i].Close()
+	}
+	h.build, h.probe, h.table = nil, nil, nil
+}
+
+func (h *Hash) match(row types.DataRow, key string, ok bool) (stop bool) {
+	matched := false
+	if ok {
+		if matched, stop = h.join(row, key); stop {
+			return true
+		}
+	}
+
+	if !matched && h.outer {
+		return h.push(row)
+	}
+	return false
+}
+
+// join pushes left row joined with each right row of the same key.
+func (h *Hash) join(row types.DataRow, key string) (matched, stop bool) {
+	for _, r := range h.table[key] {
+		joined := make(types.DataRow, len(row)+len(r))
+		for k, v := range row {
+			joined[k] = v
+		}
+		for k, v := range r {
+			joined[k] = v
+		}
+
+		if h.on != nil && !h.on.Compare(joined) {
+			continue
+		}
+
+		matched = true
+		if h.push(joined) {
+			return true, true
+		}
+	}
+	return matched, false
+}
+
+func (h *Hash) push(row types.DataRow) (stop bool) {
+	h.dst.Push(row)
+	return !h.dst.ShouldContinue()
+}
+
+func (h *Hash) spill() error {
+	build, err := newPartitions()
+	if err != nil {
+		return err
+	}
+	probe, err := newPartitions()
+	if err != nil {
+		closePartitions(build)
+		return err
+	}
+	h.build, h.probe = build, probe
+
+	for key, rows := range h.table {
+		for _, row := range rows {
+			if err := h.build[partition(key, 0)].Write(row); err != nil {
+				return err
+			}
+		}
+	}
+
+	h.table = map[string][]types.DataRow{}
+	h.memSize = 0
+	return nil
+}
+
+func newPartitions() ([]*spill.File, error) {
+	files := make([]*spill.File, partitions)
+	for i := range files {
+		var err error
+		if files[i], err = spill.New(); err != nil {
+			closePartitions(files[:i])
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+func closePartitions(files []*spill.File) {
+	for _, f := range files {
+		f.Close()
+	}
+}
+
+// split writes rows of file to partitions of given level by their keys.
+func split(file *spill.File, parts []*spill.File, key func(types.DataRow) (string, bool), level int) error {
+	if err := file.Rewind(); err != nil {
+		return err
+	}
+
+	for row, ok, err := file.Next(); ok || err != nil; row, ok, err = file.Next() {
+		if err != nil {
+			return err
+		}
+
+		k, _ := key(row)
+		if err := parts[partition(k, level)].Write(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// partition returns partition of key on given level of partitioning,
+// keys of one partition are spread over partitions of the next level.
+func partition(key string, level int) int {
+	return hashed.Partition(strconv.Itoa(level)+key, partitions)
+}
+
+func (h *Hash) rightKey(row types.DataRow) (string, bool) {
+	if vals, ok := values(row, h.rightKeys); ok {
+		return h.key(vals)
+	}
+	return "", false
+}
+
+func (h *Hash) leftKey(row types.DataRow) (string, bool) {
+	if vals, ok := values(row, h.leftKeys); ok {
+		return h.key(vals)
+	}
+	return "", false
+}
+
+func values(row types.DataRow, keys []*projection.Projection) ([]types.DataType, bool) {
+	vals := make([]types.DataType, len(keys))
+	for i, k := range keys {
+		if vals[i] = eval.Eval(row, k); vals[i] == nil {
+			return nil, false
+		}
+	}
+	return vals, true
+}
+
+// key encodes values casted to right side types, so equal
+// values of different types have the same key.
+func (h *Hash) key(vals []types.DataType) (string, bool) {
+	if h.metas != nil {
+		for i, v := range vals {
+			casted, err := v.Cast(h.metas[i])
+			if err != nil {
+				return "", false
+			}
+			vals[i] = casted
+		}
+	}
+	return hashed.Key(vals...), true
+}
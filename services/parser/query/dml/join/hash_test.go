@@ -0,0 +1,99 @@
+package join
+
+import (
+	"testing"
+
+	"go-dbms/pkg/types"
+	"go-dbms/services/parser/query/dml/projection"
+	"go-dbms/util/stream"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func intVal(n int) types.DataType {
+	return types.Type(types.Meta(types.TYPE_INTEGER, true, 4, true)).Set(int32(n))
+}
+
+func testJoin(t *testing.T, memLimit int, outer bool, keyOf func(i int) int) map[[2]int32]int {
+	leftKey := &projection.Projection{Name: "l.k", Alias: "l.k", Type: projection.IDENTIFIER}
+	rightKey := &projection.Projection{Name: "r.k", Alias: "r.k", Type: projection.IDENTIFIER}
+
+	dst := stream.New[types.DataRow](1)
+	h := NewHash(
+		[]*projection.Projection{leftKey},
+		[]*projection.Projection{rightKey},
+		nil, outer, memLimit, dst,
+	)
+
+	errCh := make(chan error, 1)
+	go func() {
+		defer dst.Close()
+		defer h.Close()
+
+		errCh <- func() error {
+			for i := 0; i < 100; i++ {
+				if err := h.Build(types.DataRow{"r.k": intVal(keyOf(i)), "r.id": intVal(i)}); err != nil {
+					return err
+				}
+			}
+			for i := 0; i < 60; i++ {
+				stop, err := h.Probe(types.DataRow{"l.k": intVal(i), "l.id": intVal(i)})
+				if err != nil {
+					return err
+				} else if stop {
+					return errors.New("probe stopped")
+				}
+			}
+			return h.Flush()
+		}()
+	}()
+
+	res := map[[2]int32]int{}
+	for row, ok := dst.Pop(); ok; row, ok = dst.Pop() {
+		dst.Continue(true)
+		key := [2]int32{row["l.id"].Value().(int32), -1}
+		if row["r.id"] != nil {
+			key[1] = row["r.id"].Value().(int32)
+		}
+		res[key]++
+	}
+	require.NoError(t, <-errCh)
+	return res
+}
+
+func TestHashJoin(t *testing.T) {
+	mod50 := func(i int) int { return i % 50 }
+	for _, memLimit := range []int{DefaultMemLimit, 512} {
+		res := testJoin(t, memLimit, false, mod50)
+		require.Len(t, res, 100)
+		for i := 0; i < 100; i++ {
+			require.Equal(t, 1, res[[2]int32{int32(i % 50), int32(i)}])
+		}
+
+		res = testJoin(t, memLimit, true, mod50)
+		require.Len(t, res, 110)
+		for i := 50; i < 60; i++ {
+			require.Equal(t, 1, res[[2]int32{int32(i), -1}])
+		}
+	}
+}
+
+func TestHashJoinSkewed(t *testing.T) {
+	// all right rows have the same key, so their partition can't be split to fit into memory
+	same := func(i int) int { return 7 }
+
+	res := testJoin(t, 512, false, same)
+	require.Len(t, res, 100)
+	for i := 0; i < 100; i++ {
+		require.Equal(t, 1, res[[2]int32{7, int32(i)}])
+	}
+
+	res = testJoin(t, 512, true, same)
+	require.Len(t, res, 159)
+	for i := 0; i < 60; i++ {
+		if i != 7 {
+			require.Equal(t, 1, res[[2]int32{int32(i), -1}])
+		}
+	}
+}
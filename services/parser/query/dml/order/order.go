@@ -0,0 +1,56 @@
+package order
+
+import (
+	"go-dbms/pkg/types"
+	"go-dbms/pkg/types/sorted"
+	"go-dbms/services/parser/query/dml/eval"
+	"go-dbms/services/parser/query/dml/projection"
+	"go-dbms/util/stream"
+)
+
+type Item struct {
+	Projection *projection.Projection
+	Desc       bool
+}
+
+type Order struct {
+	items  []*Item
+	sorter *sorted.Sorter
+	dst    stream.WriterContinue[types.DataRow]
+}
+
+func New(items []*Item, dst stream.WriterContinue[types.DataRow]) *Order {
+	o := &Order{
+		items: items,
+		dst:   dst,
+	}
+	o.sorter = sorted.NewSorter(o.compare, sorted.DefaultMemLimit)
+	return o
+}
+
+func (o *Order) Add(row types.DataRow) error {
+	for _, it := range o.items {
+		if _, ok := row[it.Projection.Alias]; !ok {
+			row[it.Projection.Alias] = eval.Eval(row, it.Projection)
+		}
+	}
+	return o.sorter.Add(row)
+}
+
+func (o *Order) Flush() error {
+	defer o.sorter.Close()
+	return o.sorter.Flush(o.dst)
+}
+
+func (o *Order) compare(a, b types.DataRow) int {
+	for _, it := range o.items {
+		cmp := types.Compare(a[it.Projection.Alias], b[it.Projection.Alias])
+		if it.Desc {
+			cmp = -cmp
+		}
+		if cmp != 0 {
+			return cmp
+		}
+	}
+	return 0
+}
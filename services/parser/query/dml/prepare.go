@@ -1,17 +1,13 @@
 package dml
 
 import (
-	"strconv"
-	"text/scanner"
-
-	"go-dbms/services/parser/errors"
-	"go-dbms/services/parser/kwords"
+	"go-dbms/services/parser/lexer"
 	"go-dbms/services/parser/query"
 	"go-dbms/util/helpers"
 )
 
 /*
-PREPARE TABLE <tableName> ROWS <n>;
+PREPARE TABLE [<dbName>.]<tableName> ROWS <n>;
 */
 type QueryPrepare struct {
 	query.Query
@@ -21,7 +17,7 @@ type QueryPrepare struct {
 }
 
 
-func (qp *QueryPrepare) Parse(s *scanner.Scanner, ps query.Parser) (err error) {
+func (qp *QueryPrepare) Parse(s *lexer.Lexer, ps query.Parser) (err error) {
 	defer helpers.RecoverOnError(&err)()
 
 	qp.Type = query.PREPARE
@@ -32,35 +28,13 @@ func (qp *QueryPrepare) Parse(s *scanner.Scanner, ps query.Parser) (err error) {
 	return nil
 }
 
-func (qp *QueryPrepare) parseTable(s *scanner.Scanner) {
-	s.Scan()
-	if s.TokenText() != "TABLE" {
-		panic(errors.ErrSyntax)
-	}
-
-	s.Scan()
-	qp.Table = s.TokenText()
-	if _, isKW := kwords.KeyWords[qp.Table]; isKW {
-		panic(errors.ErrSyntax)
-	}
+func (qp *QueryPrepare) parseTable(s *lexer.Lexer) {
+	s.Expect("PREPARE")
+	s.Expect("TABLE")
+	qp.DB, qp.Table = s.TableName()
 }
 
-func (qp *QueryPrepare) parseRows(s *scanner.Scanner) {
-	s.Scan()
-	if s.TokenText() != "ROWS" {
-		panic(errors.ErrSyntax)
-	}
-
-	s.Scan()
-	rows, err := strconv.Atoi(s.TokenText())
-	if err != nil {
-		panic(err)
-	}
-	
-	qp.Rows = rows
-
-	s.Scan()
-	if s.TokenText() != ";" {
-		panic(errors.ErrSyntax)
-	}
+func (qp *QueryPrepare) parseRows(s *lexer.Lexer) {
+	s.Expect("ROWS")
+	qp.Rows = parseUint(s)
 }
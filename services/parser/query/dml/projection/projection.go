@@ -2,6 +2,7 @@ package projection
 
 import (
 	"fmt"
+	"slices"
 
 	"go-dbms/pkg/column"
 	"go-dbms/pkg/types"
@@ -16,8 +17,16 @@ const (
 	IDENTIFIER
 	LITERAL
 	SUBQUERY
+	LIST
+	CASE
 )
 
+// Condition is boolean expression of CASE projection.
+type Condition interface {
+	Compare(row types.DataRow) bool
+	Operands() []*Projection
+}
+
 func FromCols(cols []*column.Column) *Projections {
 	p := New()
 	for _, col := range cols {
@@ -37,6 +46,29 @@ type Projection struct {
 	Arguments []*Projection
 	Literal   types.DataType
 	Subquery  query.Querier
+	Distinct  bool
+
+	// CASE returns first of Arguments which condition is satisfied,
+	// extra last argument is returned if none of conditions is satisfied
+	Conditions []Condition
+}
+
+// Bind sets value of parameter placeholder, placeholder is LITERAL projection.
+func (p *Projection) Bind(val types.DataType) {
+	p.Literal = val
+}
+
+// Operands returns arguments of projection and operands of its conditions.
+func (p *Projection) Operands() []*Projection {
+	if len(p.Conditions) == 0 {
+		return p.Arguments
+	}
+
+	ops := slices.Clone(p.Arguments)
+	for _, cond := range p.Conditions {
+		ops = append(ops, cond.Operands()...)
+	}
+	return ops
 }
 
 func New() *Projections {
@@ -71,6 +103,14 @@ func (p *Projections) Add(pr *Projection) {
 	}
 }
 
+func (p *Projections) Copy() *Projections {
+	cp := New()
+	for _, pr := range p.list {
+		cp.Add(pr)
+	}
+	return cp
+}
+
 func (p *Projections) Has(alias string) bool {
 	_, found := p.mapping[alias]
 	return found
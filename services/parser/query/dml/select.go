@@ -4,17 +4,19 @@ import (
 	"bytes"
 	"fmt"
 	r "math/rand"
-	"text/scanner"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"go-dbms/pkg/index"
 	"go-dbms/pkg/statement"
 	"go-dbms/pkg/types"
-	"go-dbms/services/parser/errors"
-	"go-dbms/services/parser/kwords"
+	"go-dbms/services/parser/lexer"
 	"go-dbms/services/parser/query"
 	"go-dbms/services/parser/query/dml/aggregator"
 	"go-dbms/services/parser/query/dml/function"
+	"go-dbms/services/parser/query/dml/order"
 	"go-dbms/services/parser/query/dml/projection"
 	"go-dbms/util/helpers"
 )
@@ -32,354 +34,828 @@ type From struct {
 	DB, Table string
 	SubQuery  query.Querier
 	Type      FromType
+	Alias     string
+	Joins     []*Join
+}
+
+// Name returns name by which source columns can be qualified.
+func (f From) Name() string {
+	if f.Alias != "" {
+		return f.Alias
+	}
+	return f.Table
+}
+
+type JoinType uint8
+
+const (
+	JOIN_INNER JoinType = iota
+	JOIN_LEFT
+)
+
+type Join struct {
+	Type   JoinType
+	Source From
+	On     *statement.WhereStatement
+}
+
+type Limit struct {
+	Count, Offset int
 }
 
 /*
-SELECT <...projection>
-FROM <tableName>
-[WHERE_INDEX <indexName> <condition> [AND <condition>]]
+SELECT [DISTINCT] <...projection>
+FROM [<dbName>.]<tableName> [AS <alias>]
+[USE_INDEX <indexName>]
+[WHERE_INDEX (<condition> [AND <condition>]) [AND (<condition> [AND <condition>])]]
+[[INNER | LEFT [OUTER]] JOIN <[dbName.]tableName | subquery> [AS <alias>] ON <...condition>]
 [WHERE <...condition>]
-[GROUP BY <...projection>];
+[GROUP BY <...projection>]
+[HAVING <...condition>]
+[ORDER BY <projection> [ASC | DESC], ...]
+[LIMIT <count> [OFFSET <offset>]];
 */
 type QuerySelect struct {
 	query.Query
+	Distinct    bool
 	Projections *projection.Projections
 	From        From
 	UseIndex    string
 	Where       *statement.WhereStatement
 	WhereIndex  *WhereIndex
-	GroupBy     map[string]struct{}
+	GroupBy     []*projection.Projection
+	Having      *statement.WhereStatement
+	OrderBy     []*order.Item
+	Limit       *Limit
 }
 
-func (qs *QuerySelect) Parse(s *scanner.Scanner, ps query.Parser) (err error) {
+func (qs *QuerySelect) Parse(s *lexer.Lexer, ps query.Parser) (err error) {
 	defer helpers.RecoverOnError(&err)()
 
 	qs.Type = query.SELECT
 
+	s.Expect("SELECT")
 	qs.parseProjections(s, ps)
 	qs.parseFrom(s, ps)
 	qs.parseUseIndex(s)
 	qs.parseWhereIndex(s, ps)
+	qs.parseJoins(s, ps)
 	qs.parseWhere(s, ps)
-	qs.parseGroupBy(s)
+	qs.parseGroupBy(s, ps)
+	qs.parseHaving(s, ps)
+	qs.parseOrderBy(s, ps)
+	qs.parseLimit(s)
 
 	return nil
 }
 
-func (qs *QuerySelect) parseProjections(s *scanner.Scanner, ps query.Parser) {
+func (qs *QuerySelect) parseProjections(s *lexer.Lexer, ps query.Parser) {
 	qs.Projections = projection.New()
-	s.Scan()
-
-	p := parseProjection(s, ps)
-	qs.Projections.Add(p)
+	if s.Is("DISTINCT") {
+		qs.Distinct = true
+		s.Scan()
+	}
 
-	for s.TokenText() != "FROM" {
+	for {
+		qs.Projections.Add(parseProjection(s, ps))
+		if !s.Is(",") {
+			break
+		}
 		s.Scan()
-		p = parseProjection(s, ps)
-		qs.Projections.Add(p)
 	}
 }
 
-func parseProjection(s *scanner.Scanner, ps query.Parser) *projection.Projection {
-	word := s.TokenText()
-	_, isKW := kwords.KeyWords[word]
-	if isKW || word == "," || word == ")" {
-		panic(errors.ErrSyntax)
+// arithmetic operators with functions implementing them, operators with
+// higher precedence bind tighter
+var arithmetic = map[string]struct {
+	fn   function.FunctionType
+	prec int
+}{
+	"+": {function.ADD, 1},
+	"-": {function.SUB, 1},
+	"*": {function.MUL, 2},
+	"/": {function.DIV, 2},
+	"%": {function.RES, 2},
+}
+
+func parseProjection(s *lexer.Lexer, ps query.Parser) *projection.Projection {
+	p, _ := parseExpr(s, ps, 0)
+	if s.Is("AS") {
+		s.Scan()
+		p.Alias = s.Ident()
 	}
+	return p
+}
 
-	p := &projection.Projection{}
+// parseExpr parses infix expression by precedence climbing, operators with
+// precedence lower than minPrec are left to caller. Besides projection returns
+// text of expression, which is used as alias.
+func parseExpr(s *lexer.Lexer, ps query.Parser, minPrec int) (*projection.Projection, string) {
+	left, text := parseOperand(s, ps)
 
-	if word == "(" {
-		s.Scan()
-		sq, err := ps.ParseQuery(s)
-		if err != nil {
-			panic(err)
+	for {
+		word := s.TokenText()
+		op, ok := arithmetic[word]
+		if !ok || s.Token().Kind != lexer.Op || op.prec < minPrec {
+			return left, text
 		}
 
-		p.Subquery = sq
-		p.Type = projection.SUBQUERY
-		p.Alias = fmt.Sprint(rand.Int63())
-		p.Name = p.Alias
+		s.Scan()
+		right, rightText := parseExpr(s, ps, op.prec+1)
+		text += word + rightText
+		left = &projection.Projection{
+			Alias:     text,
+			Name:      string(op.fn),
+			Type:      projection.FUNCTION,
+			Arguments: []*projection.Projection{left, right},
+		}
+	}
+}
 
-		word = s.TokenText()
-	} else {
-		p.Alias = word
-		p.Name = word
-		p.Type = projection.IDENTIFIER
+func parseOperand(s *lexer.Lexer, ps query.Parser) (*projection.Projection, string) {
+	tok := s.Token()
+	p := &projection.Projection{}
 
-		jsonVal, isLiteral := helpers.ParseJSONToken([]byte(word))
+	switch {
+		case s.Is("("):
+			if s.Scan(); !s.Is("SELECT") {
+				p, text := parseExpr(s, ps, 0)
+				s.Expect(")")
+				return p, "(" + text + ")"
+			}
 
-		s.Scan()
-		word = s.TokenText()
-		_, isOP := kwords.IndexOperators[types.Operator(word)]
+			sq, err := ps.ParseQuery(s)
+			if err != nil {
+				panic(err)
+			}
 
-		if isLiteral {
-			p.Type = projection.LITERAL
-			p.Literal = types.ParseJSONValue(jsonVal)
+			p.Subquery = sq
+			p.Type = projection.SUBQUERY
 			p.Alias = fmt.Sprint(rand.Int63())
 			p.Name = p.Alias
-		} else if word == "FROM" || word == "," || word == ")" || isOP {
-			return p
-		} else if word == "(" {
-			buf := bytes.NewBuffer([]byte(p.Alias))
-			p.Arguments = []*projection.Projection{}
-
-			if aggregator.IsAggregator(p.Name) {
-				p.Type = projection.AGGREGATOR
-			} else if function.IsFunction(p.Name) {
-				p.Type = projection.FUNCTION
-			} else {
-				panic(fmt.Errorf("unknown aggregation/function: '%s'", p.Name))
+
+			s.Expect(")")
+			return p, "(subquery)"
+
+		case s.Is("-"):
+			s.Scan()
+			arg, text := parseOperand(s, ps)
+			zero := types.Type(types.Meta(types.TYPE_INTEGER, true, 8, false)).Set(0)
+
+			// negative numbers are folded into literals, so they can be used
+			// in where_index conditions
+			if arg.Type == projection.LITERAL && arg.Literal != nil &&
+				(arg.Literal.GetCode() == types.TYPE_INTEGER || arg.Literal.GetCode() == types.TYPE_FLOAT) {
+				arg.Literal = function.Eval(function.SUB, nil, []types.DataType{zero, arg.Literal})
+				return arg, "-" + text
 			}
 
-			buf.WriteByte('(')
-			for tok := s.Scan(); tok != scanner.EOF; tok = s.Scan() {
-				word = s.TokenText()
-				if word == "," {
-					continue
-				} else if word == ")" {
-					break
-				}
+			p.Alias = "-" + text
+			p.Name = string(function.SUB)
+			p.Type = projection.FUNCTION
+			p.Arguments = []*projection.Projection{{
+				Alias:   fmt.Sprint(rand.Int63()),
+				Type:    projection.LITERAL,
+				Literal: zero,
+			}, arg}
+			p.Arguments[0].Name = p.Arguments[0].Alias
+			return p, p.Alias
 
-				p.Arguments = append(p.Arguments, parseProjection(s, ps))
+		case s.Is("CASE"):
+			return parseCase(s, ps)
 
-				buf.Write([]byte(word))
-				buf.WriteByte(',')
+		case s.Is("IF") && s.Peek().Text == "(":
+			return parseIf(s, ps)
 
-				word := s.TokenText()
-				if word == ")" {
-					break
+		case s.Is("NULL"), tok.Kind == lexer.Int, tok.Kind == lexer.Float, tok.Kind == lexer.String:
+			p.Type = projection.LITERAL
+			p.Alias = fmt.Sprint(rand.Int63())
+			p.Name = p.Alias
+			if tok.Kind != lexer.Ident {
+				val, ok := helpers.ParseJSONToken([]byte(tok.Text))
+				if !ok {
+					s.Errorf("invalid literal %v", tok)
 				}
+				p.Literal = types.ParseJSONValue(val)
 			}
+			s.Scan()
+			return p, tok.Text
+
+		case tok.Kind == lexer.Placeholder:
+			p.Type = projection.LITERAL
+			p.Alias = fmt.Sprint(rand.Int63())
+			p.Name = p.Alias
+			s.Param(p)
+			return p, tok.Text
 
-			buf.Truncate(buf.Len() - 1)
-			buf.WriteByte(')')
-			p.Alias = buf.String()
+		case tok.Kind != lexer.Ident || s.IsKeyword():
+			s.Unexpected("expression")
+	}
+
+	if s.Peek().Text == "(" && s.Peek().Kind == lexer.Op {
+		return parseCall(s, ps)
+	}
+
+	p.Type = projection.IDENTIFIER
+	p.Name = s.Ident()
+	if s.Is(".") {
+		s.Scan()
+		p.Name += "." + s.Ident()
+	}
+	p.Alias = p.Name
+
+	return p, p.Alias
+}
 
+// parseCall parses call of aggregator or function, names are case-insensitive.
+func parseCall(s *lexer.Lexer, ps query.Parser) (*projection.Projection, string) {
+	p := &projection.Projection{Name: strings.ToUpper(s.TokenText())}
+
+	if aggregator.IsAggregator(p.Name) {
+		p.Type = projection.AGGREGATOR
+	} else if function.IsFunction(p.Name) {
+		p.Type = projection.FUNCTION
+	} else {
+		s.Errorf("unknown aggregation/function: '%s'", s.TokenText())
+	}
+
+	s.Scan()
+	s.Expect("(")
+
+	buf := bytes.NewBufferString(p.Name)
+	buf.WriteByte('(')
+	p.Arguments = []*projection.Projection{}
+
+	for !s.Is(")") {
+		if len(p.Arguments) > 0 {
+			s.Expect(",")
+			buf.WriteByte(',')
+		} else if p.Type == projection.AGGREGATOR && !p.Distinct && s.Is("DISTINCT") {
+			p.Distinct = true
+			buf.WriteString("DISTINCT ")
 			s.Scan()
-			word = s.TokenText()
-		} else if word != "AS" {
-			panic(errors.ErrSyntax)
+			continue
 		}
+
+		arg, text := parseExpr(s, ps, 0)
+		p.Arguments = append(p.Arguments, arg)
+		buf.WriteString(text)
+	}
+	s.Scan()
+
+	buf.WriteByte(')')
+	p.Alias = buf.String()
+	return p, p.Alias
+}
+
+// parseCase parses searched and simple CASE expressions. Simple CASE is
+// converted to searched one by comparing operand with every WHEN value.
+func parseCase(s *lexer.Lexer, ps query.Parser) (*projection.Projection, string) {
+	p := &projection.Projection{
+		Alias: fmt.Sprint(rand.Int63()),
+		Type:  projection.CASE,
 	}
+	p.Name = p.Alias
 
-	if word == "AS" {
+	var operand *projection.Projection
+	if s.Scan(); !s.Is("WHEN") {
+		operand, _ = parseExpr(s, ps, 0)
+	}
+
+	for s.Is("WHEN") {
 		s.Scan()
-		p.Alias = s.TokenText()
+
+		var cond *statement.WhereStatement
+		if operand == nil {
+			cond = parseCondition(s, ps)
+		} else {
+			val, _ := parseExpr(s, ps, 0)
+			cond = statement.WhereS(&statement.Statement{
+				Left:  operand,
+				Op:    types.Equal,
+				Right: val,
+			})
+		}
+
+		s.Expect("THEN")
+		res, _ := parseExpr(s, ps, 0)
+		p.Conditions = append(p.Conditions, cond)
+		p.Arguments = append(p.Arguments, res)
+	}
+
+	if len(p.Conditions) == 0 {
+		s.Unexpected("WHEN")
+	} else if s.Is("ELSE") {
 		s.Scan()
+		res, _ := parseExpr(s, ps, 0)
+		p.Arguments = append(p.Arguments, res)
 	}
+	s.Expect("END")
 
-	return p
+	return p, p.Alias
 }
 
-func (qs *QuerySelect) parseFrom(s *scanner.Scanner, ps query.Parser) {
-	word := s.TokenText()
-	if word != "FROM" {
-		panic(errors.ErrNoFrom)
+// parseIf parses IF(<condition>, <then>, <else>) as CASE expression.
+func parseIf(s *lexer.Lexer, ps query.Parser) (*projection.Projection, string) {
+	p := &projection.Projection{
+		Alias: fmt.Sprint(rand.Int63()),
+		Type:  projection.CASE,
 	}
+	p.Name = p.Alias
 
-	tok := s.Scan()
-	if tok == scanner.EOF {
-		panic(errors.ErrSyntax)
+	s.Scan()
+	s.Expect("(")
+	p.Conditions = []projection.Condition{parseCondition(s, ps)}
+	for range 2 {
+		s.Expect(",")
+		res, _ := parseExpr(s, ps, 0)
+		p.Arguments = append(p.Arguments, res)
 	}
+	s.Expect(")")
 
-	qs.From = From{}
-	word = s.TokenText()
-	if word == "(" {
+	return p, p.Alias
+}
+
+func (qs *QuerySelect) parseFrom(s *lexer.Lexer, ps query.Parser) {
+	s.Expect("FROM")
+	qs.From = parseSource(s, ps)
+}
+
+func parseSource(s *lexer.Lexer, ps query.Parser) From {
+	f := From{}
+	if s.Is("(") {
 		s.Scan()
 		sq, err := ps.ParseQuery(s)
 		if err != nil {
 			panic(err)
 		}
 
-		qs.From.SubQuery = sq
-		qs.From.Type = FROM_SUBQUERY
+		f.SubQuery = sq
+		f.Type = FROM_SUBQUERY
+		s.Expect(")")
 	} else {
-		qs.From.Table = word
-		qs.From.Type = FROM_SCHEMA
+		f.DB, f.Table = s.TableName()
+		f.Type = FROM_SCHEMA
 	}
 
-	s.Scan()
+	if s.Is("AS") {
+		s.Scan()
+		f.Alias = s.Ident()
+	}
+
+	return f
 }
 
-func (qs *QuerySelect) parseUseIndex(s *scanner.Scanner) {
-	word := s.TokenText()
-	if word != "USE_INDEX" {
-		return
+func (qs *QuerySelect) parseJoins(s *lexer.Lexer, ps query.Parser) {
+	for {
+		j := &Join{}
+		switch {
+			case s.Is("JOIN"):  break
+			case s.Is("INNER"): s.Scan()
+			case s.Is("LEFT"):
+				j.Type = JOIN_LEFT
+				if s.Scan(); s.Is("OUTER") {
+					s.Scan()
+				}
+			default: return
+		}
+
+		s.Expect("JOIN")
+		j.Source = parseSource(s, ps)
+		s.Expect("ON")
+		j.On = parseCondition(s, ps)
+		qs.From.Joins = append(qs.From.Joins, j)
 	}
+}
 
-	tok := s.Scan()
-	if tok == scanner.EOF {
-		panic(errors.ErrSyntax)
+func (qs *QuerySelect) parseUseIndex(s *lexer.Lexer) {
+	qs.UseIndex = parseUseIndex(s)
+}
+
+func parseUseIndex(s *lexer.Lexer) string {
+	if !s.Is("USE_INDEX") {
+		return ""
 	}
 
-	qs.UseIndex = s.TokenText()
 	s.Scan()
+	return s.Ident()
 }
 
-func (qs *QuerySelect) parseWhereIndex(s *scanner.Scanner, ps query.Parser) {
+func (qs *QuerySelect) parseWhereIndex(s *lexer.Lexer, ps query.Parser) {
 	qs.WhereIndex = parseWhereIndex(s, ps)
 }
 
-func parseWhereIndex(s *scanner.Scanner, ps query.Parser) *WhereIndex {
-	word := s.TokenText()
-	if word != "WHERE_INDEX" {
+func parseWhereIndex(s *lexer.Lexer, ps query.Parser) *WhereIndex {
+	if !s.Is("WHERE_INDEX") {
 		return nil
 	}
-
 	s.Scan()
-	if s.TokenText() != "(" {
-		panic(errors.ErrSyntax)
-	}
 
-	wi := &WhereIndex{
-		FilterStart: parseWhereIndexSection(s, ps),
-	}
+	wi := &WhereIndex{}
+	wi.FilterStart, wi.FilterEnd = parseWhereIndexSection(s, ps)
 
-	word = s.TokenText()
-	if word == "AND" {
-		s.Scan()
-		if s.TokenText() != "(" {
-			panic(errors.ErrSyntax)
+	if s.Is("AND") {
+		if wi.FilterEnd != nil {
+			s.Errorf("range condition can't be combined with end filter")
 		}
-
-		wi.FilterEnd = parseWhereIndexSection(s, ps)
-	} else {
 		s.Scan()
+
+		var end *index.Filter
+		tok := s.Token()
+		if wi.FilterEnd, end = parseWhereIndexSection(s, ps); end != nil {
+			s.ErrorAt(tok, "range condition isn't allowed in end filter")
+		}
 	}
 
 	return wi
 }
 
-func parseWhereIndexSection(s *scanner.Scanner, ps query.Parser) *index.Filter {
-	left, op, right := parseWhereFilter(s, false, ps)
-	f := &index.Filter{
-		Operator: op,
-		Conditions: []index.FilterCondition{{
+// parseWhereIndexSection parses filter of index scan. If filter contains range
+// condition (BETWEEN or prefix LIKE) it's split into start and end filters.
+func parseWhereIndexSection(s *lexer.Lexer, ps query.Parser) (start, end *index.Filter) {
+	s.Expect("(")
+
+	f := &index.Filter{}
+	ops := []types.Operator{}
+	toks := []lexer.Token{}
+	for {
+		toks = append(toks, s.Token())
+		left, op, right := parseWhereFilter(s, ps)
+		ops = append(ops, op)
+		f.Conditions = append(f.Conditions, index.FilterCondition{
 			Left:  left,
 			Right: right,
-		}},
+		})
+
+		if !s.Is("AND") {
+			break
+		}
+		s.Scan()
+	}
+	s.Expect(")")
+	f.Operator = ops[0]
+
+	// IN lists are scanned as several point lookups, so can be combined
+	// only with equality conditions
+	if slices.Contains(ops, types.In) {
+		for i, op := range ops {
+			if op != types.In && op != types.Equal {
+				s.ErrorAt(toks[i], "IN can be combined only with equality conditions in where_index")
+			}
+		}
+		f.Operator = types.In
 	}
 
-	if s.TokenText() != ")" {
-		for s.TokenText() == "AND" {
-			left, _, right := parseWhereFilter(s, false, ps)
-			f.Conditions = append(f.Conditions, index.FilterCondition{
-				Left:  left,
-				Right: right,
-			})
+	// range condition must be the last one, preceded by equality conditions
+	i := slices.IndexFunc(ops, func(op types.Operator) bool {
+		return op == types.Between || op == types.Like
+	})
+	if i == -1 {
+		return f, nil
+	}
+	for j, op := range ops[:i] {
+		if op != types.Equal {
+			s.ErrorAt(toks[j], "range condition can be preceded only by equality conditions in where_index")
 		}
 	}
+	if i != len(ops)-1 {
+		s.ErrorAt(toks[i], "range condition must be the last one in where_index")
+	}
+	return rangeFilters(f, ops[i])
+}
 
-	if s.TokenText() != ")" {
-		panic(errors.ErrSyntax)
+// rangeFilters splits filter which last condition is BETWEEN or prefix LIKE
+// into start and end filters of range scan.
+func rangeFilters(f *index.Filter, op types.Operator) (start, end *index.Filter) {
+	eqs := f.Conditions[:len(f.Conditions)-1]
+	last := f.Conditions[len(f.Conditions)-1]
+	endOp := types.LessOrEqual
+
+	var low, high *projection.Projection
+	if op == types.Between {
+		low, high = last.Right.Arguments[0], last.Right.Arguments[1]
+	} else {
+		pattern, isString := last.Right.Literal.(*types.DataTypeSTRING)
+		if last.Right.Type != projection.LITERAL || !isString {
+			panic(fmt.Errorf("pattern of LIKE must be string literal in where_index"))
+		}
+
+		prefix, ok := types.LikePrefix(pattern.Value().(string))
+		if !ok {
+			panic(fmt.Errorf("only prefix patterns are supported in where_index: '%v'", pattern.Value()))
+		}
+
+		low = literal(prefix)
+		if prefixEnd, ok := types.PrefixEnd(prefix); ok {
+			high = literal(prefixEnd)
+		}
+		endOp = types.Less
 	}
 
-	s.Scan()
-	return f
+	start = &index.Filter{
+		Operator:   types.GreaterOrEqual,
+		Conditions: append(slices.Clone(eqs), index.FilterCondition{Left: last.Left, Right: low}),
+	}
+	if high != nil {
+		end = &index.Filter{
+			Operator:   endOp,
+			Conditions: append(slices.Clone(eqs), index.FilterCondition{Left: last.Left, Right: high}),
+		}
+	} else if len(eqs) != 0 {
+		end = &index.Filter{
+			Operator:   types.LessOrEqual,
+			Conditions: slices.Clone(eqs),
+		}
+	}
+	return start, end
 }
 
-func parseWhereFilter(s *scanner.Scanner, firstScanned bool, ps query.Parser) (
+func literal(val string) *projection.Projection {
+	p := &projection.Projection{
+		Alias:   fmt.Sprint(rand.Int63()),
+		Type:    projection.LITERAL,
+		Literal: types.Type(types.Meta(types.TYPE_STRING)).Set(val),
+	}
+	p.Name = p.Alias
+	return p
+}
+
+// parseCondition parses boolean expression:
+//
+//	condition = and {OR and}
+//	and       = not {AND not}
+//	not       = NOT not | "(" condition ")" | filter
+func parseCondition(s *lexer.Lexer, ps query.Parser) *statement.WhereStatement {
+	ws := parseAnd(s, ps)
+	if !s.Is("OR") {
+		return ws
+	}
+
+	or := &statement.WhereStatement{Or: []*statement.WhereStatement{ws}}
+	for s.Is("OR") {
+		s.Scan()
+		or.Or = append(or.Or, parseAnd(s, ps))
+	}
+	return or
+}
+
+func parseAnd(s *lexer.Lexer, ps query.Parser) *statement.WhereStatement {
+	ws := parseNot(s, ps)
+	if !s.Is("AND") {
+		return ws
+	}
+
+	and := &statement.WhereStatement{And: []*statement.WhereStatement{ws}}
+	for s.Is("AND") {
+		s.Scan()
+		and.And = append(and.And, parseNot(s, ps))
+	}
+	return and
+}
+
+func parseNot(s *lexer.Lexer, ps query.Parser) *statement.WhereStatement {
+	if s.Is("NOT") {
+		s.Scan()
+		ws := parseNot(s, ps)
+		ws.Not = !ws.Not
+		return ws
+	} else if s.Is("(") && isGroup(s) {
+		s.Scan()
+		ws := parseCondition(s, ps)
+		s.Expect(")")
+		return ws
+	}
+
+	left, op, right := parseWhereFilter(s, ps)
+	return statement.WhereS(&statement.Statement{
+		Left:  left,
+		Op:    op,
+		Right: right,
+	})
+}
+
+// isGroup checks if parenthesis at cursor opens group of conditions
+// rather than expression like "(a + 1) * 2 > b".
+func isGroup(s *lexer.Lexer) bool {
+	pos := s.Pos()
+	defer s.Reset(pos)
+
+	if s.Scan(); s.Is("SELECT") {
+		return false
+	}
+
+	for depth := 1; depth > 0; s.Scan() {
+		switch {
+			case s.Token().Kind == lexer.EOF: return true
+			case s.Is("("):                   depth++
+			case s.Is(")"):                   depth--
+		}
+	}
+
+	_, isArithmetic := arithmetic[s.TokenText()]
+	return !s.Is("=", "<", ">", "<=", ">=", "!=", "<>", "IS", "IN", "NOT", "BETWEEN", "LIKE", "ILIKE") &&
+		!(isArithmetic && s.Token().Kind == lexer.Op)
+}
+
+func parseWhereFilter(s *lexer.Lexer, ps query.Parser) (
 	left *projection.Projection,
 	op types.Operator,
 	right *projection.Projection,
 ) {
-	if !firstScanned {
-		s.Scan()
-	}
-	left = parseProjection(s, ps)
+	left, _ = parseExpr(s, ps, 0)
 
-	op = types.Operator(s.TokenText())
-	_, isOP := kwords.IndexOperators[op]
-	if !isOP {
-		panic(errors.ErrSyntax)
+	not := ""
+	if s.Is("NOT") {
+		not = "NOT "
+		if s.Scan(); !s.Is("IN", "BETWEEN", "LIKE", "ILIKE") {
+			s.Unexpected("IN, BETWEEN, LIKE or ILIKE")
+		}
 	}
 
-	if s.Peek() == '=' {
-		op += "="
-		s.Next()
+	switch {
+		case s.Is("IS"):
+			op = types.IsNull
+			if s.Scan(); s.Is("NOT") {
+				op = types.IsNotNull
+				s.Scan()
+			}
+
+			if !s.Is("NULL") {
+				s.Unexpected("NULL")
+			}
+			right, _ = parseOperand(s, ps)
+			return left, op, right
+
+		case s.Is("IN"):
+			s.Scan()
+			return left, types.Operator(not + "IN"), parseList(s, ps)
+
+		case s.Is("BETWEEN"):
+			s.Scan()
+			return left, types.Operator(not + "BETWEEN"), parseRange(s, ps)
+
+		case s.Is("LIKE", "ILIKE"):
+			op = types.Operator(not + s.TokenText())
+
+		case s.Is("=", "<", ">", "<=", ">=", "!="):
+			op = types.Operator(s.TokenText())
+
+		case s.Is("<>"):
+			op = types.NotEqual
+
+		default:
+			s.Unexpected("comparison operator")
 	}
 
 	s.Scan()
-	right = parseProjection(s, ps)
+	right, _ = parseExpr(s, ps, 0)
 
 	return left, op, right
 }
 
-func (qs *QuerySelect) parseWhere(s *scanner.Scanner, ps query.Parser) {
-	word := s.TokenText()
-	if word != "WHERE" {
-		return
+// parseRange parses bounds of BETWEEN operator.
+func parseRange(s *lexer.Lexer, ps query.Parser) *projection.Projection {
+	p := &projection.Projection{
+		Alias: fmt.Sprint(rand.Int63()),
+		Type:  projection.LIST,
+	}
+	p.Name = p.Alias
+
+	low, _ := parseExpr(s, ps, 0)
+	s.Expect("AND")
+	high, _ := parseExpr(s, ps, 0)
+
+	p.Arguments = []*projection.Projection{low, high}
+	return p
+}
+
+// parseList parses value list or subquery of IN operator.
+func parseList(s *lexer.Lexer, ps query.Parser) *projection.Projection {
+	s.Expect("(")
+
+	p := &projection.Projection{
+		Alias: fmt.Sprint(rand.Int63()),
+		Type:  projection.LIST,
 	}
+	p.Name = p.Alias
+
+	if s.Is("SELECT") {
+		sq, err := ps.ParseQuery(s)
+		if err != nil {
+			panic(err)
+		}
 
+		p.Type = projection.SUBQUERY
+		p.Subquery = sq
+	} else {
+		for {
+			arg, _ := parseExpr(s, ps, 0)
+			p.Arguments = append(p.Arguments, arg)
+			if !s.Is(",") {
+				break
+			}
+			s.Scan()
+		}
+	}
+
+	s.Expect(")")
+	return p
+}
+
+func (qs *QuerySelect) parseWhere(s *lexer.Lexer, ps query.Parser) {
 	qs.Where = parseWhere(s, ps)
 }
 
-func parseWhere(s *scanner.Scanner, ps query.Parser) *statement.WhereStatement {
-	var logOp string
-	var tok rune
-	sttmnts := []*statement.WhereStatement{}
+func parseWhere(s *lexer.Lexer, ps query.Parser) *statement.WhereStatement {
+	if !s.Is("WHERE") {
+		return nil
+	}
 
-	tok = s.Scan()
+	s.Scan()
+	return parseCondition(s, ps)
+}
+
+func (qs *QuerySelect) parseGroupBy(s *lexer.Lexer, ps query.Parser) {
+	if !s.Is("GROUP") {
+		return
+	}
+
+	s.Scan()
+	s.Expect("BY")
+
+	qs.GroupBy = []*projection.Projection{}
 	for {
-		word := s.TokenText()
-		_, isKW := kwords.KeyWords[word]
-		if tok == scanner.EOF {
-			panic(errors.ErrSyntax)
-		} else if word == "(" {
-			sttmnts = append(sttmnts, parseWhere(s, ps))
-			tok = s.Scan()
-		} else if word == ")" || word == ";" || word == "GROUP" || isKW {
+		tok := s.Token()
+		p, _ := parseExpr(s, ps, 0)
+		if !isRowExpr(p) {
+			s.ErrorAt(tok, "group item can't contain aggregators or subqueries")
+		}
+		qs.GroupBy = append(qs.GroupBy, p)
+		if !s.Is(",") {
 			break
-		} else if _, ok := kwords.LogicalOperators[word]; ok {
-			logOp = word
-			tok = s.Scan()
-		} else {
-			left, op, right := parseWhereFilter(s, true, ps)
-			sttmnts = append(sttmnts, &statement.WhereStatement{
-				Statement: &statement.Statement{
-					Left:  left,
-					Op:    op,
-					Right: right,
-				},
-			})
 		}
+		s.Scan()
+	}
+}
+
+func (qs *QuerySelect) parseHaving(s *lexer.Lexer, ps query.Parser) {
+	if !s.Is("HAVING") {
+		return
 	}
 
-	if logOp == "AND" {
-		return &statement.WhereStatement{
-			And: sttmnts,
+	s.Scan()
+	qs.Having = parseCondition(s, ps)
+}
+
+func (qs *QuerySelect) parseOrderBy(s *lexer.Lexer, ps query.Parser) {
+	if !s.Is("ORDER") {
+		return
+	}
+
+	s.Scan()
+	s.Expect("BY")
+
+	qs.OrderBy = []*order.Item{}
+	for {
+		item := &order.Item{}
+		item.Projection, _ = parseExpr(s, ps, 0)
+
+		switch {
+			case s.Is("DESC"): item.Desc = true; s.Scan()
+			case s.Is("ASC"):  s.Scan()
 		}
-	} else if logOp == "OR" {
-		return &statement.WhereStatement{
-			Or: sttmnts,
+
+		qs.OrderBy = append(qs.OrderBy, item)
+		if !s.Is(",") {
+			break
 		}
+		s.Scan()
 	}
-	return sttmnts[0]
 }
 
-func (qs *QuerySelect) parseGroupBy(s *scanner.Scanner) {
-	word := s.TokenText()
-	if word != "GROUP" {
+func (qs *QuerySelect) parseLimit(s *lexer.Lexer) {
+	if !s.Is("LIMIT") {
 		return
 	}
 
 	s.Scan()
-	word = s.TokenText()
-	if word != "BY" {
-		panic(errors.ErrSyntax)
+	qs.Limit = &Limit{Count: parseUint(s)}
+	if s.Is("OFFSET") {
+		s.Scan()
+		qs.Limit.Offset = parseUint(s)
 	}
+}
 
-	qs.GroupBy = map[string]struct{}{}
-	for s.Scan(); s.TokenText() != ","; s.Scan() {
-		qs.GroupBy[s.TokenText()] = struct{}{}
+func parseUint(s *lexer.Lexer) int {
+	if s.Token().Kind != lexer.Int {
+		s.Unexpected("number")
+	}
 
-		s.Scan()
-		word = s.TokenText()
-		if word == "," {
-			continue
-		} else if word == ";" {
-			break
-		}
+	n, err := strconv.Atoi(s.TokenText())
+	if err != nil {
+		s.Errorf("invalid number %v", s.Token())
 	}
+
+	s.Scan()
+	return n
 }
@@ -0,0 +1,35 @@
+package dml
+
+import (
+	"testing"
+
+	"go-dbms/services/parser/lexer"
+	"go-dbms/services/parser/query/dml/function"
+	"go-dbms/services/parser/query/dml/projection"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseExpr(t *testing.T) {
+	s, err := lexer.New([]byte("a + b * -2 - (c - 1) % 3 AS x FROM t"))
+	require.NoError(t, err)
+
+	p := parseProjection(s, nil)
+	require.Equal(t, "x", p.Alias)
+	require.Equal(t, "FROM", s.TokenText())
+
+	// ((a + (b * -2)) - ((c - 1) % 3))
+	require.Equal(t, string(function.SUB), p.Name)
+	add, res := p.Arguments[0], p.Arguments[1]
+
+	require.Equal(t, string(function.ADD), add.Name)
+	require.Equal(t, "a", add.Arguments[0].Name)
+	mul := add.Arguments[1]
+	require.Equal(t, "b*-2", mul.Alias)
+	require.Equal(t, projection.LITERAL, mul.Arguments[1].Type)
+	require.Equal(t, int64(-2), mul.Arguments[1].Literal.Value())
+
+	require.Equal(t, string(function.RES), res.Name)
+	require.Equal(t, "(c-1)%3", res.Alias)
+	require.Equal(t, string(function.SUB), res.Arguments[0].Name)
+}
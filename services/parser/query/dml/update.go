@@ -1,139 +1,127 @@
 package dml
 
 import (
-	"encoding/json"
-	"text/scanner"
+	"slices"
 
 	"go-dbms/pkg/statement"
-	"go-dbms/pkg/types"
-	"go-dbms/services/parser/errors"
-	"go-dbms/services/parser/kwords"
+	"go-dbms/services/parser/lexer"
 	"go-dbms/services/parser/query"
+	"go-dbms/services/parser/query/dml/projection"
 	"go-dbms/util/helpers"
 )
 
 /*
-UPDATE <tableName>
+UPDATE [<dbName>.]<tableName>
+[USE_INDEX <indexName>]
 SET
-	<columnName> = <value>,
+	<columnName> = <expression>,
 	...
-	<columnName> = <value>
-[WHERE_INDEX <indexName> <condition> [AND <condition>]]
-[WHERE <...condition>];
+	<columnName> = <expression>
+[WHERE_INDEX (<condition> [AND <condition>]) [AND (<condition> [AND <condition>])]]
+[WHERE <...condition>]
+[RETURNING <...projection>];
 */
 type QueryUpdate struct {
 	query.Query
 	DB         string
 	Table      string
 	UseIndex   string
-	Values     types.DataRow
+	Values     map[string]*projection.Projection // expressions, evaluated on execution for each row
 	Where      *statement.WhereStatement
 	WhereIndex *WhereIndex
+	Returning  *projection.Projections // projections of updated rows, primary key if nil
 }
 
-func (qu *QueryUpdate) Parse(s *scanner.Scanner, ps query.Parser) (err error) {
+func (qu *QueryUpdate) Parse(s *lexer.Lexer, ps query.Parser) (err error) {
 	defer helpers.RecoverOnError(&err)()
 
 	qu.Type = query.UPDATE
 
 	qu.parseFrom(s)
 	qu.parseUseIndex(s)
-	qu.parseValues(s)
+	qu.parseValues(s, ps)
 	qu.parseWhereIndex(s, ps)
 	qu.parseWhere(s, ps)
+	qu.parseReturning(s, ps)
 
 	return nil
 }
 
-func (qu *QueryUpdate) parseFrom(s *scanner.Scanner) {
-	tok := s.Scan()
-	word := s.TokenText()
-	_, isKW := kwords.KeyWords[word]
-	if tok == scanner.EOF {
-		panic(errors.ErrSyntax)
-	} else if isKW {
-		panic(errors.ErrNoFrom)
-	}
-
-	qu.Table = word
-
-	tok = s.Scan()
-	word = s.TokenText()
-	_, idKW := kwords.KeyWords[word]
-	if tok != scanner.EOF && !idKW {
-		panic(errors.ErrSyntax)
-	}
+func (qu *QueryUpdate) parseFrom(s *lexer.Lexer) {
+	s.Expect("UPDATE")
+	qu.DB, qu.Table = s.TableName()
 }
 
-func (qs *QueryUpdate) parseUseIndex(s *scanner.Scanner) {
-	word := s.TokenText()
-	if word != "USE_INDEX" {
-		return
-	}
-
-	tok := s.Scan()
-	if tok == scanner.EOF {
-		panic(errors.ErrSyntax)
-	}
-
-	qs.UseIndex = s.TokenText()
-	s.Scan()
+func (qu *QueryUpdate) parseUseIndex(s *lexer.Lexer) {
+	qu.UseIndex = parseUseIndex(s)
 }
 
-func (qu *QueryUpdate) parseValues(s *scanner.Scanner) {
-	qu.Values = types.DataRow{}
-
-	if s.TokenText() != "SET" {
-		panic(errors.ErrSyntax)
-	}
-
-	for tok := s.Scan(); tok != scanner.EOF; tok = s.Scan() {
-		col := s.TokenText()
-		_, isKW := kwords.KeyWords[col]
-		if tok == scanner.EOF || isKW {
-			panic(errors.ErrSyntax)
-		}
-
-		tok = s.Scan()
-		if tok == scanner.EOF || s.TokenText() != "=" {
-			panic(errors.ErrSyntax)
-		}
+func (qu *QueryUpdate) parseValues(s *lexer.Lexer, ps query.Parser) {
+	s.Expect("SET")
+	qu.Values = parseSet(s, ps)
+}
 
-		tok = s.Scan()
-		val := s.TokenText()
-		_, isKW = kwords.KeyWords[val]
-		if tok == scanner.EOF || isKW {
-			panic(errors.ErrSyntax)
+// parseSet parses comma separated assignments of expressions to columns,
+// expressions may reference values of current row.
+func parseSet(s *lexer.Lexer, ps query.Parser) map[string]*projection.Projection {
+	values := map[string]*projection.Projection{}
+	for {
+		col := s.Ident()
+		s.Expect("=")
+
+		tok := s.Token()
+		p, _ := parseExpr(s, ps, 0)
+		if !isRowExpr(p) {
+			s.ErrorAt(tok, "value of column '%s' can't contain aggregators or subqueries", col)
 		}
+		values[col] = p
 
-		var valInt interface{}
-		if err := json.Unmarshal([]byte(val), &valInt); err != nil {
-			panic(err)
+		if !s.Is(",") {
+			return values
 		}
-		qu.Values[col] = types.ParseJSONValue(valInt)
-
 		s.Scan()
-		word := s.TokenText()
-		_, isKW = kwords.KeyWords[word]
-		if word == ";" || isKW {
-			break
-		} else if word == "," {
-			continue
-		} else {
-			panic(errors.ErrSyntax)
-		}
 	}
 }
 
-func (qs *QueryUpdate) parseWhereIndex(s *scanner.Scanner, ps query.Parser) {
-	qs.WhereIndex = parseWhereIndex(s, ps)
+// ParseConstant parses expression, which doesn't depend on row values.
+func ParseConstant(s *lexer.Lexer, ps query.Parser) *projection.Projection {
+	tok := s.Token()
+	p, _ := parseExpr(s, ps, 0)
+	if !isConstant(p) {
+		s.ErrorAt(tok, "expected constant expression")
+	}
+	return p
 }
 
-func (qu *QueryUpdate) parseWhere(s *scanner.Scanner, ps query.Parser) {
-	word := s.TokenText()
-	if word != "WHERE" {
-		return
+// isConstant reports whether expression doesn't depend on row values.
+func isConstant(p *projection.Projection) bool {
+	switch p.Type {
+		case projection.LITERAL:  return true
+		case projection.FUNCTION: return !slices.ContainsFunc(p.Arguments, func(arg *projection.Projection) bool {
+			return !isConstant(arg)
+		})
 	}
+	return false
+}
 
+// isRowExpr reports whether expression can be evaluated on single row.
+func isRowExpr(p *projection.Projection) bool {
+	switch p.Type {
+		case projection.AGGREGATOR, projection.SUBQUERY: return false
+	}
+	return !slices.ContainsFunc(p.Operands(), func(arg *projection.Projection) bool {
+		return !isRowExpr(arg)
+	})
+}
+
+func (qu *QueryUpdate) parseWhereIndex(s *lexer.Lexer, ps query.Parser) {
+	qu.WhereIndex = parseWhereIndex(s, ps)
+}
+
+func (qu *QueryUpdate) parseWhere(s *lexer.Lexer, ps query.Parser) {
 	qu.Where = parseWhere(s, ps)
 }
+
+func (qu *QueryUpdate) parseReturning(s *lexer.Lexer, ps query.Parser) {
+	qu.Returning = parseReturning(s, ps)
+}
@@ -0,0 +1,30 @@
+package dml
+
+import (
+	"testing"
+
+	"go-dbms/services/parser/lexer"
+	"go-dbms/services/parser/query/dml/function"
+	"go-dbms/services/parser/query/dml/projection"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSet(t *testing.T) {
+	s, err := lexer.New([]byte("balance = balance - 10, note = NOW() WHERE"))
+	require.NoError(t, err)
+
+	values := parseSet(s, nil)
+	require.Equal(t, "WHERE", s.TokenText())
+	require.Len(t, values, 2)
+
+	balance := values["balance"]
+	require.Equal(t, string(function.SUB), balance.Name)
+	require.Equal(t, projection.IDENTIFIER, balance.Arguments[0].Type)
+	require.Equal(t, "balance", balance.Arguments[0].Name)
+	require.Equal(t, string(function.NOW), values["note"].Name)
+
+	s, err = lexer.New([]byte("balance = SUM(balance)"))
+	require.NoError(t, err)
+	require.Panics(t, func() { parseSet(s, nil) })
+}
@@ -0,0 +1,39 @@
+package query
+
+import (
+	"fmt"
+
+	"go-dbms/pkg/types"
+	"go-dbms/services/parser/lexer"
+)
+
+// Prepared is query with parameter placeholders. It's parsed once and
+// executed many times, values of parameters are bound before every execution.
+type Prepared struct {
+	Querier
+	params [][]lexer.Param
+}
+
+func NewPrepared(q Querier, params [][]lexer.Param) *Prepared {
+	return &Prepared{Querier: q, params: params}
+}
+
+// NumParams returns count of query parameters.
+func (p *Prepared) NumParams() int {
+	return len(p.params)
+}
+
+// Bind sets values of parameters to their placeholders, vals[i]
+// is value of parameter i+1. nil value binds NULL.
+func (p *Prepared) Bind(vals []types.DataType) error {
+	if len(vals) != len(p.params) {
+		return fmt.Errorf("expected %d parameters, got %d", len(p.params), len(vals))
+	}
+
+	for i, val := range vals {
+		for _, param := range p.params[i] {
+			param.Bind(val)
+		}
+	}
+	return nil
+}
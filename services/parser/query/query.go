@@ -1,11 +1,12 @@
 package query
 
-import "text/scanner"
+import "go-dbms/services/parser/lexer"
 
 type QueryType string
 
 const (
 	INSERT   QueryType = "INSERT"
+	REPLACE  QueryType = "REPLACE"
 	SELECT   QueryType = "SELECT"
 	UPDATE   QueryType = "UPDATE"
 	DELETE   QueryType = "DELETE"
@@ -15,10 +16,18 @@ const (
 	TRUNCATE QueryType = "TRUNCATE"
 	RENAME   QueryType = "RENAME"
 	PREPARE  QueryType = "PREPARE"
+	COMPOUND QueryType = "COMPOUND"
+	EXPLAIN  QueryType = "EXPLAIN"
+	USE      QueryType = "USE"
+	SHOW     QueryType = "SHOW"
+	DESCRIBE QueryType = "DESCRIBE"
 )
 
 type Parser interface {
-	ParseQuery(s *scanner.Scanner) (Querier, error)
+	Parse(src []byte) (Querier, error)
+	Prepare(src []byte) (*Prepared, error)
+	ParseQuery(s *lexer.Lexer) (Querier, error)
+	Use(db string) Parser
 }
 
 type Querier interface {
@@ -27,7 +36,7 @@ type Querier interface {
 
 type QueryParser interface {
 	Querier
-	Parse(s *scanner.Scanner, ps Parser) error
+	Parse(s *lexer.Lexer, ps Parser) error
 }
 
 type Query struct {
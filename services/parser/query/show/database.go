@@ -0,0 +1,21 @@
+package show
+
+import (
+	"go-dbms/services/parser/lexer"
+	"go-dbms/services/parser/query"
+	"go-dbms/util/helpers"
+)
+
+/*
+SHOW DATABASES;
+*/
+type QueryShowDatabases struct {
+	*QueryShow
+}
+
+func (qs *QueryShowDatabases) Parse(s *lexer.Lexer, ps query.Parser) (err error) {
+	defer helpers.RecoverOnError(&err)()
+
+	s.Scan()
+	return nil
+}
@@ -0,0 +1,62 @@
+package show
+
+import (
+	"strings"
+
+	"go-dbms/services/parser/lexer"
+	"go-dbms/services/parser/query"
+	"go-dbms/util/helpers"
+)
+
+type QueryShowTarget string
+
+const (
+	DATABASES    QueryShowTarget = "DATABASES"
+	TABLES       QueryShowTarget = "TABLES"
+	COLUMNS      QueryShowTarget = "COLUMNS"
+	INDEXES      QueryShowTarget = "INDEXES"
+	CREATE_TABLE QueryShowTarget = "CREATE TABLE"
+)
+
+type Shower interface {
+	query.QueryParser
+	GetTarget() QueryShowTarget
+}
+
+type QueryShow struct {
+	*query.Query
+	Target QueryShowTarget `json:"target"`
+}
+
+func (qs *QueryShow) GetTarget() QueryShowTarget {
+	return qs.Target
+}
+
+// Parse parses SHOW query or DESCRIBE, which is the same as SHOW COLUMNS.
+// Targets aren't key words, so they can be names of tables.
+func Parse(s *lexer.Lexer) (q Shower, err error) {
+	defer helpers.RecoverOnError(&err)()
+
+	qs := &QueryShow{Query: &query.Query{Type: query.SHOW}}
+	if s.Is(string(query.DESCRIBE)) {
+		qs.Target = COLUMNS
+		q = &QueryShowColumns{QueryShow: qs}
+		return q, q.Parse(s, nil)
+	}
+
+	s.Expect(string(query.SHOW))
+	qs.Target = QueryShowTarget(strings.ToUpper(s.TokenText()))
+	switch qs.Target {
+		case DATABASES: q = &QueryShowDatabases{QueryShow: qs}
+		case TABLES:    q = &QueryShowTables{QueryShow: qs}
+		case COLUMNS:   q = &QueryShowColumns{QueryShow: qs}
+		case INDEXES:   q = &QueryShowIndexes{QueryShow: qs}
+		case "CREATE":
+			qs.Target = CREATE_TABLE
+			q = &QueryShowCreateTable{QueryShow: qs}
+		default:
+			s.Unexpected("DATABASES, TABLES, COLUMNS, INDEXES or CREATE")
+	}
+
+	return q, q.Parse(s, nil)
+}
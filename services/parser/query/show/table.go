@@ -0,0 +1,85 @@
+package show
+
+import (
+	"go-dbms/services/parser/lexer"
+	"go-dbms/services/parser/query"
+	"go-dbms/util/helpers"
+)
+
+/*
+SHOW TABLES [FROM <dbName>];
+*/
+type QueryShowTables struct {
+	*QueryShow
+	DB string `json:"db"`
+}
+
+func (qs *QueryShowTables) Parse(s *lexer.Lexer, ps query.Parser) (err error) {
+	defer helpers.RecoverOnError(&err)()
+
+	s.Scan()
+	if s.Is("FROM") {
+		s.Scan()
+		qs.DB = s.Ident()
+	} else {
+		qs.DB = s.Database()
+	}
+	return nil
+}
+
+/*
+SHOW CREATE TABLE [<dbName>.]<tableName>;
+*/
+type QueryShowCreateTable struct {
+	*QueryShow
+	DB    string `json:"db"`
+	Table string `json:"table"`
+}
+
+func (qs *QueryShowCreateTable) Parse(s *lexer.Lexer, ps query.Parser) (err error) {
+	defer helpers.RecoverOnError(&err)()
+
+	s.Expect("CREATE")
+	s.Expect("TABLE")
+	qs.DB, qs.Table = s.TableName()
+	return nil
+}
+
+/*
+{DESCRIBE | SHOW COLUMNS FROM} [<dbName>.]<tableName>;
+*/
+type QueryShowColumns struct {
+	*QueryShow
+	DB    string `json:"db"`
+	Table string `json:"table"`
+}
+
+func (qs *QueryShowColumns) Parse(s *lexer.Lexer, ps query.Parser) (err error) {
+	defer helpers.RecoverOnError(&err)()
+
+	describe := s.Is(string(query.DESCRIBE))
+	s.Scan()
+	if !describe {
+		s.Expect("FROM")
+	}
+	qs.DB, qs.Table = s.TableName()
+	return nil
+}
+
+/*
+SHOW INDEXES FROM [<dbName>.]<tableName>;
+*/
+type QueryShowIndexes struct {
+	*QueryShow
+	DB    string `json:"db"`
+	Table string `json:"table"`
+}
+
+func (qs *QueryShowIndexes) Parse(s *lexer.Lexer, ps query.Parser) (err error) {
+	defer helpers.RecoverOnError(&err)()
+
+	s.Scan()
+	s.Expect("FROM")
+	qs.DB, qs.Table = s.TableName()
+	return nil
+}
@@ -0,0 +1,24 @@
+package query
+
+import (
+	"go-dbms/services/parser/lexer"
+	"go-dbms/util/helpers"
+)
+
+/*
+USE <databaseName>;
+*/
+type QueryUse struct {
+	Query
+	DB string
+}
+
+func (qu *QueryUse) Parse(s *lexer.Lexer, ps Parser) (err error) {
+	defer helpers.RecoverOnError(&err)()
+
+	qu.Type = USE
+
+	s.Expect("USE")
+	qu.DB = s.Ident()
+	return nil
+}